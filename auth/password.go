@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// 그누보드 pbkdf2.compat.php 기본값
+const (
+	pbkdf2Iterations = 12000
+	pbkdf2SaltBytes  = 24
+	pbkdf2HashBytes  = 24
+)
+
+var pbkdf2Hashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// CheckPassword reports whether password matches a g5_member.mb_password
+// (or wr_password) value. Every format Gnuboard has shipped is accepted:
+//
+//	sha256:12000:salt:hash  create_hash() (5.1 이후 기본)
+//	*94BDCEBE19083CE2...    MySQL PASSWORD()
+//	565491d704013245        MySQL OLD_PASSWORD()
+//	$2y$10$...              password_hash() (bcrypt)
+func CheckPassword(password, hashed string) bool {
+	switch {
+	case hashed == "":
+		return false
+	case strings.HasPrefix(hashed, "$2"):
+		return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil
+	case strings.Count(hashed, ":") == 3:
+		return checkPBKDF2(password, hashed)
+	case len(hashed) == 41 && hashed[0] == '*':
+		return constantEqual(mysqlPassword(password), strings.ToUpper(hashed))
+	case len(hashed) == 16:
+		return constantEqual(mysqlOldPassword(password), strings.ToLower(hashed))
+	}
+	return false
+}
+
+// HashPassword returns a create_hash() compatible PBKDF2 hash, which the
+// PHP side can verify as well
+func HashPassword(password string) (string, error) {
+	raw := make([]byte, pbkdf2SaltBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	// PHP 와 같이 base64 문자열 자체를 salt 로 사용
+	salt := base64.StdEncoding.EncodeToString(raw)
+	key := pbkdf2.Key([]byte(password), []byte(salt), pbkdf2Iterations, pbkdf2HashBytes, sha256.New)
+	return fmt.Sprintf("sha256:%d:%s:%s", pbkdf2Iterations, salt, base64.StdEncoding.EncodeToString(key)), nil
+}
+
+// validate_password() 와 같은 방식
+func checkPBKDF2(password, hashed string) bool {
+	parts := strings.Split(hashed, ":")
+	newHash, ok := pbkdf2Hashes[strings.ToLower(parts[0])]
+	if !ok {
+		return false
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return false
+	}
+	want, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+	got := pbkdf2.Key([]byte(password), []byte(parts[2]), iterations, len(want), newHash)
+	return hmac.Equal(got, want)
+}
+
+// mysqlPassword is MySQL 4.1+ PASSWORD(): "*" + upper(hex(sha1(sha1(pw))))
+func mysqlPassword(password string) string {
+	first := sha1.Sum([]byte(password))
+	second := sha1.Sum(first[:])
+	return "*" + strings.ToUpper(hex.EncodeToString(second[:]))
+}
+
+// mysqlOldPassword is the pre-4.1 OLD_PASSWORD() hash
+func mysqlOldPassword(password string) string {
+	var nr, add, nr2 uint32 = 1345345333, 7, 0x12345671
+	for i := 0; i < len(password); i++ {
+		ch := password[i]
+		// 공백과 탭은 무시
+		if ch == ' ' || ch == '\t' {
+			continue
+		}
+		tmp := uint32(ch)
+		nr ^= (((nr & 63) + add) * tmp) + (nr << 8)
+		nr2 += (nr2 << 8) ^ nr
+		add += tmp
+	}
+	return fmt.Sprintf("%08x%08x", nr&0x7fffffff, nr2&0x7fffffff)
+}
+
+func constantEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
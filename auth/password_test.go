@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCheckPassword(t *testing.T) {
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// PHP password_hash() 는 $2y$ 접두어
+	phpBcrypt := strings.Replace(string(bcryptHash), "$2a$", "$2y$", 1)
+
+	hashes := map[string]string{
+		"pbkdf2":       "sha256:12000:c2FsdHNhbHRzYWx0c2FsdHNhbHRzYWx0c2FsdA==:Bi+e1RLK5TDCcaXGisnsgE/+Eapzl6oX",
+		"password":     "*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19",
+		"old_password": "5d2e19393cc5ef67",
+		"bcrypt":       string(bcryptHash),
+		"bcrypt $2y$":  phpBcrypt,
+	}
+	for name, hash := range hashes {
+		if !CheckPassword("password", hash) {
+			t.Errorf("%s: correct password rejected", name)
+		}
+		if CheckPassword("Password", hash) {
+			t.Errorf("%s: wrong password accepted", name)
+		}
+	}
+
+	// OLD_PASSWORD() 는 공백을 무시
+	if !CheckPassword("pass word", hashes["old_password"]) {
+		t.Error("old_password: spaces should be ignored")
+	}
+
+	for _, hash := range []string{"", "password", "md5:1:a:b", "sha256:x:salt:aGFzaA==", "sha256:12000:salt:!!"} {
+		if CheckPassword("password", hash) {
+			t.Errorf("%q: accepted", hash)
+		}
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("비밀번호")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "sha256:12000:") || strings.Count(hash, ":") != 3 {
+		t.Errorf("hash = %q", hash)
+	}
+	if !CheckPassword("비밀번호", hash) || CheckPassword("비밀번호2", hash) {
+		t.Error("HashPassword result does not verify")
+	}
+	if other, _ := HashPassword("비밀번호"); other == hash {
+		t.Error("salt is not random")
+	}
+}
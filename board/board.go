@@ -0,0 +1,123 @@
+package board
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// 그누보드 bo_table 규칙 (영문, 숫자, 언더바 20자 이내)
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,20}$`)
+
+// Board is a single row of Gnuboard's g5_board table
+type Board struct {
+	Table          string `json:"bo_table"`
+	Subject        string `json:"bo_subject"`
+	ListLevel      int    `json:"bo_list_level"`
+	ReadLevel      int    `json:"bo_read_level"`
+	CommentLevel   int    `json:"bo_comment_level"`
+	WriteLevel     int    `json:"bo_write_level"`
+	ReplyLevel     int    `json:"bo_reply_level"`
+	DownloadLevel  int    `json:"bo_download_level"`
+	UploadLevel    int    `json:"bo_upload_level"`
+	UseSecret      int    `json:"bo_use_secret"` // 0 사용 안 함, 1 체크박스, 2 무조건
+	Admin          string `json:"bo_admin"`      // 게시판 관리자 mb_id
+	GroupAdmin     string `json:"gr_admin"`      // 그룹 관리자 mb_id (g5_group)
+	UseGood        bool   `json:"bo_use_good"`
+	UseNogood      bool   `json:"bo_use_nogood"`
+	ReadPoint      int    `json:"bo_read_point"`
+	WritePoint     int    `json:"bo_write_point"`
+	CommentPoint   int    `json:"bo_comment_point"`
+	DownloadPoint  int    `json:"bo_download_point"`
+	CountModify    int    `json:"bo_count_modify"` // 다른 회원의 댓글이 이만큼 달리면 수정 불가
+	CountDelete    int    `json:"bo_count_delete"` // 다른 회원의 댓글이 이만큼 달리면 삭제 불가
+	PageRows       int    `json:"bo_page_rows"`
+	MobilePageRows int    `json:"bo_mobile_page_rows"`
+	UploadSize     int64  `json:"bo_upload_size"`  // 파일 하나의 최대 바이트
+	UploadCount    int    `json:"bo_upload_count"` // 글 하나의 최대 첨부파일 수 (0 이면 업로드 안 함)
+	Skin           string `json:"bo_skin"`
+	GalleryWidth   int    `json:"bo_gallery_width"` // 갤러리 목록 썸네일 크기
+	GalleryHeight  int    `json:"bo_gallery_height"`
+	// g5_config 의 포인트 설정 (모든 게시판이 같음)
+	UsePoint  bool `json:"cf_use_point"`
+	PointTerm int  `json:"cf_point_term"` // 포인트 유효기간 (일), 0 이면 만료 없음
+}
+
+// WriteTable returns the g5_write_* table name that holds the board's posts
+func (b Board) WriteTable() string {
+	return "g5_write_" + b.Table
+}
+
+// IsGallery reports whether the board uses a gallery skin (gallery,
+// theme/gallery), whose list shows thumbnails
+func (b Board) IsGallery() bool {
+	return path.Base(b.Skin) == "gallery"
+}
+
+// ValidTableName reports whether name is safe to use as a bo_table
+func ValidTableName(name string) bool {
+	return tableNamePattern.MatchString(name)
+}
+
+// ParseAllowed splits a comma separated ALLOWED_BOARDS value
+func ParseAllowed(value string) []string {
+	var tables []string
+	for _, t := range strings.Split(value, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// Source loads board definitions
+type Source interface {
+	LoadBoards(ctx context.Context) ([]Board, error)
+}
+
+// MySQLSource loads boards from the g5_board table
+type MySQLSource struct {
+	db *sql.DB
+}
+
+// NewMySQLSource creates a Source backed by g5_board
+func NewMySQLSource(db *sql.DB) *MySQLSource {
+	return &MySQLSource{db: db}
+}
+
+// LoadBoards implements Source
+func (s *MySQLSource) LoadBoards(ctx context.Context) ([]Board, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.bo_table, b.bo_subject, b.bo_list_level, b.bo_read_level, b.bo_comment_level,
+			b.bo_write_level, b.bo_reply_level, b.bo_download_level, b.bo_upload_level, b.bo_use_secret, b.bo_use_good, b.bo_use_nogood, b.bo_admin, COALESCE(g.gr_admin, ''),
+			b.bo_read_point, b.bo_write_point, b.bo_comment_point, b.bo_download_point, b.bo_count_modify, b.bo_count_delete, b.bo_page_rows, b.bo_mobile_page_rows,
+			b.bo_upload_size, b.bo_upload_count, b.bo_skin, b.bo_gallery_width, b.bo_gallery_height,
+			c.cf_use_point, c.cf_point_term
+		FROM g5_board b
+		LEFT JOIN g5_group g ON g.gr_id = b.gr_id
+		CROSS JOIN (SELECT cf_use_point, cf_point_term FROM g5_config LIMIT 1) c
+		ORDER BY b.gr_id, b.bo_order, b.bo_table
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var boards []Board
+	for rows.Next() {
+		var b Board
+		if err := rows.Scan(
+			&b.Table, &b.Subject, &b.ListLevel, &b.ReadLevel, &b.CommentLevel,
+			&b.WriteLevel, &b.ReplyLevel, &b.DownloadLevel, &b.UploadLevel, &b.UseSecret, &b.UseGood, &b.UseNogood, &b.Admin, &b.GroupAdmin,
+			&b.ReadPoint, &b.WritePoint, &b.CommentPoint, &b.DownloadPoint, &b.CountModify, &b.CountDelete, &b.PageRows, &b.MobilePageRows,
+			&b.UploadSize, &b.UploadCount, &b.Skin, &b.GalleryWidth, &b.GalleryHeight,
+			&b.UsePoint, &b.PointTerm,
+		); err != nil {
+			return nil, err
+		}
+		boards = append(boards, b)
+	}
+	return boards, rows.Err()
+}
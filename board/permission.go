@@ -0,0 +1,39 @@
+package board
+
+// Action is something a member does on a board, each guarded by a bo_*_level
+type Action int
+
+const (
+	ActionList     Action = iota // bo_list_level
+	ActionRead                   // bo_read_level
+	ActionComment                // bo_comment_level
+	ActionWrite                  // bo_write_level
+	ActionReply                  // bo_reply_level
+	ActionDownload               // bo_download_level
+	ActionUpload                 // bo_upload_level
+)
+
+// RequiredLevel returns the mb_level needed for action
+func (b Board) RequiredLevel(action Action) int {
+	switch action {
+	case ActionRead:
+		return b.ReadLevel
+	case ActionComment:
+		return b.CommentLevel
+	case ActionWrite:
+		return b.WriteLevel
+	case ActionReply:
+		return b.ReplyLevel
+	case ActionDownload:
+		return b.DownloadLevel
+	case ActionUpload:
+		return b.UploadLevel
+	}
+	return b.ListLevel
+}
+
+// Allows reports whether a member of level may perform action.
+// Guests have level 1, like Gnuboard.
+func (b Board) Allows(level int, action Action) bool {
+	return level >= b.RequiredLevel(action)
+}
@@ -0,0 +1,124 @@
+package board
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// 목록 페이지 기본 게시글 수 (bo_page_rows 가 비어있을 때)
+const defaultPageRows = 20
+
+// Registry holds the set of boards served by this service
+type Registry struct {
+	source  Source
+	allowed map[string]bool
+
+	mu     sync.RWMutex
+	boards map[string]Board
+	order  []string
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewRegistry creates a registry. If allowed is not empty only those
+// bo_table values are exposed even if g5_board has more boards.
+func NewRegistry(source Source, allowed []string) *Registry {
+	r := &Registry{
+		source: source,
+		boards: map[string]Board{},
+		stop:   make(chan struct{}),
+	}
+	if len(allowed) > 0 {
+		r.allowed = make(map[string]bool, len(allowed))
+		for _, t := range allowed {
+			r.allowed[t] = true
+		}
+	}
+	return r
+}
+
+// Load reads boards from the source and replaces the current set
+func (r *Registry) Load(ctx context.Context) error {
+	loaded, err := r.source.LoadBoards(ctx)
+	if err != nil {
+		return err
+	}
+
+	boards := make(map[string]Board, len(loaded))
+	order := make([]string, 0, len(loaded))
+	for _, b := range loaded {
+		// 테이블명은 SQL 에 그대로 들어가므로 형식이 맞지 않으면 제외
+		if !ValidTableName(b.Table) {
+			log.Printf("잘못된 게시판 테이블명 무시: %q", b.Table)
+			continue
+		}
+		if r.allowed != nil && !r.allowed[b.Table] {
+			continue
+		}
+		if b.PageRows <= 0 {
+			b.PageRows = defaultPageRows
+		}
+		if b.MobilePageRows <= 0 {
+			b.MobilePageRows = b.PageRows
+		}
+		boards[b.Table] = b
+		order = append(order, b.Table)
+	}
+
+	r.mu.Lock()
+	r.boards = boards
+	r.order = order
+	r.mu.Unlock()
+	return nil
+}
+
+// Start refreshes the registry every interval until Stop is called
+func (r *Registry) Start(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if err := r.Load(ctx); err != nil {
+					// 갱신 실패 시 기존 목록 유지
+					log.Printf("게시판 목록 갱신 실패: %v", err)
+				}
+				cancel()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background refresh
+func (r *Registry) Stop() {
+	r.once.Do(func() { close(r.stop) })
+}
+
+// Get returns the board for bo_table
+func (r *Registry) Get(table string) (Board, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	b, ok := r.boards[table]
+	return b, ok
+}
+
+// List returns all boards in g5_board order
+func (r *Registry) List() []Board {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]Board, 0, len(r.order))
+	for _, t := range r.order {
+		list = append(list, r.boards[t])
+	}
+	return list
+}
@@ -0,0 +1,271 @@
+// Package hits counts post views (wr_hit). A viewer is counted once per
+// post within a window, and counts are buffered and written in batches.
+package hits
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Post identifies a post by bo_table and wr_id
+type Post struct {
+	Table string
+	ID    int
+}
+
+// Store keeps the viewers already counted and the counts not yet written.
+// MemoryStore is per process; RedisStore can be shared by several servers.
+type Store interface {
+	// Seen marks key for ttl and reports whether it was already marked
+	Seen(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Add buffers n views of post
+	Add(ctx context.Context, post Post, n int) error
+	// Drain removes and returns the buffered views
+	Drain(ctx context.Context) (map[Post]int, error)
+}
+
+// Writer applies counts to the boards (wr_id → count)
+type Writer interface {
+	AddHits(ctx context.Context, table string, hits map[int]int) error
+}
+
+// Counter de-duplicates views and writes them through Writer
+type Counter struct {
+	store    Store
+	writer   Writer
+	window   time.Duration
+	buffered bool
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewCounter creates a counter. A viewer is counted once per post within
+// window. If interval is positive views are buffered in store and flushed
+// every interval until Stop; otherwise each view is written immediately.
+func NewCounter(store Store, w Writer, window, interval time.Duration) *Counter {
+	c := &Counter{
+		store:    store,
+		writer:   w,
+		window:   window,
+		buffered: interval > 0,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if !c.buffered {
+		close(c.done)
+		return c
+	}
+	go func() {
+		defer close(c.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if err := c.Flush(ctx); err != nil {
+					log.Printf("조회수 반영 실패: %v", err)
+				}
+				cancel()
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+	return c
+}
+
+// View counts a view of post by viewer (member or IP), and reports
+// whether it was counted
+func (c *Counter) View(ctx context.Context, viewer string, post Post) (bool, error) {
+	key := viewer + ":" + post.Table + ":" + strconv.Itoa(post.ID)
+	seen, err := c.store.Seen(ctx, key, c.window)
+	if err != nil || seen {
+		return false, err
+	}
+	if !c.buffered {
+		return true, c.writer.AddHits(ctx, post.Table, map[int]int{post.ID: 1})
+	}
+	return true, c.store.Add(ctx, post, 1)
+}
+
+// Flush writes the buffered views, one batch per board. Counts of a
+// board that failed are buffered again for the next flush.
+func (c *Counter) Flush(ctx context.Context) error {
+	pending, err := c.store.Drain(ctx)
+	if err != nil {
+		return err
+	}
+	tables := map[string]map[int]int{}
+	for p, n := range pending {
+		if tables[p.Table] == nil {
+			tables[p.Table] = map[int]int{}
+		}
+		tables[p.Table][p.ID] += n
+	}
+
+	var first error
+	for table, hits := range tables {
+		err := c.writer.AddHits(ctx, table, hits)
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		for id, n := range hits {
+			if err := c.store.Add(ctx, Post{Table: table, ID: id}, n); err != nil {
+				log.Printf("조회수 %s/%d 유실 (%d): %v", table, id, n, err)
+			}
+		}
+	}
+	return first
+}
+
+// Stop ends the background flush and writes what is left
+func (c *Counter) Stop(ctx context.Context) error {
+	c.once.Do(func() { close(c.stop) })
+	<-c.done
+	if !c.buffered {
+		return nil
+	}
+	return c.Flush(ctx)
+}
+
+// MemoryStore keeps viewers and counts in this process. It holds at most
+// limit viewers and forgets the oldest first; expired viewers are swept in
+// the background until Close.
+type MemoryStore struct {
+	mu      sync.Mutex
+	limit   int
+	seen    map[string]*list.Element // key → order 의 원소
+	order   *list.List               // 기록한 순서 (앞이 가장 오래됨)
+	pending map[Post]int
+
+	stop chan struct{}
+	once sync.Once
+}
+
+// seenEntry is a viewer in MemoryStore.order
+type seenEntry struct {
+	key     string
+	expires time.Time
+}
+
+// DefaultMemoryLimit is the number of viewers MemoryStore keeps by default
+const DefaultMemoryLimit = 100000
+
+const (
+	// 만료된 방문 기록을 지우는 간격
+	sweepInterval = time.Minute
+	// 한 번 잠금에 지우는 최대 개수 (요청이 오래 기다리지 않도록)
+	sweepBatch = 1000
+)
+
+// NewMemoryStore creates an empty MemoryStore that keeps at most limit
+// viewers (DefaultMemoryLimit if limit is not positive)
+func NewMemoryStore(limit int) *MemoryStore {
+	if limit <= 0 {
+		limit = DefaultMemoryLimit
+	}
+	s := &MemoryStore{
+		limit:   limit,
+		seen:    map[string]*list.Element{},
+		order:   list.New(),
+		pending: map[Post]int{},
+		stop:    make(chan struct{}),
+	}
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.sweep(time.Now())
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// Close stops the background sweep
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+// Len returns the number of viewers kept
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// sweep removes viewers expired at now. Counter 는 항상 같은 window 를 쓰므로
+// 기록한 순서가 곧 만료 순서라 앞에서부터 만료되지 않은 기록을 만날 때까지만 지움
+func (s *MemoryStore) sweep(now time.Time) {
+	for {
+		s.mu.Lock()
+		n := 0
+		for e := s.order.Front(); e != nil && n < sweepBatch; e = s.order.Front() {
+			if now.Before(e.Value.(*seenEntry).expires) {
+				break
+			}
+			s.remove(e)
+			n++
+		}
+		s.mu.Unlock()
+		if n < sweepBatch {
+			return
+		}
+	}
+}
+
+func (s *MemoryStore) remove(e *list.Element) {
+	s.order.Remove(e)
+	delete(s.seen, e.Value.(*seenEntry).key)
+}
+
+// Seen implements Store
+func (s *MemoryStore) Seen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if e, ok := s.seen[key]; ok {
+		if now.Before(e.Value.(*seenEntry).expires) {
+			return true, nil
+		}
+		s.remove(e)
+	}
+	s.seen[key] = s.order.PushBack(&seenEntry{key: key, expires: now.Add(ttl)})
+	// 가득 차면 가장 오래된 기록부터 잊음 (그 방문자는 다시 한 번 셀 수 있음)
+	for s.order.Len() > s.limit {
+		s.remove(s.order.Front())
+	}
+	return false, nil
+}
+
+// Add implements Store
+func (s *MemoryStore) Add(ctx context.Context, post Post, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[post] += n
+	return nil
+}
+
+// Drain implements Store
+func (s *MemoryStore) Drain(ctx context.Context) (map[Post]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = map[Post]int{}
+	return pending, nil
+}
@@ -0,0 +1,278 @@
+package hits
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 반영된 조회수를 기록하는 Writer
+type fakeWriter struct {
+	mu   sync.Mutex
+	hits map[Post]int
+	// 실패시킬 게시판
+	fail string
+}
+
+func (w *fakeWriter) AddHits(ctx context.Context, table string, hits map[int]int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if table == w.fail {
+		return errors.New("실패")
+	}
+	if w.hits == nil {
+		w.hits = map[Post]int{}
+	}
+	for id, n := range hits {
+		w.hits[Post{table, id}] += n
+	}
+	return nil
+}
+
+func TestCounterWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	w := &fakeWriter{}
+	c := NewCounter(NewMemoryStore(0), w, time.Hour, 0)
+
+	for _, v := range []string{"mb:user1", "mb:user1", "ip:1.2.3.4", "mb:user2"} {
+		c.View(ctx, v, Post{"free", 1})
+	}
+	c.View(ctx, "mb:user1", Post{"free", 2})
+	want := map[Post]int{{"free", 1}: 3, {"free", 2}: 1}
+	if !reflect.DeepEqual(w.hits, want) {
+		t.Errorf("hits = %v, want %v", w.hits, want)
+	}
+	if err := c.Stop(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCounterBuffered(t *testing.T) {
+	ctx := context.Background()
+	w := &fakeWriter{fail: "qna"}
+	store := NewMemoryStore(0)
+	c := NewCounter(store, w, time.Hour, time.Hour)
+
+	for _, v := range []string{"a", "b", "b", "c"} {
+		c.View(ctx, v, Post{"free", 1})
+	}
+	c.View(ctx, "a", Post{"notice", 1})
+	c.View(ctx, "a", Post{"qna", 1})
+	if len(w.hits) != 0 {
+		t.Fatalf("written before flush: %v", w.hits)
+	}
+
+	// 실패한 게시판은 다시 쌓아 둠
+	if err := c.Flush(ctx); err == nil {
+		t.Error("Flush: want error")
+	}
+	want := map[Post]int{{"free", 1}: 3, {"notice", 1}: 1}
+	if !reflect.DeepEqual(w.hits, want) {
+		t.Errorf("hits = %v, want %v", w.hits, want)
+	}
+
+	w.fail = ""
+	c.View(ctx, "d", Post{"qna", 1})
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want[Post{"qna", 1}] = 2
+	if !reflect.DeepEqual(w.hits, want) {
+		t.Errorf("after Stop hits = %v, want %v", w.hits, want)
+	}
+	if pending, _ := store.Drain(ctx); len(pending) != 0 {
+		t.Errorf("pending after Stop = %v", pending)
+	}
+}
+
+func TestMemoryStoreWindow(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(0)
+	defer s.Close()
+	if seen, _ := s.Seen(ctx, "k", 20*time.Millisecond); seen {
+		t.Error("first Seen = true")
+	}
+	if seen, _ := s.Seen(ctx, "k", 20*time.Millisecond); !seen {
+		t.Error("second Seen = false")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if seen, _ := s.Seen(ctx, "k", 20*time.Millisecond); seen {
+		t.Error("Seen after window = true")
+	}
+}
+
+func TestMemoryStoreLimit(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(3)
+	defer s.Close()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		if seen, _ := s.Seen(ctx, key, time.Hour); seen {
+			t.Errorf("%s: first Seen = true", key)
+		}
+	}
+	if n := s.Len(); n != 3 {
+		t.Errorf("Len = %d, want 3", n)
+	}
+	// 가장 오래된 a 만 잊음
+	for key, want := range map[string]bool{"b": true, "c": true, "d": true} {
+		if seen, _ := s.Seen(ctx, key, time.Hour); seen != want {
+			t.Errorf("%s: Seen = %v", key, seen)
+		}
+	}
+	if seen, _ := s.Seen(ctx, "a", time.Hour); seen {
+		t.Error("evicted a: Seen = true")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(0)
+	defer s.Close()
+	for i := 0; i < sweepBatch+10; i++ {
+		s.Seen(ctx, "old"+strconv.Itoa(i), time.Millisecond)
+	}
+	s.Seen(ctx, "new", time.Hour)
+
+	s.sweep(time.Now().Add(time.Second))
+	if n := s.Len(); n != 1 {
+		t.Errorf("Len after sweep = %d, want 1", n)
+	}
+	if seen, _ := s.Seen(ctx, "new", time.Hour); !seen {
+		t.Error("new: Seen = false")
+	}
+}
+
+// SET NX, HINCRBY, MULTI/EXEC 만 처리하는 가짜 Redis
+func fakeRedis(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	keys := map[string]bool{}
+	hash := map[string]int{}
+	bulk := func(s string) string { return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n" }
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				var queued []string
+				inMulti := false
+				for {
+					header, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					count, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					var args []string
+					for i := 0; i < count; i++ {
+						r.ReadString('\n') // $길이
+						arg, _ := r.ReadString('\n')
+						args = append(args, strings.TrimSpace(arg))
+					}
+
+					mu.Lock()
+					var reply string
+					switch args[0] {
+					case "AUTH":
+						reply = "+OK\r\n"
+						if args[1] != "secret" {
+							reply = "-WRONGPASS invalid password\r\n"
+						}
+					case "SELECT":
+						reply = "+OK\r\n"
+					case "SET":
+						reply = "$-1\r\n"
+						if !keys[args[1]] {
+							keys[args[1]] = true
+							reply = "+OK\r\n"
+						}
+					case "HINCRBY":
+						n, _ := strconv.Atoi(args[3])
+						hash[args[2]] += n
+						reply = ":" + strconv.Itoa(hash[args[2]]) + "\r\n"
+					case "MULTI":
+						inMulti, reply = true, "+OK\r\n"
+					case "HGETALL", "DEL":
+						if inMulti {
+							queued = append(queued, args[0])
+							reply = "+QUEUED\r\n"
+						}
+					case "EXEC":
+						reply = "*" + strconv.Itoa(len(queued)) + "\r\n"
+						for _, cmd := range queued {
+							if cmd == "HGETALL" {
+								reply += "*" + strconv.Itoa(len(hash)*2) + "\r\n"
+								for f, n := range hash {
+									reply += bulk(f) + bulk(strconv.Itoa(n))
+								}
+							} else {
+								reply += ":1\r\n"
+								hash = map[string]int{}
+							}
+						}
+						inMulti, queued = false, nil
+					default:
+						reply = "-ERR unknown command\r\n"
+					}
+					mu.Unlock()
+					conn.Write([]byte(reply))
+				}
+			}(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	addr := fakeRedis(t)
+
+	if _, err := NewRedisStore("http://" + addr); err == nil {
+		t.Error("NewRedisStore accepted http url")
+	}
+	bad, _ := NewRedisStore("redis://:wrong@" + addr)
+	if _, err := bad.Seen(ctx, "k", time.Hour); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("wrong password: %v", err)
+	}
+
+	store, err := NewRedisStore("redis://:secret@" + addr + "/2?prefix=test:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.DB != 2 || store.Prefix != "test:" {
+		t.Errorf("store = %+v", store)
+	}
+	w := &fakeWriter{}
+	c := NewCounter(store, w, time.Hour, time.Hour)
+	for _, v := range []string{"a", "a", "b"} {
+		if _, err := c.View(ctx, v, Post{"free", 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.View(ctx, "a", Post{"free", 12})
+	if err := c.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[Post]int{{"free", 1}: 2, {"free", 12}: 1}
+	if !reflect.DeepEqual(w.hits, want) {
+		t.Errorf("hits = %v, want %v", w.hits, want)
+	}
+	if pending, err := store.Drain(ctx); err != nil || len(pending) != 0 {
+		t.Errorf("Drain after Stop = %v, %v", pending, err)
+	}
+}
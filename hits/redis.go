@@ -0,0 +1,81 @@
+package hits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fibergo/redis"
+)
+
+// RedisStore keeps viewers and counts in Redis (or a compatible server),
+// so several processes share de-duplication and the pending counts.
+type RedisStore struct {
+	*redis.Client
+	// 키 접두어 (기본 g5go:hits:)
+	Prefix string
+}
+
+// NewRedisStore parses redis://[:password@]host:port[/db][?prefix=...]
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	client, query, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("hits: %w", err)
+	}
+	s := &RedisStore{Client: client, Prefix: "g5go:hits:"}
+	if prefix := query.Get("prefix"); prefix != "" {
+		s.Prefix = prefix
+	}
+	return s, nil
+}
+
+// Seen implements Store with SET NX PX
+func (s *RedisStore) Seen(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		return false, nil
+	}
+	replies, err := s.Do(ctx, []string{"SET", s.Prefix + "seen:" + key, "1", "NX", "PX", strconv.FormatInt(ms, 10)})
+	if err != nil {
+		return false, err
+	}
+	// 이미 있으면 nil
+	return replies[0] == nil, nil
+}
+
+// Add implements Store with HINCRBY on the pending hash
+func (s *RedisStore) Add(ctx context.Context, post Post, n int) error {
+	_, err := s.Do(ctx, []string{"HINCRBY", s.Prefix + "pending", post.Table + ":" + strconv.Itoa(post.ID), strconv.Itoa(n)})
+	return err
+}
+
+// Drain implements Store. HGETALL and DEL run in one MULTI so views
+// added by other processes meanwhile are not lost.
+func (s *RedisStore) Drain(ctx context.Context) (map[Post]int, error) {
+	key := s.Prefix + "pending"
+	replies, err := s.Do(ctx, []string{"MULTI"}, []string{"HGETALL", key}, []string{"DEL", key}, []string{"EXEC"})
+	if err != nil {
+		return nil, err
+	}
+	exec, ok := replies[3].([]interface{})
+	if !ok || len(exec) != 2 {
+		return nil, errors.New("hits: redis transaction aborted")
+	}
+	fields, _ := exec[0].([]interface{})
+	pending := make(map[Post]int, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		field, _ := fields[i].([]byte)
+		value, _ := fields[i+1].([]byte)
+		table, id, ok := strings.Cut(string(field), ":")
+		n, err := strconv.Atoi(string(value))
+		wrID, idErr := strconv.Atoi(id)
+		if !ok || err != nil || idErr != nil {
+			continue
+		}
+		pending[Post{Table: table, ID: wrID}] += n
+	}
+	return pending, nil
+}
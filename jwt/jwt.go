@@ -0,0 +1,234 @@
+// Package jwt signs and verifies the compact JWTs issued to API clients.
+// Only HS256 and EdDSA (Ed25519) are supported; the algorithm is taken
+// from the key selected by kid, never from the token header.
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 서명 알고리즘
+const (
+	HS256 = "HS256"
+	EdDSA = "EdDSA"
+)
+
+// 토큰 종류 (typ 클레임)
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+var (
+	// ErrInvalid is returned for malformed tokens or bad signatures
+	ErrInvalid = errors.New("jwt: invalid token")
+	// ErrExpired is returned for tokens past exp
+	ErrExpired = errors.New("jwt: token expired")
+	// ErrUnknownKey is returned when kid does not name a known key
+	ErrUnknownKey = errors.New("jwt: unknown key id")
+)
+
+var b64 = base64.RawURLEncoding
+
+// Claims are the claims of tokens issued for a g5_member
+type Claims struct {
+	Subject   string `json:"sub"`      // mb_id
+	Level     int    `json:"mb_level"` // 발급 시점의 mb_level
+	Type      string `json:"typ"`      // access, refresh
+	ID        string `json:"jti"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Expires returns exp as a time
+func (c Claims) Expires() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// NewClaims returns claims with a random jti valid for ttl from now
+func NewClaims(mbID string, level int, typ string, ttl time.Duration) (Claims, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Claims{}, err
+	}
+	now := time.Now()
+	return Claims{
+		Subject:   mbID,
+		Level:     level,
+		Type:      typ,
+		ID:        hex.EncodeToString(id),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// Key is a signing or verification key identified by kid
+type Key struct {
+	ID  string
+	Alg string
+
+	secret  []byte             // HS256
+	private ed25519.PrivateKey // EdDSA, 검증 전용 키는 nil
+	public  ed25519.PublicKey
+}
+
+// NewHS256Key returns an HMAC-SHA256 key. The secret should be at least 32 bytes.
+func NewHS256Key(id string, secret []byte) (*Key, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("jwt: HS256 key %q is shorter than 32 bytes", id)
+	}
+	return &Key{ID: id, Alg: HS256, secret: secret}, nil
+}
+
+// NewEd25519Key returns an EdDSA key from a 32-byte seed or 64-byte private key
+func NewEd25519Key(id string, private []byte) (*Key, error) {
+	var pk ed25519.PrivateKey
+	switch len(private) {
+	case ed25519.SeedSize:
+		pk = ed25519.NewKeyFromSeed(private)
+	case ed25519.PrivateKeySize:
+		pk = ed25519.PrivateKey(private)
+	default:
+		return nil, fmt.Errorf("jwt: Ed25519 key %q must be a 32-byte seed or 64-byte private key", id)
+	}
+	return &Key{ID: id, Alg: EdDSA, private: pk, public: pk.Public().(ed25519.PublicKey)}, nil
+}
+
+// NewEd25519PublicKey returns a verification-only EdDSA key, for keys
+// retired from signing whose tokens should still be accepted
+func NewEd25519PublicKey(id string, public []byte) (*Key, error) {
+	if len(public) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("jwt: Ed25519 public key %q must be 32 bytes", id)
+	}
+	return &Key{ID: id, Alg: EdDSA, public: ed25519.PublicKey(public)}, nil
+}
+
+func (k *Key) sign(data []byte) ([]byte, error) {
+	switch k.Alg {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(data)
+		return mac.Sum(nil), nil
+	case EdDSA:
+		if k.private == nil {
+			return nil, fmt.Errorf("jwt: key %q cannot sign", k.ID)
+		}
+		return ed25519.Sign(k.private, data), nil
+	}
+	return nil, fmt.Errorf("jwt: unsupported algorithm %q", k.Alg)
+}
+
+func (k *Key) verify(data, sig []byte) bool {
+	switch k.Alg {
+	case HS256:
+		want, _ := k.sign(data)
+		return hmac.Equal(sig, want)
+	case EdDSA:
+		return ed25519.Verify(k.public, data, sig)
+	}
+	return false
+}
+
+// KeySet signs with one key and verifies with any key it holds, so keys
+// can be rotated by adding a new signing key and keeping the old one
+// until its tokens expire
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// NewKeySet returns a key set signing with the key named signingID
+func NewKeySet(signingID string, keys ...*Key) (*KeySet, error) {
+	s := &KeySet{keys: map[string]*Key{}}
+	for _, k := range keys {
+		if _, dup := s.keys[k.ID]; dup {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", k.ID)
+		}
+		s.keys[k.ID] = k
+	}
+	s.signing = s.keys[signingID]
+	if s.signing == nil {
+		return nil, fmt.Errorf("jwt: signing key %q not found", signingID)
+	}
+	if s.signing.Alg == EdDSA && s.signing.private == nil {
+		return nil, fmt.Errorf("jwt: signing key %q has no private key", signingID)
+	}
+	return s, nil
+}
+
+// Sign returns the compact serialization of claims
+func (s *KeySet) Sign(claims Claims) (string, error) {
+	h, err := json.Marshal(header{Alg: s.signing.Alg, Typ: "JWT", Kid: s.signing.ID})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := b64.EncodeToString(h) + "." + b64.EncodeToString(p)
+	sig, err := s.signing.sign([]byte(signed))
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + b64.EncodeToString(sig), nil
+}
+
+// Parse verifies the signature and expiry of token and returns its claims
+func (s *KeySet) Parse(token string, now time.Time) (Claims, error) {
+	var claims Claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrInvalid
+	}
+
+	raw, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return claims, ErrInvalid
+	}
+	var h header
+	if err := json.Unmarshal(raw, &h); err != nil {
+		return claims, ErrInvalid
+	}
+	key, ok := s.keys[h.Kid]
+	if !ok {
+		return claims, ErrUnknownKey
+	}
+	// alg 헤더는 키의 알고리즘과 같아야 함 (none, 알고리즘 바꿔치기 방지)
+	if h.Alg != key.Alg {
+		return claims, ErrInvalid
+	}
+
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return claims, ErrInvalid
+	}
+
+	raw, err = b64.DecodeString(parts[1])
+	if err != nil {
+		return claims, ErrInvalid
+	}
+	if err := json.Unmarshal(raw, &claims); err != nil || claims.Subject == "" || claims.ID == "" {
+		return Claims{}, ErrInvalid
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrExpired
+	}
+	return claims, nil
+}
@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = bytes.Repeat([]byte("k"), 32)
+
+func TestSignParse(t *testing.T) {
+	hs, _ := NewHS256Key("hs1", secret)
+	ed, _ := NewEd25519Key("ed1", bytes.Repeat([]byte{1}, ed25519.SeedSize))
+
+	for _, key := range []*Key{hs, ed} {
+		set, err := NewKeySet(key.ID, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, _ := NewClaims("user1", 2, TypeAccess, time.Minute)
+		token, err := set.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := set.Parse(token, time.Now())
+		if err != nil || got != claims {
+			t.Errorf("%s: claims = %+v, err = %v", key.Alg, got, err)
+		}
+
+		if _, err := set.Parse(token, claims.Expires()); !errors.Is(err, ErrExpired) {
+			t.Errorf("%s: expired token: err = %v", key.Alg, err)
+		}
+		tampered := token[:len(token)-2] + "AA"
+		if _, err := set.Parse(tampered, time.Now()); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: tampered token: err = %v", key.Alg, err)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old, _ := NewHS256Key("2024", secret)
+	seed := bytes.Repeat([]byte{7}, ed25519.SeedSize)
+	current, _ := NewEd25519Key("2025", seed)
+
+	before, _ := NewKeySet("2024", old)
+	claims, _ := NewClaims("user1", 2, TypeAccess, time.Minute)
+	oldToken, _ := before.Sign(claims)
+
+	// 새 키로 서명하고 이전 키의 토큰도 받음
+	after, _ := NewKeySet("2025", old, current)
+	if _, err := after.Parse(oldToken, time.Now()); err != nil {
+		t.Errorf("old token: %v", err)
+	}
+	newToken, _ := after.Sign(claims)
+	if !strings.Contains(decodeHeader(t, newToken), `"kid":"2025"`) {
+		t.Errorf("new token header = %s", decodeHeader(t, newToken))
+	}
+
+	// 이전 키를 빼면 거부
+	retired, _ := NewKeySet("2025", current)
+	if _, err := retired.Parse(oldToken, time.Now()); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("retired key: err = %v", err)
+	}
+
+	// 공개키만 남긴 검증 전용 키
+	public, _ := NewEd25519PublicKey("2025", ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey))
+	other, _ := NewHS256Key("next", bytes.Repeat([]byte("n"), 32))
+	verifyOnly, _ := NewKeySet("next", other, public)
+	if _, err := verifyOnly.Parse(newToken, time.Now()); err != nil {
+		t.Errorf("verification-only key: %v", err)
+	}
+}
+
+func TestAlgorithmConfusion(t *testing.T) {
+	ed, _ := NewEd25519Key("k", bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	set, _ := NewKeySet("k", ed)
+
+	// 같은 kid 로 HS256 / none 토큰을 만들어도 거부
+	claims, _ := NewClaims("admin", 10, TypeAccess, time.Minute)
+	hs, _ := NewHS256Key("k", []byte(ed.public)[:32])
+	forged, _ := (&KeySet{signing: hs, keys: map[string]*Key{"k": hs}}).Sign(claims)
+	if _, err := set.Parse(forged, time.Now()); !errors.Is(err, ErrInvalid) {
+		t.Errorf("HS256 with Ed25519 kid: err = %v", err)
+	}
+
+	none := b64.EncodeToString([]byte(`{"alg":"none","typ":"JWT","kid":"k"}`)) + "." + strings.Split(forged, ".")[1] + "."
+	if _, err := set.Parse(none, time.Now()); !errors.Is(err, ErrInvalid) {
+		t.Errorf("alg none: err = %v", err)
+	}
+}
+
+func TestParseKeySet(t *testing.T) {
+	spec := "a:hs256:" + base64.StdEncoding.EncodeToString(secret) +
+		", b:ed25519:" + base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	set, err := ParseKeySet(spec, "b")
+	if err != nil || set.signing.ID != "b" || len(set.keys) != 2 {
+		t.Fatalf("set = %+v, err = %v", set, err)
+	}
+	if set, _ := ParseKeySet(spec, ""); set.signing.ID != "a" {
+		t.Errorf("default signing key = %s", set.signing.ID)
+	}
+
+	for _, bad := range []string{"", "a:hs256:c2hvcnQ=", "a:rsa:AAAA", "a:hs256:!!", "nokey"} {
+		if _, err := ParseKeySet(bad, ""); err == nil {
+			t.Errorf("%q: accepted", bad)
+		}
+	}
+	if _, err := ParseKeySet(spec, "missing"); err == nil {
+		t.Error("missing signing key accepted")
+	}
+}
+
+func decodeHeader(t *testing.T, token string) string {
+	t.Helper()
+	raw, err := b64.DecodeString(strings.Split(token, ".")[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(raw)
+}
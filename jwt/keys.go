@@ -0,0 +1,51 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ParseKeySet reads keys written as "kid:type:base64,kid:type:base64".
+// type is hs256 (secret), ed25519 (seed or private key) or ed25519-public.
+// The key named signingID signs new tokens; if empty the first key is used.
+func ParseKeySet(spec, signingID string) (*KeySet, error) {
+	var keys []*Key
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("jwt: invalid key %q (kid:type:base64)", entry)
+		}
+		data, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q is not base64: %w", parts[0], err)
+		}
+
+		var key *Key
+		switch strings.ToLower(parts[1]) {
+		case "hs256":
+			key, err = NewHS256Key(parts[0], data)
+		case "ed25519":
+			key, err = NewEd25519Key(parts[0], data)
+		case "ed25519-public":
+			key, err = NewEd25519PublicKey(parts[0], data)
+		default:
+			err = fmt.Errorf("jwt: unknown key type %q", parts[1])
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwt: no keys")
+	}
+	if signingID == "" {
+		signingID = keys[0].ID
+	}
+	return NewKeySet(signingID, keys...)
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Comment is a row of a g5_write_* table with wr_is_comment = 1
+type Comment struct {
+	ID           int        // wr_id
+	Parent       int        // wr_parent (원글 wr_id)
+	Comment      int        // wr_comment (댓글 그룹 번호)
+	CommentReply string     // wr_comment_reply (대댓글 단계)
+	Option       string     // wr_option
+	Content      string     // wr_content
+	Good         int        // wr_good
+	Nogood       int        // wr_nogood
+	MemberID     string     // mb_id
+	Password     string     // wr_password
+	Name         string     // wr_name
+	Email        string     // wr_email
+	Homepage     string     // wr_homepage
+	Datetime     time.Time  // wr_datetime
+	IP           string     // wr_ip
+	Extra        [10]string // wr_1 ~ wr_10
+
+	// 부모 댓글 wr_id (조회 시 wr_comment_reply 로 계산, 원댓글이면 0)
+	ReplyTo int
+}
+
+// Depth returns the reply depth encoded in wr_comment_reply ("" = 0, "A" = 1, "AB" = 2)
+func (c Comment) Depth() int {
+	return len(c.CommentReply)
+}
+
+// ParentReply returns the wr_comment_reply of the comment this one replies to
+func (c Comment) ParentReply() string {
+	if c.CommentReply == "" {
+		return ""
+	}
+	return c.CommentReply[:len(c.CommentReply)-1]
+}
+
+// HasOption reports whether wr_option contains opt
+func (c Comment) HasOption(opt string) bool {
+	return hasOption(c.Option, opt)
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// BoardFile is a row of Gnuboard's g5_board_file table. The file itself is
+// data/file/{bo_table}/{bf_file}.
+type BoardFile struct {
+	Table    string    // bo_table
+	PostID   int       // wr_id
+	No       int       // bf_no (0부터)
+	Source   string    // bf_source (올린 파일명)
+	File     string    // bf_file (저장된 파일명)
+	Download int       // bf_download
+	Content  string    // bf_content (파일 설명)
+	Filesize int64     // bf_filesize
+	Width    int       // bf_width
+	Height   int       // bf_height
+	Type     int       // bf_type (이미지면 IMAGETYPE_*, 아니면 0)
+	Datetime time.Time // bf_datetime
+}
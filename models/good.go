@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// BoardGood is a row of Gnuboard's g5_board_good table (one vote per member)
+type BoardGood struct {
+	ID       int       // bg_id
+	Table    string    // bo_table
+	PostID   int       // wr_id
+	MemberID string    // mb_id
+	Flag     string    // bg_flag (good, nogood)
+	Datetime time.Time // bg_datetime
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Member is a row of Gnuboard's g5_member table
+type Member struct {
+	ID            string    // mb_id
+	Password      string    // mb_password
+	Name          string    // mb_name
+	Nick          string    // mb_nick
+	Email         string    // mb_email
+	Level         int       // mb_level
+	Point         int       // mb_point
+	Datetime      time.Time // mb_datetime
+	LeaveDate     string    // mb_leave_date (탈퇴일, yyyymmdd)
+	InterceptDate string    // mb_intercept_date (접근차단일, yyyymmdd)
+}
+
+// IsLeft reports whether the member has left as of now (mb_leave_date <= today)
+func (m Member) IsLeft(now time.Time) bool {
+	return m.LeaveDate != "" && m.LeaveDate <= now.Format("20060102")
+}
+
+// IsIntercepted reports whether the member is blocked as of now (mb_intercept_date <= today)
+func (m Member) IsIntercepted(now time.Time) bool {
+	return m.InterceptDate != "" && m.InterceptDate <= now.Format("20060102")
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Point is a row of Gnuboard's g5_point table
+type Point struct {
+	ID          int       // po_id
+	MemberID    string    // mb_id
+	Datetime    time.Time // po_datetime
+	Content     string    // po_content
+	Point       int       // po_point
+	UsePoint    int       // po_use_point
+	Expired     int       // po_expired
+	ExpireDate  string    // po_expire_date (yyyy-mm-dd)
+	MemberPoint int       // po_mb_point (적립 후 mb_point)
+	RelTable    string    // po_rel_table
+	RelID       string    // po_rel_id
+	RelAction   string    // po_rel_action
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Post is a row of a g5_write_* table with wr_is_comment = 0
+type Post struct {
+	ID           int        // wr_id
+	Num          int        // wr_num (음수, 작을수록 최신)
+	Reply        string     // wr_reply (답변글 단계)
+	Parent       int        // wr_parent
+	CommentCount int        // wr_comment (댓글 수)
+	CategoryName string     // ca_name
+	Option       string     // wr_option (html1, html2, secret, mail)
+	Subject      string     // wr_subject
+	Content      string     // wr_content
+	Link1        string     // wr_link1
+	Link2        string     // wr_link2
+	Link1Hit     int        // wr_link1_hit
+	Link2Hit     int        // wr_link2_hit
+	Hit          int        // wr_hit
+	Good         int        // wr_good
+	Nogood       int        // wr_nogood
+	MemberID     string     // mb_id
+	Password     string     // wr_password
+	Name         string     // wr_name
+	Email        string     // wr_email
+	Homepage     string     // wr_homepage
+	Datetime     time.Time  // wr_datetime
+	File         int        // wr_file (첨부파일 수)
+	Last         string     // wr_last
+	IP           string     // wr_ip
+	Extra        [10]string // wr_1 ~ wr_10
+}
+
+// HasOption reports whether wr_option contains opt
+func (p Post) HasOption(opt string) bool {
+	return hasOption(p.Option, opt)
+}
+
+func hasOption(options, opt string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if strings.TrimSpace(o) == opt {
+			return true
+		}
+	}
+	return false
+}
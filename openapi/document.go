@@ -0,0 +1,198 @@
+// Package openapi builds an OpenAPI 3.1 document from the API route table.
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Document is an OpenAPI 3.1 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// Info is the document's info object
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL of the API
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Components holds reusable schemas
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// PathItem is a single operation of a path
+type PathItem struct {
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// RequestBody is the request body of an operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a response body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Operation documents one route. Body values are zero values of the response types.
+type Operation struct {
+	Summary string
+	Tags    []string
+	Query   []Param
+	Body    interface{} // 요청 본문 (JSON), 없으면 nil
+	// 본문의 Content-Type (기본 application/json)
+	BodyType string
+	// 본문 없이 보내도 되는 요청 (DELETE 의 손님 비밀번호 등)
+	BodyOptional bool
+	Responses    []ResponseDoc
+}
+
+// Param documents a query parameter
+type Param struct {
+	Name        string
+	Type        string // integer, string
+	Description string
+}
+
+// ResponseDoc documents one status code of an operation
+type ResponseDoc struct {
+	Status      int
+	Description string
+	Body        interface{}
+}
+
+// Route is a documented route. Path uses Fiber syntax (/boards/:type).
+type Route struct {
+	Method string
+	Path   string
+	Doc    Operation
+}
+
+// Build creates the document for routes served under basePath
+func Build(info Info, basePath string, routes []Route) *Document {
+	r := NewReflector()
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Servers: []Server{{URL: basePath}},
+		Paths:   map[string]map[string]*PathItem{},
+	}
+
+	for _, rt := range routes {
+		path, params := convertPath(rt.Path)
+		item := &PathItem{
+			Summary:     rt.Doc.Summary,
+			Tags:        rt.Doc.Tags,
+			OperationID: operationID(rt.Method, rt.Path),
+			Parameters:  params,
+			Responses:   map[string]*Response{},
+		}
+		for _, q := range rt.Doc.Query {
+			item.Parameters = append(item.Parameters, Parameter{
+				Name:        q.Name,
+				In:          "query",
+				Description: q.Description,
+				Schema:      &Schema{Type: q.Type},
+			})
+		}
+		if rt.Doc.Body != nil {
+			bodyType := rt.Doc.BodyType
+			if bodyType == "" {
+				bodyType = "application/json"
+			}
+			item.RequestBody = &RequestBody{
+				Required: !rt.Doc.BodyOptional,
+				Content: map[string]MediaType{
+					bodyType: {Schema: r.Reflect(rt.Doc.Body)},
+				},
+			}
+		}
+		for _, resp := range rt.Doc.Responses {
+			description := resp.Description
+			if description == "" {
+				description = http.StatusText(resp.Status)
+			}
+			response := &Response{Description: description}
+			if resp.Body != nil {
+				response.Content = map[string]MediaType{
+					"application/json": {Schema: r.Reflect(resp.Body)},
+				}
+			}
+			item.Responses[strconv.Itoa(resp.Status)] = response
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(rt.Method)] = item
+	}
+
+	doc.Components.Schemas = r.Components
+	return doc
+}
+
+// convertPath turns /boards/:type into /boards/{type} and returns its path parameters
+func convertPath(path string) (string, []Parameter) {
+	var params []Parameter
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if !strings.HasPrefix(p, ":") {
+			continue
+		}
+		name := strings.TrimSuffix(p[1:], "?")
+		typ := "string"
+		if name == "id" || strings.HasSuffix(name, "_id") || name == "no" {
+			typ = "integer"
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: typ}})
+		parts[i] = "{" + name + "}"
+	}
+	return strings.Join(parts, "/"), params
+}
+
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, p := range strings.Split(path, "/") {
+		p = strings.TrimPrefix(p, ":")
+		if p == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(p[:1]) + p[1:])
+	}
+	return b.String()
+}
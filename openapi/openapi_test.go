@@ -0,0 +1,61 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type inner struct {
+	ID int `json:"id"`
+}
+
+type outer struct {
+	inner
+	Name    string    `json:"name"`
+	Tags    []string  `json:"tags" openapi:"nullable"`
+	Note    string    `json:"note,omitempty"`
+	Created time.Time `json:"created"`
+	skip    int
+}
+
+func TestReflectAndValidate(t *testing.T) {
+	doc := Build(Info{Title: "t", Version: "1"}, "/", []Route{{
+		Method: "GET",
+		Path:   "/things/:id",
+		Doc:    Operation{Responses: []ResponseDoc{{Status: 200, Body: outer{}}}},
+	}})
+
+	item := doc.Paths["/things/{id}"]["get"]
+	if item == nil || item.Parameters[0].Name != "id" {
+		t.Fatalf("path item = %+v", item)
+	}
+	schema := item.Responses["200"].Content["application/json"].Schema
+	if schema.Ref != "#/components/schemas/outer" {
+		t.Fatalf("ref = %q", schema.Ref)
+	}
+	if got := doc.Components.Schemas["outer"].Required; len(got) != 4 {
+		t.Errorf("required = %v", got)
+	}
+
+	tests := []struct {
+		body string
+		ok   bool
+	}{
+		{`{"id": 1, "name": "a", "tags": null, "created": "2025-01-01T00:00:00Z"}`, true},
+		{`{"id": 1, "name": "a", "tags": ["x"], "note": "n", "created": "2025-01-01T09:00:00+09:00"}`, true},
+		{`{"id": 1, "name": "a", "tags": [], "created": "2025-01-01 00:00:00"}`, false},
+		{`{"id": "1", "name": "a", "tags": [], "created": "2025-01-01T00:00:00Z"}`, false},
+		{`{"id": 1, "tags": [], "created": "2025-01-01T00:00:00Z"}`, false},
+		{`{"id": 1, "name": "a", "tags": [], "created": "2025-01-01T00:00:00Z", "extra": 1}`, false},
+	}
+	for _, tt := range tests {
+		var v interface{}
+		if err := json.Unmarshal([]byte(tt.body), &v); err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.Validate(schema, v); (err == nil) != tt.ok {
+			t.Errorf("Validate(%s) = %v, want ok=%v", tt.body, err, tt.ok)
+		}
+	}
+}
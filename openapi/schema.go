@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema (2020-12) used by OpenAPI 3.1
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 interface{}        `json:"type,omitempty"` // string 또는 []string
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // bool 또는 *Schema
+	Enum                 []interface{}      `json:"enum,omitempty"`
+}
+
+// Reflector converts Go types into schemas, collecting named struct types as components
+type Reflector struct {
+	Components map[string]*Schema
+}
+
+// NewReflector creates an empty reflector
+func NewReflector() *Reflector {
+	return &Reflector{Components: map[string]*Schema{}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Reflect returns the schema of v's type
+func (r *Reflector) Reflect(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+	return r.reflectType(reflect.TypeOf(v))
+}
+
+func (r *Reflector) reflectType(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return r.reflectType(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflectType(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		// 제네릭 타입 (Envelope[T]) 은 인스턴스마다 다르므로 인라인
+		name := t.Name()
+		if name == "" || strings.Contains(name, "[") {
+			return r.structSchema(t)
+		}
+		if _, ok := r.Components[name]; !ok {
+			r.Components[name] = &Schema{} // 재귀 참조 대비
+			r.Components[name] = r.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+func (r *Reflector) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{},
+		AdditionalProperties: false,
+	}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON fields of t, flattening embedded structs like encoding/json
+func (r *Reflector) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			r.addFields(s, f.Type)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := r.reflectType(f.Type)
+		if f.Tag.Get("openapi") == "nullable" {
+			fs = nullable(fs)
+		}
+		if d := f.Tag.Get("doc"); d != "" {
+			fs.Description = d
+		}
+		s.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// nullable allows null in addition to the schema's type
+func nullable(s *Schema) *Schema {
+	if t, ok := s.Type.(string); ok {
+		s.Type = []string{t, "null"}
+	}
+	return s
+}
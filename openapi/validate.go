@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Validate checks a decoded JSON value (encoding/json into interface{})
+// against schema, resolving $ref against the document's components.
+func (d *Document) Validate(schema *Schema, value interface{}) error {
+	return d.validate(schema, value, "$")
+}
+
+func (d *Document) validate(s *Schema, v interface{}, at string) error {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		ref, ok := d.Components.Schemas[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown $ref %s", at, s.Ref)
+		}
+		return d.validate(ref, v, at)
+	}
+	if s.Type == nil {
+		return nil
+	}
+
+	actual := jsonType(v)
+	if !typeAllowed(s.Type, actual) {
+		return fmt.Errorf("%s: type %s, want %v", at, actual, s.Type)
+	}
+
+	switch actual {
+	case "string":
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, v)
+			}
+		}
+	case "array":
+		if s.Items != nil {
+			for i, item := range v.([]interface{}) {
+				if err := d.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		return d.validateObject(s, v.(map[string]interface{}), at)
+	}
+	return nil
+}
+
+func (d *Document) validateObject(s *Schema, obj map[string]interface{}, at string) error {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", at, name)
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if ps, ok := s.Properties[k]; ok {
+			if err := d.validate(ps, obj[k], at+"."+k); err != nil {
+				return err
+			}
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				return fmt.Errorf("%s: unexpected property %q", at, k)
+			}
+		case *Schema:
+			if err := d.validate(extra, obj[k], at+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonType(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func typeAllowed(want interface{}, actual string) bool {
+	match := func(t string) bool {
+		return t == actual || (t == "number" && actual == "integer")
+	}
+	switch w := want.(type) {
+	case string:
+		return match(w)
+	case []string:
+		for _, t := range w {
+			if match(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+// Package phpsession reads sessions written by PHP so the Go service can
+// share the login state of the Gnuboard front-end.
+package phpsession
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// session.serialize_handler 값
+const (
+	HandlerPHP          = "php"           // key|value key|value (PHP 기본)
+	HandlerPHPSerialize = "php_serialize" // serialize($_SESSION)
+)
+
+// ErrSyntax is returned for data that is not a PHP session
+var ErrSyntax = errors.New("phpsession: invalid session data")
+
+// Decode parses session data written with the given serialize_handler.
+// Values are string, int64, float64, bool, nil or map[string]interface{}
+// (arrays and objects).
+func Decode(data []byte, handler string) (map[string]interface{}, error) {
+	if handler == HandlerPHPSerialize {
+		d := decoder{data: data}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, ErrSyntax
+		}
+		return m, nil
+	}
+
+	result := map[string]interface{}{}
+	d := decoder{data: data}
+	for d.pos < len(d.data) {
+		end := bytes.IndexByte(d.data[d.pos:], '|')
+		if end < 0 {
+			return nil, ErrSyntax
+		}
+		key := string(d.data[d.pos : d.pos+end])
+		d.pos += end + 1
+		v, err := d.value()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, key)
+		}
+		result[key] = v
+	}
+	return result, nil
+}
+
+// String returns the session value as a string (PHP 의 느슨한 형 변환과 같이)
+func String(session map[string]interface{}, key string) string {
+	switch v := session[key].(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+	}
+	return ""
+}
+
+// decoder reads values in PHP serialize() format
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) value() (interface{}, error) {
+	if d.pos+1 >= len(d.data) {
+		return nil, ErrSyntax
+	}
+	kind := d.data[d.pos]
+	if kind == 'N' {
+		if d.data[d.pos+1] != ';' {
+			return nil, ErrSyntax
+		}
+		d.pos += 2
+		return nil, nil
+	}
+	if d.data[d.pos+1] != ':' {
+		return nil, ErrSyntax
+	}
+	d.pos += 2
+
+	switch kind {
+	case 'i':
+		s, err := d.until(';')
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, ErrSyntax
+		}
+		return n, nil
+	case 'd':
+		s, err := d.until(';')
+		if err != nil {
+			return nil, err
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, ErrSyntax
+		}
+		return f, nil
+	case 'b':
+		s, err := d.until(';')
+		if err != nil || (s != "0" && s != "1") {
+			return nil, ErrSyntax
+		}
+		return s == "1", nil
+	case 's':
+		s, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.expect(';'); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case 'a':
+		return d.array()
+	case 'O':
+		// 클래스 이름은 버리고 속성만
+		if _, err := d.str(); err != nil {
+			return nil, err
+		}
+		if err := d.expect(':'); err != nil {
+			return nil, err
+		}
+		return d.array()
+	}
+	return nil, ErrSyntax
+}
+
+// str reads `len:"bytes"`
+func (d *decoder) str() (string, error) {
+	s, err := d.until(':')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || d.pos+n+2 > len(d.data) || d.data[d.pos] != '"' || d.data[d.pos+n+1] != '"' {
+		return "", ErrSyntax
+	}
+	v := string(d.data[d.pos+1 : d.pos+1+n])
+	d.pos += n + 2
+	return v, nil
+}
+
+// array reads `count:{key;value;...}` into a map with string keys
+func (d *decoder) array() (map[string]interface{}, error) {
+	s, err := d.until(':')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return nil, ErrSyntax
+	}
+	if err := d.expect('{'); err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{}, min(n, 64))
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		var key string
+		switch k := k.(type) {
+		case string:
+			key = k
+		case int64:
+			key = strconv.FormatInt(k, 10)
+		default:
+			return nil, ErrSyntax
+		}
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	if err := d.expect('}'); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (d *decoder) until(c byte) (string, error) {
+	end := bytes.IndexByte(d.data[d.pos:], c)
+	if end < 0 {
+		return "", ErrSyntax
+	}
+	s := string(d.data[d.pos : d.pos+end])
+	d.pos += end + 1
+	return s, nil
+}
+
+func (d *decoder) expect(c byte) error {
+	if d.pos >= len(d.data) || d.data[d.pos] != c {
+		return ErrSyntax
+	}
+	d.pos++
+	return nil
+}
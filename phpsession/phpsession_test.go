@@ -0,0 +1,153 @@
+package phpsession
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecode(t *testing.T) {
+	// 그누보드 로그인 후 세션 파일과 같은 형식
+	data := `ss_mb_id|s:5:"user1";ss_mb_key|s:32:"0123456789abcdef0123456789abcdef";ss_lang|s:6:"한국";` +
+		`count|i:3;ratio|d:0.5;ok|b:1;none|N;list|a:2:{i:0;s:1:"a";s:3:"key";a:1:{i:0;b:0;}}` +
+		`obj|O:8:"stdClass":1:{s:4:"name";s:2:"go";}`
+	sess, err := Decode([]byte(data), HandlerPHP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := map[string]string{"ss_mb_id": "user1", "ss_lang": "한국", "count": "3", "ratio": "0.5", "ok": "1", "none": ""}
+	for key, want := range checks {
+		if got := String(sess, key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	list := sess["list"].(map[string]interface{})
+	if list["0"] != "a" || list["key"].(map[string]interface{})["0"] != false {
+		t.Errorf("list = %#v", list)
+	}
+	if sess["obj"].(map[string]interface{})["name"] != "go" {
+		t.Errorf("obj = %#v", sess["obj"])
+	}
+}
+
+func TestDecodeSerializeHandler(t *testing.T) {
+	sess, err := Decode([]byte(`a:2:{s:8:"ss_mb_id";s:5:"admin";s:7:"visited";i:1;}`), HandlerPHPSerialize)
+	if err != nil || String(sess, "ss_mb_id") != "admin" || String(sess, "visited") != "1" {
+		t.Errorf("sess = %#v, err = %v", sess, err)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, data := range []string{
+		`ss_mb_id`,
+		`ss_mb_id|s:10:"short";`,
+		`ss_mb_id|s:5:"user1"`,
+		`n|i:x;`,
+		`a|a:2:{i:0;s:1:"a";}`,
+		`x|z:1;`,
+	} {
+		if _, err := Decode([]byte(data), HandlerPHP); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: err = %v", data, err)
+		}
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sess_abc123"), []byte(`ss_mb_id|s:1:"a";`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(dir, "sess_old")
+	os.WriteFile(old, []byte(`ss_mb_id|s:1:"b";`), 0o600)
+	past := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(old, past, past)
+
+	store := FileStore{Dir: dir, MaxLifetime: time.Hour}
+	if data, err := store.Read(context.Background(), "abc123"); err != nil || string(data) != `ss_mb_id|s:1:"a";` {
+		t.Errorf("read = %q, %v", data, err)
+	}
+	for _, id := range []string{"old", "missing", "../sess_abc123", ""} {
+		if _, err := store.Read(context.Background(), id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%q: err = %v", id, err)
+		}
+	}
+}
+
+func TestRedisStore(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	// GET 만 처리하는 가짜 Redis
+	values := map[string]string{"PHPREDIS_SESSION:abc": `ss_mb_id|s:5:"user1";`}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					header, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					count, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					var args []string
+					for i := 0; i < count; i++ {
+						r.ReadString('\n') // $길이
+						arg, _ := r.ReadString('\n')
+						args = append(args, strings.TrimSpace(arg))
+					}
+					switch {
+					case args[0] == "AUTH" && args[1] != "secret":
+						conn.Write([]byte("-WRONGPASS invalid password\r\n"))
+					case args[0] == "AUTH" || args[0] == "SELECT":
+						conn.Write([]byte("+OK\r\n"))
+					case args[0] == "GET":
+						v, ok := values[args[1]]
+						if !ok {
+							conn.Write([]byte("$-1\r\n"))
+							continue
+						}
+						conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	store, err := NewRedisStore("redis://:secret@" + ln.Addr().String() + "/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := Reader{Store: store}
+	sess, err := reader.Load(context.Background(), "abc")
+	if err != nil || String(sess, "ss_mb_id") != "user1" {
+		t.Errorf("sess = %#v, err = %v", sess, err)
+	}
+	if _, err := store.Read(context.Background(), "nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing: err = %v", err)
+	}
+
+	// 연결을 재사용하므로 잘못된 비밀번호는 새 저장소로 확인
+	bad, err := NewRedisStore("redis://:wrong@" + ln.Addr().String() + "/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bad.Read(context.Background(), "abc"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("wrong password: err = %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package phpsession
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+)
+
+// Reader loads and decodes sessions from a Store
+type Reader struct {
+	Store Store
+	// session.serialize_handler (기본 php)
+	Handler string
+}
+
+// Load returns the decoded $_SESSION of id
+func (r Reader) Load(ctx context.Context, id string) (map[string]interface{}, error) {
+	data, err := r.Store.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data, r.Handler)
+}
+
+// MemberKey returns the ss_mb_key Gnuboard's common.php expects for a
+// logged-in member: md5(mb_datetime . client ip . user agent)
+func MemberKey(mbDatetime, ip, userAgent string) string {
+	sum := md5.Sum([]byte(mbDatetime + ip + userAgent))
+	return hex.EncodeToString(sum[:])
+}
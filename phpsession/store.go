@@ -0,0 +1,115 @@
+package phpsession
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"fibergo/redis"
+)
+
+// ErrNotFound is returned for unknown or expired sessions
+var ErrNotFound = errors.New("phpsession: session not found")
+
+// session.sid_bits_per_character 설정과 관계없이 허용되는 문자
+var validID = regexp.MustCompile(`^[A-Za-z0-9,-]{1,128}$`)
+
+// ValidID reports whether id can be a PHP session id. Stores reject
+// other ids so a cookie cannot point outside the session storage.
+func ValidID(id string) bool {
+	return validID.MatchString(id)
+}
+
+// Store reads raw session data by session id
+type Store interface {
+	Read(ctx context.Context, id string) ([]byte, error)
+}
+
+// FileStore reads the files handler (session.save_path/sess_{id}).
+// Gnuboard keeps them in data/session.
+type FileStore struct {
+	Dir string
+	// 마지막 수정 후 이 시간이 지난 파일은 만료 (session.gc_maxlifetime, 0 이면 확인 안 함)
+	MaxLifetime time.Duration
+}
+
+// Read implements Store
+func (s FileStore) Read(ctx context.Context, id string) ([]byte, error) {
+	if !ValidID(id) {
+		return nil, ErrNotFound
+	}
+	path := filepath.Join(s.Dir, "sess_"+id)
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.MaxLifetime > 0 && time.Since(info.ModTime()) > s.MaxLifetime {
+		return nil, ErrNotFound
+	}
+	return os.ReadFile(path)
+}
+
+// RedisStore reads sessions saved by the phpredis session handler
+// (session.save_handler = redis)
+type RedisStore struct {
+	*redis.Client
+	// 키 접두어 (phpredis 기본 PHPREDIS_SESSION:)
+	Prefix string
+}
+
+// NewRedisStore parses redis://[:password@]host:port[/db][?prefix=...]
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	client, query, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("phpsession: %w", err)
+	}
+	s := &RedisStore{Client: client, Prefix: "PHPREDIS_SESSION:"}
+	if prefix := query.Get("prefix"); prefix != "" {
+		s.Prefix = prefix
+	}
+	return s, nil
+}
+
+// Read implements Store
+func (s *RedisStore) Read(ctx context.Context, id string) ([]byte, error) {
+	if !ValidID(id) {
+		return nil, ErrNotFound
+	}
+	replies, err := s.Do(ctx, []string{"GET", s.Prefix + id})
+	if err != nil {
+		return nil, err
+	}
+	data, _ := replies[0].([]byte)
+	if data == nil {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// SQLStore reads sessions kept in a database table by a custom
+// session_set_save_handler. Query selects the data column by id.
+type SQLStore struct {
+	DB    *sql.DB
+	Query string // 예: SELECT data FROM g5_session WHERE id = ?
+}
+
+// Read implements Store
+func (s SQLStore) Read(ctx context.Context, id string) ([]byte, error) {
+	if !ValidID(id) {
+		return nil, ErrNotFound
+	}
+	var data []byte
+	err := s.DB.QueryRowContext(ctx, s.Query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
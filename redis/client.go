@@ -0,0 +1,251 @@
+// Package redis is a small client for Redis (or a compatible server)
+// shared by the PHP session store and the hit counter. They need only a
+// few commands, so it speaks RESP directly instead of pulling in a library.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client sends commands over a small pool of connections, dropping a
+// connection after an error
+type Client struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+	// 동시에 여는 최대 연결 수 (기본 DefaultPoolSize)
+	PoolSize int
+
+	once sync.Once
+	sem  chan struct{} // 사용 중인 연결 수만큼 찬 슬롯
+	mu   sync.Mutex
+	idle []*conn
+}
+
+// DefaultPoolSize is the number of connections a Client opens at most by default
+const DefaultPoolSize = 10
+
+// conn is a pooled connection with its reader
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// ParseURL parses redis://[:password@]host:port[/db]. The query is
+// returned for the caller's own options (e.g. ?prefix=).
+func ParseURL(rawURL string) (*Client, url.Values, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "redis" || u.Host == "" {
+		return nil, nil, fmt.Errorf("redis: invalid url %q", rawURL)
+	}
+	c := &Client{Addr: u.Host, Timeout: 2 * time.Second}
+	if p, ok := u.User.Password(); ok {
+		c.Password = p
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if c.DB, err = strconv.Atoi(db); err != nil {
+			return nil, nil, fmt.Errorf("redis: invalid db %q", db)
+		}
+	}
+	return c, u.Query(), nil
+}
+
+// Do sends the commands in one round trip and returns their replies.
+// Each reply is string, []byte, int64, []interface{} or nil for a null reply.
+// An error reply is returned as Error after every reply has been read.
+func (c *Client) Do(ctx context.Context, cmds ...[]string) ([]interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		cn.SetDeadline(deadline)
+	} else {
+		cn.SetDeadline(time.Now().Add(c.Timeout))
+	}
+
+	replies, err := roundTrip(cn, cn.r, cmds)
+	var redisErr Error
+	// 응답을 끝까지 읽지 못했으면 연결을 버림
+	c.put(cn, err == nil || errors.As(err, &redisErr))
+	return replies, err
+}
+
+// Close closes the idle connections; the next Do connects again
+func (c *Client) Close() error {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle = nil
+	c.mu.Unlock()
+	var first error
+	for _, cn := range idle {
+		if err := cn.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// get takes an idle connection or dials one, waiting while PoolSize
+// connections are in use
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.once.Do(func() {
+		size := c.PoolSize
+		if size <= 0 {
+			size = DefaultPoolSize
+		}
+		c.sem = make(chan struct{}, size)
+	})
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	cn, err := c.connect(ctx)
+	if err != nil {
+		<-c.sem
+		return nil, err
+	}
+	return cn, nil
+}
+
+// put returns cn to the pool, or closes it if it is not reusable
+func (c *Client) put(cn *conn, reusable bool) {
+	if reusable {
+		c.mu.Lock()
+		c.idle = append(c.idle, cn)
+		c.mu.Unlock()
+	} else {
+		cn.Close()
+	}
+	<-c.sem
+}
+
+func (c *Client) connect(ctx context.Context) (*conn, error) {
+	dialer := net.Dialer{Timeout: c.Timeout}
+	nc, err := dialer.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	nc.SetDeadline(time.Now().Add(c.Timeout))
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc)}
+
+	var setup [][]string
+	if c.Password != "" {
+		setup = append(setup, []string{"AUTH", c.Password})
+	}
+	if c.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.DB)})
+	}
+	if len(setup) > 0 {
+		if _, err := roundTrip(cn, cn.r, setup); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// Error is an error reply (-ERR ...); the connection is still usable
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// roundTrip writes all commands, then reads one reply each. It returns
+// the first error reply after reading every reply.
+func roundTrip(conn net.Conn, r *bufio.Reader, cmds [][]string) ([]interface{}, error) {
+	var b strings.Builder
+	for _, args := range cmds {
+		fmt.Fprintf(&b, "*%d\r\n", len(args))
+		for _, a := range args {
+			fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+		}
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+	return readReplies(r, len(cmds))
+}
+
+// readReplies reads n replies, keeping the first error reply
+func readReplies(r *bufio.Reader, n int) ([]interface{}, error) {
+	list := make([]interface{}, n)
+	var first error
+	for i := range list {
+		v, err := readReply(r)
+		var redisErr Error
+		if err != nil && !errors.As(err, &redisErr) {
+			return nil, err
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+		list[i] = v
+	}
+	return list, first
+}
+
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		return readReplies(r, n)
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseURL(t *testing.T) {
+	c, query, err := ParseURL("redis://:secret@127.0.0.1:6379/3?prefix=sess:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Addr != "127.0.0.1:6379" || c.Password != "secret" || c.DB != 3 || query.Get("prefix") != "sess:" {
+		t.Errorf("client = %+v, query = %v", c, query)
+	}
+	for _, bad := range []string{"http://127.0.0.1:6379", "redis:///0", "redis://127.0.0.1:6379/x"} {
+		if _, _, err := ParseURL(bad); err == nil {
+			t.Errorf("ParseURL(%q) accepted", bad)
+		}
+	}
+}
+
+func TestReadReplies(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("+OK\r\n:7\r\n$3\r\nabc\r\n$-1\r\n*2\r\n$1\r\na\r\n-ERR no\r\n"))
+	replies, err := readReplies(r, 5)
+	var redisErr Error
+	if !errors.As(err, &redisErr) || redisErr != "ERR no" {
+		t.Errorf("err = %v", err)
+	}
+	want := []interface{}{"OK", int64(7), []byte("abc"), nil, []interface{}{[]byte("a"), nil}}
+	if !reflect.DeepEqual(replies, want) {
+		t.Errorf("replies = %#v", replies)
+	}
+}
+
+// 명령 두 개가 서로 다른 연결로 함께 도착해야 답하는 가짜 Redis
+func TestPool(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	var dialed int32
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			first := atomic.AddInt32(&dialed, 1) <= 2
+			go func(conn net.Conn, first bool) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					// *1 $4 PING
+					for i := 0; i < 3; i++ {
+						if _, err := r.ReadString('\n'); err != nil {
+							return
+						}
+					}
+					if first {
+						arrived.Done()
+						arrived.Wait()
+						first = false
+					}
+					conn.Write([]byte("+PONG\r\n"))
+				}
+			}(conn, first)
+		}
+	}()
+
+	c := &Client{Addr: ln.Addr().String(), Timeout: time.Second, PoolSize: 2}
+	defer c.Close()
+	ping := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		replies, err := c.Do(ctx, []string{"PING"})
+		if err == nil && replies[0] != "PONG" {
+			err = errors.New("reply " + replies[0].(string))
+		}
+		return err
+	}
+
+	// 연결 하나로 차례대로 보냈다면 첫 명령이 답을 받지 못함
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- ping() }()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("concurrent ping: %v", err)
+		}
+	}
+
+	// 이후 명령은 쉬는 연결을 다시 씀
+	for i := 0; i < 3; i++ {
+		if err := ping(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&dialed); n != 2 {
+		t.Errorf("dialed %d connections, want 2", n)
+	}
+}
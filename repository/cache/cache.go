@@ -0,0 +1,238 @@
+// Package cache keeps board lists, board counts, posts and comment lists
+// in memory in front of the repositories. Writes made through the wrapped
+// writers drop the board's entries; other changes (PHP Gnuboard on the
+// same DB) show up once the TTL passes.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// Store caches the reads of a PostRepository and a CommentRepository.
+// Methods that are not cached go straight to the wrapped repositories.
+type Store struct {
+	repository.PostRepository
+	repository.CommentRepository
+
+	lru *LRU
+
+	// 게시판마다 쓰기할 때 올리는 번호. 키에 들어가므로 올리면 그 게시판의 항목을 모두 버리는 셈
+	mu       sync.Mutex
+	versions map[string]uint64
+}
+
+// New wraps posts and comments with an LRU of at most maxEntries entries
+// that live for ttl
+func New(posts repository.PostRepository, comments repository.CommentRepository, maxEntries int, ttl time.Duration) *Store {
+	return &Store{
+		PostRepository:    posts,
+		CommentRepository: comments,
+		lru:               NewLRU(maxEntries, ttl),
+		versions:          map[string]uint64{},
+	}
+}
+
+// Stats returns the hit and miss counts
+func (s *Store) Stats() Stats {
+	return s.lru.Stats()
+}
+
+// Invalidate drops every cached entry of the board
+func (s *Store) Invalidate(table string) {
+	s.mu.Lock()
+	s.versions[table]++
+	s.mu.Unlock()
+}
+
+func (s *Store) key(table, kind string, parts ...interface{}) string {
+	s.mu.Lock()
+	v := s.versions[table]
+	s.mu.Unlock()
+	return fmt.Sprint(table, ":", v, ":", kind, parts)
+}
+
+// load runs fn through the LRU. 먼저 온 요청이 취소돼도 기다리는 요청은 결과를 받도록 취소를 떼어 냄
+func (s *Store) load(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return s.lru.Load(key, func() (interface{}, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+}
+
+// ListPosts implements repository.PostRepository
+func (s *Store) ListPosts(ctx context.Context, table string, opts repository.ListOptions) ([]models.Post, error) {
+	after := ""
+	if opts.After != nil {
+		after = opts.After.Encode()
+	}
+	v, err := s.load(ctx, s.key(table, "posts", opts.Offset, opts.Limit, after), func(ctx context.Context) (interface{}, error) {
+		return s.PostRepository.ListPosts(ctx, table, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 호출한 쪽이 고쳐도 캐시는 그대로 두도록 복사본을 돌려줌
+	return append([]models.Post(nil), v.([]models.Post)...), nil
+}
+
+// CountPosts implements repository.PostRepository
+func (s *Store) CountPosts(ctx context.Context, table string) (int, error) {
+	v, err := s.load(ctx, s.key(table, "count"), func(ctx context.Context) (interface{}, error) {
+		return s.PostRepository.CountPosts(ctx, table)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// MinNum implements repository.PostRepository
+func (s *Store) MinNum(ctx context.Context, table string) (int, error) {
+	v, err := s.load(ctx, s.key(table, "min_num"), func(ctx context.Context) (interface{}, error) {
+		return s.PostRepository.MinNum(ctx, table)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// GetPost implements repository.PostRepository
+func (s *Store) GetPost(ctx context.Context, table string, id int) (*models.Post, error) {
+	v, err := s.load(ctx, s.key(table, "post", id), func(ctx context.Context) (interface{}, error) {
+		p, err := s.PostRepository.GetPost(ctx, table, id)
+		if err != nil {
+			return nil, err
+		}
+		return *p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p := v.(models.Post)
+	return &p, nil
+}
+
+// AddHits implements repository.PostRepository. 조회수만 바뀌므로 목록은 TTL 까지 두고
+// 캐시된 글의 wr_hit 만 올림
+func (s *Store) AddHits(ctx context.Context, table string, hits map[int]int) error {
+	if err := s.PostRepository.AddHits(ctx, table, hits); err != nil {
+		return err
+	}
+	for id, n := range hits {
+		s.lru.Update(s.key(table, "post", id), func(v interface{}) interface{} {
+			p := v.(models.Post)
+			p.Hit += n
+			return p
+		})
+	}
+	return nil
+}
+
+// ListComments implements repository.CommentRepository
+func (s *Store) ListComments(ctx context.Context, table string, postID int, opts repository.ListOptions) ([]models.Comment, error) {
+	v, err := s.load(ctx, s.key(table, "comments", postID, opts.Offset, opts.Limit), func(ctx context.Context) (interface{}, error) {
+		return s.CommentRepository.ListComments(ctx, table, postID, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.Comment(nil), v.([]models.Comment)...), nil
+}
+
+// CountComments implements repository.CommentRepository
+func (s *Store) CountComments(ctx context.Context, table string, postID int) (int, error) {
+	v, err := s.load(ctx, s.key(table, "comment_count", postID), func(ctx context.Context) (interface{}, error) {
+		return s.CommentRepository.CountComments(ctx, table, postID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int), nil
+}
+
+// Writer returns w that also drops the board's cache after each change
+func (s *Store) Writer(w repository.PostWriter) repository.PostWriter {
+	return postWriter{PostWriter: w, s: s}
+}
+
+// CommentWriter returns w that also drops the board's cache after each change
+func (s *Store) CommentWriter(w repository.CommentWriter) repository.CommentWriter {
+	return commentWriter{CommentWriter: w, s: s}
+}
+
+// Votes returns v that drops the board's cache after a vote (wr_good, wr_nogood)
+func (s *Store) Votes(v repository.VoteRepository) repository.VoteRepository {
+	return votes{VoteRepository: v, s: s}
+}
+
+// FileWriter returns w that drops the board's cache after an upload (wr_file)
+func (s *Store) FileWriter(w repository.FileWriter) repository.FileWriter {
+	return fileWriter{FileWriter: w, s: s}
+}
+
+type postWriter struct {
+	repository.PostWriter
+	s *Store
+}
+
+func (w postWriter) CreatePost(ctx context.Context, bo board.Board, p *models.Post, parent *models.Post) error {
+	defer w.s.Invalidate(bo.Table)
+	return w.PostWriter.CreatePost(ctx, bo, p, parent)
+}
+
+func (w postWriter) UpdatePost(ctx context.Context, table string, p models.Post) error {
+	defer w.s.Invalidate(table)
+	return w.PostWriter.UpdatePost(ctx, table, p)
+}
+
+func (w postWriter) DeletePost(ctx context.Context, table string, p models.Post) error {
+	defer w.s.Invalidate(table)
+	return w.PostWriter.DeletePost(ctx, table, p)
+}
+
+type commentWriter struct {
+	repository.CommentWriter
+	s *Store
+}
+
+func (w commentWriter) CreateComment(ctx context.Context, bo board.Board, post models.Post, c *models.Comment, parent *models.Comment) error {
+	defer w.s.Invalidate(bo.Table)
+	return w.CommentWriter.CreateComment(ctx, bo, post, c, parent)
+}
+
+func (w commentWriter) UpdateComment(ctx context.Context, table string, c models.Comment) error {
+	defer w.s.Invalidate(table)
+	return w.CommentWriter.UpdateComment(ctx, table, c)
+}
+
+func (w commentWriter) DeleteComment(ctx context.Context, table string, c models.Comment) error {
+	defer w.s.Invalidate(table)
+	return w.CommentWriter.DeleteComment(ctx, table, c)
+}
+
+type votes struct {
+	repository.VoteRepository
+	s *Store
+}
+
+func (v votes) Vote(ctx context.Context, table string, postID int, mbID, flag string) (int, int, error) {
+	defer v.s.Invalidate(table)
+	return v.VoteRepository.Vote(ctx, table, postID, mbID, flag)
+}
+
+type fileWriter struct {
+	repository.FileWriter
+	s *Store
+}
+
+func (w fileWriter) AddFiles(ctx context.Context, bo board.Board, postID int, files []*models.BoardFile) error {
+	defer w.s.Invalidate(bo.Table)
+	return w.FileWriter.AddFiles(ctx, bo, postID, files)
+}
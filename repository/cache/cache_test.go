@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+	"fibergo/repository/memory"
+)
+
+func TestLRU(t *testing.T) {
+	c := NewLRU(2, time.Hour)
+	loads := 0
+	load := func(v string) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			loads++
+			return v, nil
+		}
+	}
+	c.Load("a", load("a"))
+	c.Load("b", load("b"))
+	c.Load("a", load("a")) // a 를 최근으로
+	c.Load("c", load("c")) // b 를 밀어냄
+	if v, _ := c.Load("a", load("x")); v != "a" {
+		t.Errorf("a = %v", v)
+	}
+	if v, _ := c.Load("b", load("b2")); v != "b2" {
+		t.Errorf("b = %v, want reloaded", v)
+	}
+	want := Stats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}
+	if got := c.Stats(); got != want || loads != 4 {
+		t.Errorf("stats = %+v, loads = %d", got, loads)
+	}
+
+	// 오류는 캐시하지 않음
+	fail := errors.New("db")
+	if _, err := c.Load("e", func() (interface{}, error) { return nil, fail }); err != fail {
+		t.Errorf("err = %v", err)
+	}
+	if v, _ := c.Load("e", load("e")); v != "e" {
+		t.Errorf("e = %v", v)
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	c := NewLRU(10, 20*time.Millisecond)
+	c.Load("a", func() (interface{}, error) { return 1, nil })
+	time.Sleep(30 * time.Millisecond)
+	if v, _ := c.Load("a", func() (interface{}, error) { return 2, nil }); v != 2 {
+		t.Errorf("a = %v, want reloaded", v)
+	}
+}
+
+func TestLRUSingleflight(t *testing.T) {
+	c := NewLRU(10, time.Hour)
+	var calls int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.Load("k", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return 42, nil
+			})
+			if v != 42 || err != nil {
+				t.Errorf("Load = %v, %v", v, err)
+			}
+		}()
+	}
+	// 모두 기다리기 시작할 때까지
+	for c.Stats().Shared < 9 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("loads = %d, want 1", calls)
+	}
+
+	func() {
+		defer func() { recover() }()
+		c.Load("p", func() (interface{}, error) { panic("boom") })
+	}()
+	if v, err := c.Load("p", func() (interface{}, error) { return 1, nil }); v != 1 || err != nil {
+		t.Errorf("after panic = %v, %v", v, err)
+	}
+}
+
+// DB 조회 횟수를 세는 저장소
+type countingRepo struct {
+	*memory.Store
+	counts int32
+}
+
+func (r *countingRepo) CountPosts(ctx context.Context, table string) (int, error) {
+	atomic.AddInt32(&r.counts, 1)
+	return r.Store.CountPosts(ctx, table)
+}
+
+func TestStoreInvalidation(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.New()
+	bo := board.Board{Table: "free", Subject: "자유게시판"}
+	mem.AddBoard(bo)
+	mem.AddPost("free", models.Post{ID: 1, Num: -1, Subject: "첫 글", MemberID: "user1", Hit: 3})
+	mem.AddMember(models.Member{ID: "user2", Level: 2})
+	repo := &countingRepo{Store: mem}
+	s := New(repo, mem, 100, time.Hour)
+	writer := s.Writer(mem)
+
+	for i := 0; i < 3; i++ {
+		if n, _ := s.CountPosts(ctx, "free"); n != 1 {
+			t.Fatalf("count = %d", n)
+		}
+	}
+	if repo.counts != 1 {
+		t.Errorf("CountPosts queries = %d, want 1", repo.counts)
+	}
+
+	// 돌려준 글을 고쳐도 캐시는 그대로
+	p, _ := s.GetPost(ctx, "free", 1)
+	p.Subject = "바뀜"
+	list, _ := s.ListPosts(ctx, "free", repository.ListOptions{Limit: 10})
+	list[0].Subject = "바뀜"
+	if p, _ := s.GetPost(ctx, "free", 1); p.Subject != "첫 글" {
+		t.Errorf("cached post changed: %q", p.Subject)
+	}
+	if list, _ := s.ListPosts(ctx, "free", repository.ListOptions{Limit: 10}); list[0].Subject != "첫 글" {
+		t.Errorf("cached list changed: %q", list[0].Subject)
+	}
+
+	// 조회수는 캐시된 글에 더함
+	s.AddHits(ctx, "free", map[int]int{1: 2})
+	if p, _ := s.GetPost(ctx, "free", 1); p.Hit != 5 {
+		t.Errorf("hit = %d, want 5", p.Hit)
+	}
+
+	// 글쓰기, 추천은 그 게시판을 다시 읽게 함
+	if err := writer.CreatePost(ctx, bo, &models.Post{Subject: "둘째 글", MemberID: "user1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.CountPosts(ctx, "free"); n != 2 || repo.counts != 2 {
+		t.Errorf("after write count = %d, queries = %d", n, repo.counts)
+	}
+	if _, _, err := s.Votes(mem).Vote(ctx, "free", 1, "user2", repository.VoteGood); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := s.GetPost(ctx, "free", 1); p.Good != 1 {
+		t.Errorf("good = %d, want 1", p.Good)
+	}
+}
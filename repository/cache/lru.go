@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 조회 함수가 panic 하면 기다리던 요청이 받는 오류
+var errLoadPanicked = errors.New("cache: load panicked")
+
+// Stats are the counters of an LRU since it was created
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Shared    uint64 `json:"shared"` // 다른 요청의 조회 결과를 기다려 받은 횟수
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// LRU is a size-limited cache whose entries expire after a TTL. Load
+// runs one loader per missing key however many callers ask for it.
+type LRU struct {
+	maxEntries int
+	ttl        time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List // 앞쪽이 최근에 사용한 항목
+	items map[string]*list.Element
+	calls map[string]*call
+	stats Stats
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// 진행 중인 조회 (singleflight)
+type call struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// NewLRU creates a cache of at most maxEntries entries that live for ttl
+func NewLRU(maxEntries int, ttl time.Duration) *LRU {
+	return &LRU{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		ll:         list.New(),
+		items:      map[string]*list.Element{},
+		calls:      map[string]*call{},
+	}
+}
+
+// Load returns the cached value of key, or calls load once and caches a
+// successful result. Concurrent callers of a missing key wait for the
+// same load.
+func (c *LRU) Load(key string, load func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if v, ok := c.get(key); ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return v, nil
+	}
+	if cl, ok := c.calls[key]; ok {
+		c.stats.Shared++
+		c.mu.Unlock()
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+	c.stats.Misses++
+	cl := &call{}
+	cl.wg.Add(1)
+	c.calls[key] = cl
+	c.mu.Unlock()
+
+	// load 가 panic 해도 기다리는 요청을 풀어줌
+	loaded := false
+	defer func() {
+		if !loaded {
+			cl.err = errLoadPanicked
+		}
+		c.mu.Lock()
+		if cl.err == nil {
+			c.set(key, cl.value)
+		}
+		delete(c.calls, key)
+		c.mu.Unlock()
+		cl.wg.Done()
+	}()
+	cl.value, cl.err = load()
+	loaded = true
+	return cl.value, cl.err
+}
+
+// Update replaces the value of key with fn(value) if it is cached
+func (c *LRU) Update(key string, fn func(interface{}) interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value = fn(e.value)
+	}
+}
+
+// Stats returns the current counters
+func (c *LRU) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Entries = c.ll.Len()
+	return s
+}
+
+// get returns a live entry and marks it used; c.mu must be held
+func (c *LRU) get(key string) (interface{}, bool) {
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !time.Now().Before(e.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// set adds or replaces key, evicting the least recently used entries; c.mu must be held
+func (c *LRU) set(key string, value interface{}) {
+	expires := time.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expires = value, expires
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry{key: key, value: value, expires: expires})
+	for c.ll.Len() > c.maxEntries {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*entry).key)
+		c.stats.Evictions++
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a cursor string cannot be decoded
+var ErrInvalidCursor = errors.New("repository: invalid cursor")
+
+// Cursor is a position in the (wr_num, wr_reply) list order
+type Cursor struct {
+	Num   int
+	Reply string
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(c.Num) + ":" + c.Reply))
+}
+
+// DecodeCursor parses a string produced by Cursor.Encode
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	num, reply, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{Num: n, Reply: reply}, nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// CreateComment implements repository.CommentWriter
+func (s *Store) CreateComment(ctx context.Context, bo board.Board, post models.Post, c *models.Comment, parent *models.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parent == nil {
+		num := 0
+		for _, q := range s.comments[bo.Table] {
+			if q.Parent == post.ID {
+				num = max(num, q.Comment)
+			}
+		}
+		c.Comment, c.CommentReply = num+1, ""
+	} else {
+		n := len(parent.CommentReply) + 1
+		last := ""
+		for _, q := range s.comments[bo.Table] {
+			if q.Parent == post.ID && q.Comment == parent.Comment && len(q.CommentReply) >= n &&
+				strings.HasPrefix(q.CommentReply, parent.CommentReply) {
+				last = max(last, q.CommentReply[n-1:n])
+			}
+		}
+		reply, err := repository.NextCommentReply(parent.CommentReply, last)
+		if err != nil {
+			return err
+		}
+		c.Comment, c.CommentReply, c.ReplyTo = parent.Comment, reply, parent.ID
+	}
+
+	if c.Datetime.IsZero() {
+		c.Datetime = time.Now()
+	}
+	c.ID = s.nextID(bo.Table)
+	c.Parent = post.ID
+	cm := *c
+	s.comments[bo.Table] = append(s.comments[bo.Table], &cm)
+	if p := s.findPost(bo.Table, post.ID); p != nil {
+		p.CommentCount++
+		p.Last = c.Datetime.Format("2006-01-02 15:04:05")
+	}
+
+	if pt, ok := repository.BoardPoint(bo, models.Point{
+		MemberID:  c.MemberID,
+		Datetime:  c.Datetime,
+		Content:   fmt.Sprintf("%s %d-%d 댓글쓰기", bo.Subject, post.ID, c.ID),
+		Point:     bo.CommentPoint,
+		RelTable:  bo.Table,
+		RelID:     strconv.Itoa(c.ID),
+		RelAction: "댓글",
+	}); ok {
+		s.insertPoint(pt)
+	}
+	return nil
+}
+
+// UpdateComment implements repository.CommentWriter
+func (s *Store) UpdateComment(ctx context.Context, table string, c models.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.findComment(table, c.ID)
+	if q == nil {
+		return repository.ErrNotFound
+	}
+	q.Option, q.Content = c.Option, c.Content
+	return nil
+}
+
+// DeleteComment implements repository.CommentWriter
+func (s *Store) DeleteComment(ctx context.Context, table string, c models.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deletePoint(c.MemberID, table, strconv.Itoa(c.ID), "댓글")
+	var kept []*models.Comment
+	for _, q := range s.comments[table] {
+		if q.ID != c.ID {
+			kept = append(kept, q)
+		}
+	}
+	s.comments[table] = kept
+
+	// wr_last 는 남은 글과 댓글 중 가장 최근 시각
+	if p := s.findPost(table, c.Parent); p != nil {
+		last := p.Datetime
+		for _, q := range kept {
+			if q.Parent == p.ID && q.Datetime.After(last) {
+				last = q.Datetime
+			}
+		}
+		p.CommentCount--
+		p.Last = last.Format("2006-01-02 15:04:05")
+	}
+	return nil
+}
+
+// CountCommentReplies implements repository.CommentWriter
+func (s *Store) CountCommentReplies(ctx context.Context, table string, c models.Comment) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, q := range s.comments[table] {
+		if q.Parent == c.Parent && q.Comment == c.Comment && q.ID != c.ID && strings.HasPrefix(q.CommentReply, c.CommentReply) {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// AddFile stores a g5_board_file row
+func (s *Store) AddFile(f models.BoardFile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files = append(s.files, &f)
+}
+
+func (s *Store) findFile(table string, postID, no int) *models.BoardFile {
+	for _, f := range s.files {
+		if f.Table == table && f.PostID == postID && f.No == no && f.File != "" {
+			return f
+		}
+	}
+	return nil
+}
+
+// ListFiles implements repository.FileRepository
+func (s *Store) ListFiles(ctx context.Context, table string, postID int) ([]models.BoardFile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []models.BoardFile
+	for _, f := range s.files {
+		if f.Table == table && f.PostID == postID && f.File != "" {
+			list = append(list, *f)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].No < list[j].No })
+	return list, nil
+}
+
+// ListPostFiles implements repository.FileRepository
+func (s *Store) ListPostFiles(ctx context.Context, table string, postIDs []int) (map[int][]models.BoardFile, error) {
+	files := make(map[int][]models.BoardFile, len(postIDs))
+	for _, id := range postIDs {
+		list, err := s.ListFiles(ctx, table, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) > 0 {
+			files[id] = list
+		}
+	}
+	return files, nil
+}
+
+// GetFile implements repository.FileRepository
+func (s *Store) GetFile(ctx context.Context, table string, postID, no int) (*models.BoardFile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f := s.findFile(table, postID, no)
+	if f == nil {
+		return nil, repository.ErrNotFound
+	}
+	file := *f
+	return &file, nil
+}
+
+// IncrementDownload implements repository.FileRepository
+func (s *Store) IncrementDownload(ctx context.Context, table string, postID, no int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f := s.findFile(table, postID, no); f != nil {
+		f.Download++
+	}
+	return nil
+}
+
+// AddFiles implements repository.FileWriter
+func (s *Store) AddFiles(ctx context.Context, bo board.Board, postID int, files []*models.BoardFile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPost(bo.Table, postID)
+	if p == nil {
+		return repository.ErrNotFound
+	}
+	used := map[int]bool{}
+	for _, f := range s.files {
+		if f.Table == bo.Table && f.PostID == postID && f.File != "" {
+			used[f.No] = true
+		}
+	}
+	if len(used)+len(files) > bo.UploadCount {
+		return repository.ErrTooManyFiles
+	}
+
+	no := 0
+	for _, f := range files {
+		for used[no] {
+			no++
+		}
+		used[no] = true
+		f.Table, f.PostID, f.No, f.Download = bo.Table, postID, no, 0
+		file := *f
+		s.files = append(s.files, &file)
+	}
+	p.File = len(used)
+	return nil
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+)
+
+// 픽스처 날짜 형식 (그누보드 DATETIME 과 동일)
+const fixtureTimeFormat = "2006-01-02 15:04:05"
+
+// Fixture is a JSON seed of boards, posts, comments, members, votes and files.
+// Posts and comments are keyed by bo_table and use Gnuboard column names.
+type Fixture struct {
+	Config   FixtureConfig               `json:"config"`
+	Boards   []board.Board               `json:"boards"`
+	Posts    map[string][]FixturePost    `json:"posts"`
+	Comments map[string][]FixtureComment `json:"comments"`
+	Members  []FixtureMember             `json:"members"`
+	Votes    []FixtureVote               `json:"board_good"`
+	Files    []FixtureFile               `json:"board_file"`
+}
+
+// FixtureConfig is the g5_config row
+type FixtureConfig struct {
+	UsePoint  bool `json:"cf_use_point"`
+	PointTerm int  `json:"cf_point_term"`
+}
+
+// FixturePost is a g5_write_* post row
+type FixturePost struct {
+	ID           int    `json:"wr_id"`
+	Num          int    `json:"wr_num"`
+	Reply        string `json:"wr_reply"`
+	Parent       int    `json:"wr_parent"`
+	CommentCount int    `json:"wr_comment"`
+	CategoryName string `json:"ca_name"`
+	Option       string `json:"wr_option"`
+	Subject      string `json:"wr_subject"`
+	Content      string `json:"wr_content"`
+	Link1        string `json:"wr_link1"`
+	Link2        string `json:"wr_link2"`
+	Hit          int    `json:"wr_hit"`
+	Good         int    `json:"wr_good"`
+	Nogood       int    `json:"wr_nogood"`
+	MemberID     string `json:"mb_id"`
+	Password     string `json:"wr_password"`
+	Name         string `json:"wr_name"`
+	Email        string `json:"wr_email"`
+	Datetime     string `json:"wr_datetime"`
+	File         int    `json:"wr_file"`
+	IP           string `json:"wr_ip"`
+}
+
+// FixtureComment is a g5_write_* comment row
+type FixtureComment struct {
+	ID           int    `json:"wr_id"`
+	Parent       int    `json:"wr_parent"`
+	Comment      int    `json:"wr_comment"`
+	CommentReply string `json:"wr_comment_reply"`
+	Option       string `json:"wr_option"`
+	Content      string `json:"wr_content"`
+	MemberID     string `json:"mb_id"`
+	Password     string `json:"wr_password"`
+	Name         string `json:"wr_name"`
+	Datetime     string `json:"wr_datetime"`
+	IP           string `json:"wr_ip"`
+}
+
+// FixtureMember is a g5_member row
+type FixtureMember struct {
+	ID            string `json:"mb_id"`
+	Password      string `json:"mb_password"`
+	Name          string `json:"mb_name"`
+	Nick          string `json:"mb_nick"`
+	Email         string `json:"mb_email"`
+	Level         int    `json:"mb_level"`
+	Point         int    `json:"mb_point"`
+	Datetime      string `json:"mb_datetime"`
+	LeaveDate     string `json:"mb_leave_date"`
+	InterceptDate string `json:"mb_intercept_date"`
+}
+
+// FixtureVote is a g5_board_good row
+type FixtureVote struct {
+	Table    string `json:"bo_table"`
+	PostID   int    `json:"wr_id"`
+	MemberID string `json:"mb_id"`
+	Flag     string `json:"bg_flag"`
+	Datetime string `json:"bg_datetime"`
+}
+
+// FixtureFile is a g5_board_file row
+type FixtureFile struct {
+	Table    string `json:"bo_table"`
+	PostID   int    `json:"wr_id"`
+	No       int    `json:"bf_no"`
+	Source   string `json:"bf_source"`
+	File     string `json:"bf_file"`
+	Download int    `json:"bf_download"`
+	Content  string `json:"bf_content"`
+	Filesize int64  `json:"bf_filesize"`
+	Width    int    `json:"bf_width"`
+	Height   int    `json:"bf_height"`
+	Type     int    `json:"bf_type"`
+	Datetime string `json:"bf_datetime"`
+}
+
+// LoadFixture reads a JSON fixture file into a new store
+func LoadFixture(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	s := New()
+	if err := s.Seed(f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Seed adds every row of the fixture to the store
+func (s *Store) Seed(f Fixture) error {
+	s.SetConfig(f.Config)
+	for _, b := range f.Boards {
+		s.AddBoard(b)
+	}
+	for table, list := range f.Posts {
+		for _, p := range list {
+			t, err := parseFixtureTime(p.Datetime)
+			if err != nil {
+				return err
+			}
+			s.AddPost(table, models.Post{
+				ID: p.ID, Num: p.Num, Reply: p.Reply, Parent: p.Parent, CommentCount: p.CommentCount,
+				CategoryName: p.CategoryName, Option: p.Option, Subject: p.Subject, Content: p.Content,
+				Link1: p.Link1, Link2: p.Link2, Hit: p.Hit, Good: p.Good, Nogood: p.Nogood,
+				MemberID: p.MemberID, Password: p.Password, Name: p.Name, Email: p.Email,
+				Datetime: t, File: p.File, IP: p.IP,
+			})
+		}
+	}
+	for table, list := range f.Comments {
+		for _, c := range list {
+			t, err := parseFixtureTime(c.Datetime)
+			if err != nil {
+				return err
+			}
+			s.AddComment(table, models.Comment{
+				ID: c.ID, Parent: c.Parent, Comment: c.Comment, CommentReply: c.CommentReply,
+				Option: c.Option, Content: c.Content, MemberID: c.MemberID, Password: c.Password,
+				Name: c.Name, Datetime: t, IP: c.IP,
+			})
+		}
+	}
+	for _, m := range f.Members {
+		t, err := parseFixtureTime(m.Datetime)
+		if err != nil {
+			return err
+		}
+		s.AddMember(models.Member{
+			ID: m.ID, Password: m.Password, Name: m.Name, Nick: m.Nick, Email: m.Email,
+			Level: m.Level, Point: m.Point, Datetime: t,
+			LeaveDate: m.LeaveDate, InterceptDate: m.InterceptDate,
+		})
+	}
+	for _, v := range f.Votes {
+		t, err := parseFixtureTime(v.Datetime)
+		if err != nil {
+			return err
+		}
+		s.AddVote(models.BoardGood{Table: v.Table, PostID: v.PostID, MemberID: v.MemberID, Flag: v.Flag, Datetime: t})
+	}
+	for _, bf := range f.Files {
+		t, err := parseFixtureTime(bf.Datetime)
+		if err != nil {
+			return err
+		}
+		s.AddFile(models.BoardFile{
+			Table: bf.Table, PostID: bf.PostID, No: bf.No, Source: bf.Source, File: bf.File,
+			Download: bf.Download, Content: bf.Content, Filesize: bf.Filesize,
+			Width: bf.Width, Height: bf.Height, Type: bf.Type, Datetime: t,
+		})
+	}
+	return nil
+}
+
+func parseFixtureTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(fixtureTimeFormat, value, time.Local)
+}
@@ -0,0 +1,437 @@
+// Package memory is an in-memory implementation of the repositories,
+// used by tests in place of a Gnuboard MySQL database.
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// Store holds boards, posts, comments and members in memory
+type Store struct {
+	mu       sync.RWMutex
+	config   FixtureConfig
+	boards   []board.Board
+	posts    map[string][]*models.Post
+	comments map[string][]*models.Comment
+	members  map[string]*models.Member
+	revoked  map[string]time.Time
+	points   []models.Point
+	pointSeq int
+	votes    []models.BoardGood
+	files    []*models.BoardFile
+}
+
+// New creates an empty store
+func New() *Store {
+	return &Store{
+		posts:    map[string][]*models.Post{},
+		comments: map[string][]*models.Comment{},
+		members:  map[string]*models.Member{},
+		revoked:  map[string]time.Time{},
+	}
+}
+
+// AddBoard registers a board
+func (s *Store) AddBoard(b board.Board) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boards = append(s.boards, b)
+}
+
+// SetConfig replaces the g5_config settings that LoadBoards copies into each board
+func (s *Store) SetConfig(cfg FixtureConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = cfg
+}
+
+// AddPost stores a post of the board table
+func (s *Store) AddPost(table string, p models.Post) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Parent == 0 {
+		p.Parent = p.ID
+	}
+	s.posts[table] = append(s.posts[table], &p)
+}
+
+// AddComment stores a comment of the board table
+func (s *Store) AddComment(table string, c models.Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.comments[table] = append(s.comments[table], &c)
+}
+
+// AddMember stores a member
+func (s *Store) AddMember(m models.Member) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members[m.ID] = &m
+}
+
+// LoadBoards implements board.Source
+func (s *Store) LoadBoards(ctx context.Context) ([]board.Board, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]board.Board, 0, len(s.boards))
+	for _, b := range s.boards {
+		b.UsePoint, b.PointTerm = s.config.UsePoint, s.config.PointTerm
+		list = append(list, b)
+	}
+	return list, nil
+}
+
+// 그누보드 기본 정렬 (wr_num, wr_reply)
+func (s *Store) sortedPosts(table string) []*models.Post {
+	list := append([]*models.Post(nil), s.posts[table]...)
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Num != list[j].Num {
+			return list[i].Num < list[j].Num
+		}
+		return list[i].Reply < list[j].Reply
+	})
+	return list
+}
+
+func (s *Store) findPost(table string, id int) *models.Post {
+	for _, p := range s.posts[table] {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// ListPosts implements repository.PostRepository
+func (s *Store) ListPosts(ctx context.Context, table string, opts repository.ListOptions) ([]models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Post
+	for i, p := range s.sortedPosts(table) {
+		if opts.After != nil {
+			if p.Num < opts.After.Num || (p.Num == opts.After.Num && p.Reply <= opts.After.Reply) {
+				continue
+			}
+		} else if i < opts.Offset {
+			continue
+		}
+		if len(result) >= opts.Limit {
+			break
+		}
+		result = append(result, summary(*p))
+	}
+	return result, nil
+}
+
+// summary drops wr_content and wr_password like the MySQL list queries
+func summary(p models.Post) models.Post {
+	p.Content, p.Password = "", ""
+	return p
+}
+
+// PostContents implements repository.PostRepository
+func (s *Store) PostContents(ctx context.Context, table string, ids []int) (map[int]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	contents := make(map[int]string, len(ids))
+	for _, id := range ids {
+		if p := s.findPost(table, id); p != nil {
+			contents[id] = p.Content
+		}
+	}
+	return contents, nil
+}
+
+// CountPosts implements repository.PostRepository
+func (s *Store) CountPosts(ctx context.Context, table string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.posts[table]), nil
+}
+
+// GetPost implements repository.PostRepository
+func (s *Store) GetPost(ctx context.Context, table string, id int) (*models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p := s.findPost(table, id)
+	if p == nil {
+		return nil, repository.ErrNotFound
+	}
+	post := *p
+	return &post, nil
+}
+
+// ThreadOrigin implements repository.PostRepository
+func (s *Store) ThreadOrigin(ctx context.Context, table string, num int) (*models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, p := range s.posts[table] {
+		if p.Num == num && p.Reply == "" {
+			origin := summary(*p)
+			return &origin, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// AddHits implements repository.PostRepository
+func (s *Store) AddHits(ctx context.Context, table string, hits map[int]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, n := range hits {
+		if p := s.findPost(table, id); p != nil {
+			p.Hit += n
+		}
+	}
+	return nil
+}
+
+// ListComments implements repository.CommentRepository
+func (s *Store) ListComments(ctx context.Context, table string, postID int, opts repository.ListOptions) ([]models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []models.Comment
+	for _, c := range s.comments[table] {
+		if c.Parent == postID {
+			result = append(result, *c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Comment != result[j].Comment {
+			return result[i].Comment < result[j].Comment
+		}
+		return result[i].CommentReply < result[j].CommentReply
+	})
+
+	type replyKey struct {
+		comment int
+		reply   string
+	}
+	ids := map[replyKey]int{}
+	for i, c := range result {
+		ids[replyKey{c.Comment, c.CommentReply}] = c.ID
+		if c.CommentReply != "" {
+			result[i].ReplyTo = ids[replyKey{c.Comment, c.ParentReply()}]
+		}
+	}
+
+	if opts.Offset >= len(result) {
+		return nil, nil
+	}
+	result = result[opts.Offset:]
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	return result, nil
+}
+
+// GetComment implements repository.CommentRepository
+func (s *Store) GetComment(ctx context.Context, table string, id int) (*models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := s.findComment(table, id)
+	if c == nil {
+		return nil, repository.ErrNotFound
+	}
+	cm := *c
+	if cm.CommentReply != "" {
+		for _, p := range s.comments[table] {
+			if p.Parent == cm.Parent && p.Comment == cm.Comment && p.CommentReply == cm.ParentReply() {
+				cm.ReplyTo = p.ID
+			}
+		}
+	}
+	return &cm, nil
+}
+
+func (s *Store) findComment(table string, id int) *models.Comment {
+	for _, c := range s.comments[table] {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// CountComments implements repository.CommentRepository
+func (s *Store) CountComments(ctx context.Context, table string, postID int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, c := range s.comments[table] {
+		if c.Parent == postID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MinNum implements repository.PostRepository
+func (s *Store) MinNum(ctx context.Context, table string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	min := 0
+	for i, p := range s.posts[table] {
+		if i == 0 || p.Num < min {
+			min = p.Num
+		}
+	}
+	return min, nil
+}
+
+func (s *Store) searchMatches(table string, opts repository.SearchOptions) []models.Post {
+	var result []models.Post
+	for _, p := range s.sortedPosts(table) {
+		if p.Num < opts.SegmentStart || p.Num >= opts.SegmentStart+opts.SegmentSize {
+			continue
+		}
+		post := p
+		if opts.Query.Match(func(column string) string { return postColumn(post, column) }) {
+			result = append(result, *p)
+		}
+	}
+	return result
+}
+
+func postColumn(p *models.Post, column string) string {
+	switch column {
+	case "wr_subject":
+		return p.Subject
+	case "wr_content":
+		return p.Content
+	case "wr_name":
+		return p.Name
+	case "mb_id":
+		return p.MemberID
+	}
+	return ""
+}
+
+// SearchPosts implements repository.PostRepository
+func (s *Store) SearchPosts(ctx context.Context, table string, opts repository.SearchOptions) ([]models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	matches := s.searchMatches(table, opts)
+	if opts.Offset >= len(matches) {
+		return nil, nil
+	}
+	matches = matches[opts.Offset:]
+	if len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+	return matches, nil
+}
+
+// CountSearch implements repository.PostRepository
+func (s *Store) CountSearch(ctx context.Context, table string, opts repository.SearchOptions) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.searchMatches(table, opts)), nil
+}
+
+// LatestPosts implements repository.PostRepository
+func (s *Store) LatestPosts(ctx context.Context, table string, limit int) ([]models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]models.Post, 0, len(s.posts[table]))
+	for _, p := range s.posts[table] {
+		list = append(list, summary(*p))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID > list[j].ID })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// GetPosts implements repository.PostRepository
+func (s *Store) GetPosts(ctx context.Context, table string, ids []int) ([]models.Post, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []models.Post
+	for _, id := range ids {
+		if p := s.findPost(table, id); p != nil {
+			list = append(list, summary(*p))
+		}
+	}
+	return list, nil
+}
+
+// RecentPosts implements repository.BoardNewRepository. 메모리 저장소는
+// 모든 글이 g5_board_new 에 있는 것으로 보고 작성 시각 순으로 돌려줌
+func (s *Store) RecentPosts(ctx context.Context, tables []string, offset, limit int) ([]repository.BoardNew, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []repository.BoardNew
+	for _, t := range tables {
+		for _, p := range s.posts[t] {
+			list = append(list, repository.BoardNew{Table: t, PostID: p.ID, Datetime: p.Datetime})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Datetime.After(list[j].Datetime) })
+	for i := range list {
+		list[i].ID = len(list) - i
+	}
+	if offset >= len(list) {
+		return nil, nil
+	}
+	list = list[offset:]
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// GetMember implements repository.MemberRepository
+func (s *Store) GetMember(ctx context.Context, id string) (*models.Member, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.members[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	member := *m
+	return &member, nil
+}
+
+// RevokeToken implements repository.TokenDenylist
+func (s *Store) RevokeToken(ctx context.Context, jti, mbID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.revoked[jti]; ok {
+		return repository.ErrTokenRevoked
+	}
+	s.revoked[jti] = expiresAt
+	return nil
+}
+
+// PurgeTokens implements repository.TokenDenylist
+func (s *Store) PurgeTokens(ctx context.Context, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for jti, exp := range s.revoked {
+		if exp.Before(t) {
+			delete(s.revoked, jti)
+		}
+	}
+	return nil
+}
+
+// IsTokenRevoked implements repository.TokenDenylist
+func (s *Store) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.revoked[jti]
+	return ok, nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// ChargePoint implements repository.PointRepository
+func (s *Store) ChargePoint(ctx context.Context, pt models.Point) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := s.members[pt.MemberID]
+	if pt.Point == 0 || m == nil || s.pointCharged(pt) {
+		return nil
+	}
+	if pt.Point < 0 && m.Point+pt.Point < 0 {
+		return repository.ErrInsufficientPoints
+	}
+	s.insertPoint(pt)
+	return nil
+}
+
+// ListPoints implements repository.PointRepository
+func (s *Store) ListPoints(ctx context.Context, mbID string, offset, limit int) ([]models.Point, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := []models.Point{}
+	for i := len(s.points) - 1; i >= 0; i-- {
+		if s.points[i].MemberID == mbID {
+			list = append(list, s.points[i])
+		}
+	}
+	if offset >= len(list) {
+		return []models.Point{}, nil
+	}
+	list = list[offset:]
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// CountPoints implements repository.PointRepository
+func (s *Store) CountPoints(ctx context.Context, mbID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, q := range s.points {
+		if q.MemberID == mbID {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// AddVote stores a g5_board_good row
+func (s *Store) AddVote(g models.BoardGood) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g.ID = len(s.votes) + 1
+	s.votes = append(s.votes, g)
+}
+
+func (s *Store) findVote(table string, postID int, mbID string) string {
+	for _, g := range s.votes {
+		if g.Table == table && g.PostID == postID && g.MemberID == mbID {
+			return g.Flag
+		}
+	}
+	return ""
+}
+
+// Vote implements repository.VoteRepository
+func (s *Store) Vote(ctx context.Context, table string, postID int, mbID, flag string) (int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.findPost(table, postID)
+	if p == nil {
+		return 0, 0, repository.ErrNotFound
+	}
+	if s.findVote(table, postID, mbID) != "" {
+		return p.Good, p.Nogood, repository.ErrAlreadyVoted
+	}
+	if flag == repository.VoteGood {
+		p.Good++
+	} else {
+		p.Nogood++
+	}
+	s.votes = append(s.votes, models.BoardGood{
+		ID: len(s.votes) + 1, Table: table, PostID: postID, MemberID: mbID, Flag: flag, Datetime: time.Now(),
+	})
+	return p.Good, p.Nogood, nil
+}
+
+// MyVote implements repository.VoteRepository
+func (s *Store) MyVote(ctx context.Context, table string, postID int, mbID string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.findVote(table, postID, mbID), nil
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// 글과 댓글은 같은 g5_write_* 테이블의 wr_id 를 나눠 씀
+func (s *Store) nextID(table string) int {
+	id := 0
+	for _, p := range s.posts[table] {
+		id = max(id, p.ID)
+	}
+	for _, c := range s.comments[table] {
+		id = max(id, c.ID)
+	}
+	return id + 1
+}
+
+// CreatePost implements repository.PostWriter
+func (s *Store) CreatePost(ctx context.Context, bo board.Board, p *models.Post, parent *models.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if parent == nil {
+		num := 0
+		for _, q := range s.posts[bo.Table] {
+			num = min(num, q.Num)
+		}
+		p.Num, p.Reply = num-1, ""
+	} else {
+		n := len(parent.Reply) + 1
+		last := ""
+		for _, q := range s.posts[bo.Table] {
+			if q.Num == parent.Num && len(q.Reply) >= n && strings.HasPrefix(q.Reply, parent.Reply) {
+				last = max(last, q.Reply[n-1:n])
+			}
+		}
+		reply, err := repository.NextReply(parent.Reply, last)
+		if err != nil {
+			return err
+		}
+		p.Num, p.Reply = parent.Num, reply
+	}
+
+	if p.Datetime.IsZero() {
+		p.Datetime = time.Now()
+	}
+	p.Last = p.Datetime.Format("2006-01-02 15:04:05")
+	p.ID = s.nextID(bo.Table)
+	p.Parent = p.ID
+	post := *p
+	s.posts[bo.Table] = append(s.posts[bo.Table], &post)
+
+	if pt, ok := repository.BoardPoint(bo, models.Point{
+		MemberID:  p.MemberID,
+		Datetime:  p.Datetime,
+		Content:   fmt.Sprintf("%s %d 글쓰기", bo.Subject, p.ID),
+		Point:     bo.WritePoint,
+		RelTable:  bo.Table,
+		RelID:     strconv.Itoa(p.ID),
+		RelAction: "쓰기",
+	}); ok {
+		s.insertPoint(pt)
+	}
+	return nil
+}
+
+// UpdatePost implements repository.PostWriter
+func (s *Store) UpdatePost(ctx context.Context, table string, p models.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.findPost(table, p.ID)
+	if q == nil {
+		return repository.ErrNotFound
+	}
+	q.CategoryName, q.Option, q.Subject, q.Content = p.CategoryName, p.Option, p.Subject, p.Content
+	q.Link1, q.Link2 = p.Link1, p.Link2
+	q.Name, q.Email, q.Homepage, q.Password = p.Name, p.Email, p.Homepage, p.Password
+	return nil
+}
+
+// DeletePost implements repository.PostWriter
+func (s *Store) DeletePost(ctx context.Context, table string, p models.Post) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept []*models.Post
+	for _, q := range s.posts[table] {
+		if q.ID == p.ID {
+			s.deletePoint(q.MemberID, table, strconv.Itoa(q.ID), "쓰기")
+			continue
+		}
+		kept = append(kept, q)
+	}
+	s.posts[table] = kept
+
+	var keptComments []*models.Comment
+	for _, c := range s.comments[table] {
+		if c.Parent == p.ID {
+			s.deletePoint(c.MemberID, table, strconv.Itoa(c.ID), "댓글")
+			continue
+		}
+		keptComments = append(keptComments, c)
+	}
+	s.comments[table] = keptComments
+
+	var keptFiles []*models.BoardFile
+	for _, f := range s.files {
+		if f.Table != table || f.PostID != p.ID {
+			keptFiles = append(keptFiles, f)
+		}
+	}
+	s.files = keptFiles
+	return nil
+}
+
+// CountReplies implements repository.PostWriter
+func (s *Store) CountReplies(ctx context.Context, table string, p models.Post) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, q := range s.posts[table] {
+		if q.Num == p.Num && q.ID != p.ID && strings.HasPrefix(q.Reply, p.Reply) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// CountOtherComments implements repository.PostWriter
+func (s *Store) CountOtherComments(ctx context.Context, table string, postID int, mbID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := 0
+	for _, c := range s.comments[table] {
+		if c.Parent == postID && c.MemberID != mbID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// insertPoint follows repository's insert_point rules; s.mu must be held
+func (s *Store) insertPoint(pt models.Point) {
+	m := s.members[pt.MemberID]
+	if pt.Point == 0 || m == nil || s.pointCharged(pt) {
+		return
+	}
+	if pt.Datetime.IsZero() {
+		pt.Datetime = time.Now()
+	}
+	m.Point += pt.Point
+	s.pointSeq++
+	pt.ID = s.pointSeq
+	if pt.ExpireDate == "" {
+		pt.ExpireDate = "9999-12-31"
+	}
+	pt.MemberPoint = m.Point
+	s.points = append(s.points, pt)
+}
+
+// pointCharged reports whether pt's rel was already charged; s.mu must be held
+func (s *Store) pointCharged(pt models.Point) bool {
+	if pt.RelTable == "" && pt.RelID == "" && pt.RelAction == "" {
+		return false
+	}
+	for _, q := range s.points {
+		if q.MemberID == pt.MemberID && q.RelTable == pt.RelTable && q.RelID == pt.RelID && q.RelAction == pt.RelAction {
+			return true
+		}
+	}
+	return false
+}
+
+// deletePoint takes back a charged point; s.mu must be held
+func (s *Store) deletePoint(mbID, relTable, relID, relAction string) {
+	for i, q := range s.points {
+		if q.MemberID == mbID && q.RelTable == relTable && q.RelID == relID && q.RelAction == relAction {
+			if m := s.members[mbID]; m != nil {
+				m.Point -= q.Point
+			}
+			s.points = append(s.points[:i], s.points[i+1:]...)
+			return
+		}
+	}
+}
+
+// Points returns the g5_point rows of a member, oldest first
+func (s *Store) Points(mbID string) []models.Point {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []models.Point
+	for _, q := range s.points {
+		if q.MemberID == mbID {
+			list = append(list, q)
+		}
+	}
+	return list
+}
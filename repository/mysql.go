@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"fibergo/board"
+	"fibergo/models"
+)
+
+// MySQL implements the repositories on Gnuboard's g5_* tables
+type MySQL struct {
+	db *sql.DB
+}
+
+// NewMySQL creates a MySQL repository
+func NewMySQL(db *sql.DB) *MySQL {
+	return &MySQL{db: db}
+}
+
+// 게시판 테이블명 (bo_table 은 SQL 에 그대로 들어가므로 항상 검증)
+func writeTable(table string) (string, error) {
+	if !board.ValidTableName(table) {
+		return "", ErrInvalidTable
+	}
+	return "g5_write_" + table, nil
+}
+
+const postColumns = `wr_id, wr_num, wr_reply, wr_parent, wr_comment, ca_name, wr_option,
+	wr_subject, wr_content, wr_link1, wr_link2, wr_link1_hit, wr_link2_hit,
+	wr_hit, wr_good, wr_nogood, mb_id, wr_password, wr_name, wr_email, wr_homepage,
+	wr_datetime, wr_file, wr_last, wr_ip,
+	wr_1, wr_2, wr_3, wr_4, wr_5, wr_6, wr_7, wr_8, wr_9, wr_10`
+
+// postSummaryColumns is postColumns for lists: wr_content and wr_password
+// are read as empty strings so a page of posts (and its cache entry) carries neither
+// the bodies nor the password hashes. scanPost reads both column lists.
+const postSummaryColumns = `wr_id, wr_num, wr_reply, wr_parent, wr_comment, ca_name, wr_option,
+	wr_subject, '', wr_link1, wr_link2, wr_link1_hit, wr_link2_hit,
+	wr_hit, wr_good, wr_nogood, mb_id, '', wr_name, wr_email, wr_homepage,
+	wr_datetime, wr_file, wr_last, wr_ip,
+	wr_1, wr_2, wr_3, wr_4, wr_5, wr_6, wr_7, wr_8, wr_9, wr_10`
+
+const commentColumns = `wr_id, wr_parent, wr_comment, wr_comment_reply, wr_option, wr_content,
+	wr_good, wr_nogood, mb_id, wr_password, wr_name, wr_email, wr_homepage,
+	wr_datetime, wr_ip,
+	wr_1, wr_2, wr_3, wr_4, wr_5, wr_6, wr_7, wr_8, wr_9, wr_10`
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPost(row scanner) (models.Post, error) {
+	var p models.Post
+	err := row.Scan(
+		&p.ID, &p.Num, &p.Reply, &p.Parent, &p.CommentCount, &p.CategoryName, &p.Option,
+		&p.Subject, &p.Content, &p.Link1, &p.Link2, &p.Link1Hit, &p.Link2Hit,
+		&p.Hit, &p.Good, &p.Nogood, &p.MemberID, &p.Password, &p.Name, &p.Email, &p.Homepage,
+		&p.Datetime, &p.File, &p.Last, &p.IP,
+		&p.Extra[0], &p.Extra[1], &p.Extra[2], &p.Extra[3], &p.Extra[4],
+		&p.Extra[5], &p.Extra[6], &p.Extra[7], &p.Extra[8], &p.Extra[9],
+	)
+	return p, err
+}
+
+// scanComment reads commentColumns followed by the parent comment wr_id
+func scanComment(row scanner) (models.Comment, error) {
+	var c models.Comment
+	err := row.Scan(
+		&c.ID, &c.Parent, &c.Comment, &c.CommentReply, &c.Option, &c.Content,
+		&c.Good, &c.Nogood, &c.MemberID, &c.Password, &c.Name, &c.Email, &c.Homepage,
+		&c.Datetime, &c.IP,
+		&c.Extra[0], &c.Extra[1], &c.Extra[2], &c.Extra[3], &c.Extra[4],
+		&c.Extra[5], &c.Extra[6], &c.Extra[7], &c.Extra[8], &c.Extra[9],
+		&c.ReplyTo,
+	)
+	return c, err
+}
+
+// ListPosts implements PostRepository. 그누보드 기본 정렬 (wr_num, wr_reply) 사용
+func (r *MySQL) ListPosts(ctx context.Context, table string, opts ListOptions) ([]models.Post, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	var rows *sql.Rows
+	if opts.After != nil {
+		// OFFSET 없이 (wr_num, wr_reply) 인덱스로 바로 이동
+		rows, err = r.db.QueryContext(ctx, `
+			SELECT `+postSummaryColumns+`
+			FROM `+t+`
+			WHERE wr_is_comment = 0
+			AND (wr_num > ? OR (wr_num = ? AND wr_reply > ?))
+			ORDER BY wr_num, wr_reply
+			LIMIT ?
+		`, opts.After.Num, opts.After.Num, opts.After.Reply, opts.Limit)
+	} else {
+		rows, err = r.db.QueryContext(ctx, `
+			SELECT `+postSummaryColumns+`
+			FROM `+t+`
+			WHERE wr_is_comment = 0
+			ORDER BY wr_num, wr_reply
+			LIMIT ? OFFSET ?
+		`, opts.Limit, opts.Offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// CountPosts implements PostRepository
+func (r *MySQL) CountPosts(ctx context.Context, table string) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+t+` WHERE wr_is_comment = 0`).Scan(&count)
+	return count, err
+}
+
+// GetPost implements PostRepository
+func (r *MySQL) GetPost(ctx context.Context, table string, id int) (*models.Post, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	row := r.db.QueryRowContext(ctx, `SELECT `+postColumns+` FROM `+t+` WHERE wr_id = ? AND wr_is_comment = 0`, id)
+	p, err := scanPost(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// PostContents implements PostRepository
+func (r *MySQL) PostContents(ctx context.Context, table string, ids []int) (map[int]string, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return map[int]string{}, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT wr_id, wr_content FROM `+t+`
+		WHERE wr_is_comment = 0 AND wr_id IN (`+placeholders(len(ids))+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contents := make(map[int]string, len(ids))
+	for rows.Next() {
+		var id int
+		var content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, err
+		}
+		contents[id] = content
+	}
+	return contents, rows.Err()
+}
+
+// ThreadOrigin implements PostRepository
+func (r *MySQL) ThreadOrigin(ctx context.Context, table string, num int) (*models.Post, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+postSummaryColumns+` FROM `+t+` WHERE wr_num = ? AND wr_reply = '' AND wr_is_comment = 0 LIMIT 1`, num)
+	p, err := scanPost(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// AddHits implements PostRepository. 쌓인 조회수를 게시판마다 한 번의 UPDATE 로 반영
+func (r *MySQL) AddHits(ctx context.Context, table string, hits map[int]int) error {
+	t, err := writeTable(table)
+	if err != nil {
+		return err
+	}
+	if len(hits) == 0 {
+		return nil
+	}
+	// 잠금 순서를 맞추려고 wr_id 순서로
+	ids := make([]int, 0, len(hits))
+	for id := range hits {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var b strings.Builder
+	args := make([]interface{}, 0, len(ids)*3)
+	b.WriteString(`UPDATE ` + t + ` SET wr_hit = wr_hit + CASE wr_id`)
+	for _, id := range ids {
+		b.WriteString(` WHEN ? THEN ?`)
+		args = append(args, id, hits[id])
+	}
+	b.WriteString(` ELSE 0 END WHERE wr_id IN (` + placeholders(len(ids)) + `)`)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err = r.db.ExecContext(ctx, b.String(), args...)
+	return err
+}
+
+// ListComments implements CommentRepository
+func (r *MySQL) ListComments(ctx context.Context, table string, postID int, opts ListOptions) ([]models.Comment, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = math.MaxInt32
+	}
+	// 부모 댓글이 이전 페이지에 있을 수 있으므로 쿼리에서 찾음
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+commentColumns+`, `+replyToColumn(t)+`
+		FROM `+t+` c
+		WHERE c.wr_is_comment = 1
+		AND c.wr_parent = ?
+		ORDER BY c.wr_comment, c.wr_comment_reply
+		LIMIT ? OFFSET ?
+	`, postID, limit, opts.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []models.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// replyToColumn selects the wr_id of c's parent comment: the one in the same
+// wr_comment whose wr_comment_reply is one character shorter
+func replyToColumn(t string) string {
+	return `IF(c.wr_comment_reply = '', 0, COALESCE((
+		SELECT p.wr_id FROM ` + t + ` p
+		WHERE p.wr_parent = c.wr_parent
+		AND p.wr_is_comment = 1
+		AND p.wr_comment = c.wr_comment
+		AND p.wr_comment_reply = LEFT(c.wr_comment_reply, CHAR_LENGTH(c.wr_comment_reply) - 1)
+		LIMIT 1
+	), 0))`
+}
+
+// GetComment implements CommentRepository
+func (r *MySQL) GetComment(ctx context.Context, table string, id int) (*models.Comment, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+commentColumns+`, `+replyToColumn(t)+`
+		FROM `+t+` c WHERE c.wr_id = ? AND c.wr_is_comment = 1
+	`, id)
+	cm, err := scanComment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// CountComments implements CommentRepository
+func (r *MySQL) CountComments(ctx context.Context, table string, postID int) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+t+` WHERE wr_is_comment = 1 AND wr_parent = ?`, postID).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+)
+
+// CreateComment implements CommentWriter. A new comment takes the next
+// wr_comment of the post, an answer shares its parent's wr_comment with the
+// next wr_comment_reply.
+func (r *MySQL) CreateComment(ctx context.Context, bo board.Board, post models.Post, c *models.Comment, parent *models.Comment) error {
+	t, err := writeTable(bo.Table)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if parent == nil {
+		var max sql.NullInt64
+		err := tx.QueryRowContext(ctx, `
+			SELECT MAX(wr_comment) FROM `+t+` WHERE wr_parent = ? AND wr_is_comment = 1 FOR UPDATE
+		`, post.ID).Scan(&max)
+		if err != nil {
+			return err
+		}
+		c.Comment, c.CommentReply = int(max.Int64)+1, ""
+	} else {
+		n := len(parent.CommentReply) + 1
+		var last sql.NullString
+		err := tx.QueryRowContext(ctx, `
+			SELECT MAX(SUBSTRING(wr_comment_reply, ?, 1)) FROM `+t+`
+			WHERE wr_parent = ? AND wr_is_comment = 1 AND wr_comment = ?
+			AND SUBSTRING(wr_comment_reply, 1, ?) = ? AND SUBSTRING(wr_comment_reply, ?, 1) <> ''
+			FOR UPDATE
+		`, n, post.ID, parent.Comment, n-1, parent.CommentReply, n).Scan(&last)
+		if err != nil {
+			return err
+		}
+		if c.CommentReply, err = NextCommentReply(parent.CommentReply, last.String); err != nil {
+			return err
+		}
+		c.Comment, c.ReplyTo = parent.Comment, parent.ID
+	}
+
+	if c.Datetime.IsZero() {
+		c.Datetime = time.Now()
+	}
+	c.Parent = post.ID
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO `+t+` SET ca_name = ?, wr_option = ?, wr_num = ?, wr_reply = '', wr_parent = ?,
+			wr_is_comment = 1, wr_comment = ?, wr_comment_reply = ?, wr_subject = '', wr_content = ?,
+			mb_id = ?, wr_password = ?, wr_name = ?, wr_email = ?, wr_homepage = ?,
+			wr_datetime = ?, wr_last = '', wr_ip = ?,
+			wr_1 = ?, wr_2 = ?, wr_3 = ?, wr_4 = ?, wr_5 = ?, wr_6 = ?, wr_7 = ?, wr_8 = ?, wr_9 = ?, wr_10 = ?
+	`, post.CategoryName, c.Option, post.Num, post.ID,
+		c.Comment, c.CommentReply, c.Content,
+		c.MemberID, c.Password, c.Name, c.Email, c.Homepage,
+		c.Datetime, c.IP,
+		c.Extra[0], c.Extra[1], c.Extra[2], c.Extra[3], c.Extra[4],
+		c.Extra[5], c.Extra[6], c.Extra[7], c.Extra[8], c.Extra[9],
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = int(id)
+
+	for _, q := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE ` + t + ` SET wr_comment = wr_comment + 1, wr_last = ? WHERE wr_id = ?`, []interface{}{c.Datetime.Format(gnuboardTimeFormat), post.ID}},
+		{`INSERT INTO g5_board_new (bo_table, wr_id, wr_parent, bn_datetime, mb_id) VALUES (?, ?, ?, ?, ?)`, []interface{}{bo.Table, c.ID, post.ID, c.Datetime, c.MemberID}},
+		{`UPDATE g5_board SET bo_count_comment = bo_count_comment + 1 WHERE bo_table = ?`, []interface{}{bo.Table}},
+	} {
+		if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return err
+		}
+	}
+	if pt, ok := BoardPoint(bo, models.Point{
+		MemberID:  c.MemberID,
+		Datetime:  c.Datetime,
+		Content:   fmt.Sprintf("%s %d-%d 댓글쓰기", bo.Subject, post.ID, c.ID),
+		Point:     bo.CommentPoint,
+		RelTable:  bo.Table,
+		RelID:     strconv.Itoa(c.ID),
+		RelAction: "댓글",
+	}); ok {
+		if err := insertPoint(ctx, tx, pt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdateComment implements CommentWriter
+func (r *MySQL) UpdateComment(ctx context.Context, table string, c models.Comment) error {
+	t, err := writeTable(table)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE `+t+` SET wr_option = ?, wr_content = ? WHERE wr_id = ? AND wr_is_comment = 1
+	`, c.Option, c.Content, c.ID)
+	return err
+}
+
+// DeleteComment implements CommentWriter. Like delete_comment.php the post's
+// wr_last goes back to its newest remaining row.
+func (r *MySQL) DeleteComment(ctx context.Context, table string, c models.Comment) error {
+	t, err := writeTable(table)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deletePoint(ctx, tx, c.MemberID, table, strconv.Itoa(c.ID), "댓글"); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+t+` WHERE wr_id = ? AND wr_is_comment = 1`, c.ID); err != nil {
+		return err
+	}
+	var last time.Time
+	if err := tx.QueryRowContext(ctx, `SELECT MAX(wr_datetime) FROM `+t+` WHERE wr_parent = ?`, c.Parent).Scan(&last); err != nil {
+		return err
+	}
+
+	for _, q := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE ` + t + ` SET wr_comment = wr_comment - 1, wr_last = ? WHERE wr_id = ?`, []interface{}{last.Format(gnuboardTimeFormat), c.Parent}},
+		{`UPDATE g5_board SET bo_count_comment = bo_count_comment - 1 WHERE bo_table = ?`, []interface{}{table}},
+		{`DELETE FROM g5_board_new WHERE bo_table = ? AND wr_id = ?`, []interface{}{table, c.ID}},
+	} {
+		if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CountCommentReplies implements CommentWriter
+func (r *MySQL) CountCommentReplies(ctx context.Context, table string, c models.Comment) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM `+t+`
+		WHERE wr_parent = ? AND wr_is_comment = 1 AND wr_comment = ?
+		AND wr_comment_reply LIKE CONCAT(?, '%') AND wr_id <> ?
+	`, c.Parent, c.Comment, c.CommentReply, c.ID).Scan(&n)
+	return n, err
+}
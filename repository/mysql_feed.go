@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"fibergo/models"
+)
+
+// LatestPosts implements PostRepository
+func (r *MySQL) LatestPosts(ctx context.Context, table string, limit int) ([]models.Post, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+postSummaryColumns+`
+		FROM `+t+`
+		WHERE wr_is_comment = 0
+		ORDER BY wr_id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// GetPosts implements PostRepository
+func (r *MySQL) GetPosts(ctx context.Context, table string, ids []int) ([]models.Post, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+postSummaryColumns+`
+		FROM `+t+`
+		WHERE wr_is_comment = 0 AND wr_id IN (`+placeholders(len(ids))+`)
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// RecentPosts implements BoardNewRepository
+func (r *MySQL) RecentPosts(ctx context.Context, tables []string, offset, limit int) ([]BoardNew, error) {
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, 0, len(tables)+2)
+	for _, t := range tables {
+		args = append(args, t)
+	}
+	args = append(args, limit, offset)
+
+	// wr_id = wr_parent 인 행이 원글 (댓글은 wr_parent 가 원글 ID)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT bn_id, bo_table, wr_id, bn_datetime
+		FROM g5_board_new
+		WHERE wr_id = wr_parent AND bo_table IN (`+placeholders(len(tables))+`)
+		ORDER BY bn_id DESC
+		LIMIT ? OFFSET ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []BoardNew
+	for rows.Next() {
+		var bn BoardNew
+		if err := rows.Scan(&bn.ID, &bn.Table, &bn.PostID, &bn.Datetime); err != nil {
+			return nil, err
+		}
+		list = append(list, bn)
+	}
+	return list, rows.Err()
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"fibergo/board"
+	"fibergo/models"
+)
+
+const fileColumns = `bo_table, wr_id, bf_no, bf_source, bf_file, bf_download, bf_content,
+	bf_filesize, bf_width, bf_height, bf_type, bf_datetime`
+
+func scanFile(row scanner) (models.BoardFile, error) {
+	var f models.BoardFile
+	err := row.Scan(
+		&f.Table, &f.PostID, &f.No, &f.Source, &f.File, &f.Download, &f.Content,
+		&f.Filesize, &f.Width, &f.Height, &f.Type, &f.Datetime,
+	)
+	return f, err
+}
+
+// ListFiles implements FileRepository. 그누보드처럼 저장된 파일명이 없는 행은 제외
+func (r *MySQL) ListFiles(ctx context.Context, table string, postID int) ([]models.BoardFile, error) {
+	if !board.ValidTableName(table) {
+		return nil, ErrInvalidTable
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+fileColumns+` FROM g5_board_file
+		WHERE bo_table = ? AND wr_id = ? AND bf_file <> ''
+		ORDER BY bf_no
+	`, table, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []models.BoardFile
+	for rows.Next() {
+		f, err := scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// ListPostFiles implements FileRepository
+func (r *MySQL) ListPostFiles(ctx context.Context, table string, postIDs []int) (map[int][]models.BoardFile, error) {
+	if !board.ValidTableName(table) {
+		return nil, ErrInvalidTable
+	}
+	if len(postIDs) == 0 {
+		return map[int][]models.BoardFile{}, nil
+	}
+	args := []interface{}{table}
+	for _, id := range postIDs {
+		args = append(args, id)
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+fileColumns+` FROM g5_board_file
+		WHERE bo_table = ? AND wr_id IN (`+placeholders(len(postIDs))+`) AND bf_file <> ''
+		ORDER BY wr_id, bf_no
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make(map[int][]models.BoardFile, len(postIDs))
+	for rows.Next() {
+		f, err := scanFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files[f.PostID] = append(files[f.PostID], f)
+	}
+	return files, rows.Err()
+}
+
+// GetFile implements FileRepository
+func (r *MySQL) GetFile(ctx context.Context, table string, postID, no int) (*models.BoardFile, error) {
+	if !board.ValidTableName(table) {
+		return nil, ErrInvalidTable
+	}
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+fileColumns+` FROM g5_board_file
+		WHERE bo_table = ? AND wr_id = ? AND bf_no = ? AND bf_file <> ''
+	`, table, postID, no)
+	f, err := scanFile(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// IncrementDownload implements FileRepository
+func (r *MySQL) IncrementDownload(ctx context.Context, table string, postID, no int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE g5_board_file SET bf_download = bf_download + 1 WHERE bo_table = ? AND wr_id = ? AND bf_no = ?
+	`, table, postID, no)
+	return err
+}
+
+// AddFiles implements FileWriter. The post row is locked so two uploads to
+// the same post can't take the same bf_no or pass bo_upload_count together.
+func (r *MySQL) AddFiles(ctx context.Context, bo board.Board, postID int, files []*models.BoardFile) error {
+	t, err := writeTable(bo.Table)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT wr_id FROM `+t+` WHERE wr_id = ? AND wr_is_comment = 0 FOR UPDATE`, postID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT bf_no FROM g5_board_file WHERE bo_table = ? AND wr_id = ? AND bf_file <> ''`, bo.Table, postID)
+	if err != nil {
+		return err
+	}
+	used := map[int]bool{}
+	for rows.Next() {
+		var no int
+		if err := rows.Scan(&no); err != nil {
+			rows.Close()
+			return err
+		}
+		used[no] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(used)+len(files) > bo.UploadCount {
+		return ErrTooManyFiles
+	}
+
+	no := 0
+	for _, f := range files {
+		for used[no] {
+			no++
+		}
+		used[no] = true
+		f.Table, f.PostID, f.No = bo.Table, postID, no
+		// 파일을 지운 자리는 bf_file 이 빈 행으로 남아 있을 수 있음
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO g5_board_file
+				(bo_table, wr_id, bf_no, bf_source, bf_file, bf_download, bf_content,
+				bf_filesize, bf_width, bf_height, bf_type, bf_datetime)
+			VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE bf_source = VALUES(bf_source), bf_file = VALUES(bf_file), bf_download = 0,
+				bf_content = VALUES(bf_content), bf_filesize = VALUES(bf_filesize), bf_width = VALUES(bf_width),
+				bf_height = VALUES(bf_height), bf_type = VALUES(bf_type), bf_datetime = VALUES(bf_datetime)
+		`, f.Table, f.PostID, f.No, f.Source, f.File, f.Content,
+			f.Filesize, f.Width, f.Height, f.Type, f.Datetime); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE `+t+` SET wr_file = (
+			SELECT COUNT(*) FROM g5_board_file WHERE bo_table = ? AND wr_id = ? AND bf_file <> ''
+		) WHERE wr_id = ?
+	`, bo.Table, postID, postID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"fibergo/models"
+)
+
+// GetMember implements MemberRepository
+func (r *MySQL) GetMember(ctx context.Context, id string) (*models.Member, error) {
+	var m models.Member
+	err := r.db.QueryRowContext(ctx, `
+		SELECT mb_id, mb_password, mb_name, mb_nick, mb_email, mb_level, mb_point,
+			mb_datetime, mb_leave_date, mb_intercept_date
+		FROM g5_member
+		WHERE mb_id = ?
+	`, id).Scan(
+		&m.ID, &m.Password, &m.Name, &m.Nick, &m.Email, &m.Level, &m.Point,
+		&m.Datetime, &m.LeaveDate, &m.InterceptDate,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// SuperAdmin returns cf_admin, the mb_id of Gnuboard's super admin
+func (r *MySQL) SuperAdmin(ctx context.Context) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, `SELECT cf_admin FROM g5_config LIMIT 1`).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"fibergo/models"
+)
+
+// insertPoint is Gnuboard's insert_point: nothing happens if the point is 0,
+// the member does not exist, or the same rel_table/rel_id/rel_action was
+// already charged. mb_point and po_mb_point are updated in tx. pt comes
+// from BoardPoint, which also decides whether points are used at all.
+func insertPoint(ctx context.Context, tx *sql.Tx, pt models.Point) error {
+	if pt.Point == 0 || pt.MemberID == "" {
+		return nil
+	}
+	if charged, err := pointCharged(ctx, tx, pt); err != nil || charged {
+		return err
+	}
+
+	var balance int
+	err := tx.QueryRowContext(ctx, `SELECT mb_point FROM g5_member WHERE mb_id = ? FOR UPDATE`, pt.MemberID).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	balance += pt.Point
+
+	if pt.Datetime.IsZero() {
+		pt.Datetime = time.Now()
+	}
+	if pt.ExpireDate == "" {
+		pt.ExpireDate = noExpireDate
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO g5_point (mb_id, po_datetime, po_content, po_point, po_use_point, po_expired,
+			po_expire_date, po_mb_point, po_rel_table, po_rel_id, po_rel_action)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?)
+	`, pt.MemberID, pt.Datetime, pt.Content, pt.Point, pt.Expired, pt.ExpireDate, balance, pt.RelTable, pt.RelID, pt.RelAction)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE g5_member SET mb_point = ? WHERE mb_id = ?`, balance, pt.MemberID)
+	return err
+}
+
+// pointCharged reports whether pt's rel_table/rel_id/rel_action was already
+// charged to the member. Points without a rel are never duplicates.
+func pointCharged(ctx context.Context, tx *sql.Tx, pt models.Point) (bool, error) {
+	if pt.RelTable == "" && pt.RelID == "" && pt.RelAction == "" {
+		return false, nil
+	}
+	var n int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM g5_point
+		WHERE mb_id = ? AND po_rel_table = ? AND po_rel_id = ? AND po_rel_action = ?
+	`, pt.MemberID, pt.RelTable, pt.RelID, pt.RelAction).Scan(&n)
+	return n > 0, err
+}
+
+// ChargePoint implements PointRepository. The member row is locked before
+// the balance check so two concurrent charges can't both spend the same points.
+func (r *MySQL) ChargePoint(ctx context.Context, pt models.Point) error {
+	if pt.Point == 0 || pt.MemberID == "" {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var balance int
+	err = tx.QueryRowContext(ctx, `SELECT mb_point FROM g5_member WHERE mb_id = ? FOR UPDATE`, pt.MemberID).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	charged, err := pointCharged(ctx, tx, pt)
+	if err != nil || charged {
+		return err
+	}
+	if pt.Point < 0 && balance+pt.Point < 0 {
+		return ErrInsufficientPoints
+	}
+	if err := insertPoint(ctx, tx, pt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListPoints implements PointRepository
+func (r *MySQL) ListPoints(ctx context.Context, mbID string, offset, limit int) ([]models.Point, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT po_id, mb_id, po_datetime, po_content, po_point, po_use_point, po_expired,
+			po_expire_date, po_mb_point, po_rel_table, po_rel_id, po_rel_action
+		FROM g5_point
+		WHERE mb_id = ?
+		ORDER BY po_id DESC
+		LIMIT ? OFFSET ?
+	`, mbID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.Point{}
+	for rows.Next() {
+		var pt models.Point
+		var expire time.Time
+		if err := rows.Scan(&pt.ID, &pt.MemberID, &pt.Datetime, &pt.Content, &pt.Point, &pt.UsePoint, &pt.Expired,
+			&expire, &pt.MemberPoint, &pt.RelTable, &pt.RelID, &pt.RelAction); err != nil {
+			return nil, err
+		}
+		pt.ExpireDate = expire.Format("2006-01-02")
+		list = append(list, pt)
+	}
+	return list, rows.Err()
+}
+
+// CountPoints implements PointRepository
+func (r *MySQL) CountPoints(ctx context.Context, mbID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM g5_point WHERE mb_id = ?`, mbID).Scan(&n)
+	return n, err
+}
+
+// deletePoint is Gnuboard's delete_point: it removes the row charged for
+// rel_table/rel_id/rel_action and takes its amount back from mb_point
+func deletePoint(ctx context.Context, tx *sql.Tx, mbID, relTable, relID, relAction string) error {
+	if mbID == "" {
+		return nil
+	}
+	var id, point int
+	err := tx.QueryRowContext(ctx, `
+		SELECT po_id, po_point FROM g5_point
+		WHERE mb_id = ? AND po_rel_table = ? AND po_rel_id = ? AND po_rel_action = ?
+		LIMIT 1
+	`, mbID, relTable, relID, relAction).Scan(&id, &point)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM g5_point WHERE po_id = ?`, id); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE g5_member SET mb_point = mb_point - ? WHERE mb_id = ?`, point, mbID)
+	return err
+}
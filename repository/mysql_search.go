@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"sync"
+
+	"fibergo/models"
+	"fibergo/search"
+)
+
+// 테이블별 FULLTEXT 인덱스 컬럼 ("wr_content,wr_subject" 형태, 정렬됨)
+var fulltextIndexes sync.Map // map[string][]string
+
+// fulltextColumns returns the column sets of the table's FULLTEXT indexes
+func (r *MySQL) fulltextColumns(ctx context.Context, table string) []string {
+	if v, ok := fulltextIndexes.Load(table); ok {
+		return v.([]string)
+	}
+
+	var sets []string
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT GROUP_CONCAT(column_name ORDER BY column_name)
+		FROM information_schema.statistics
+		WHERE table_schema = DATABASE() AND table_name = ? AND index_type = 'FULLTEXT'
+		GROUP BY index_name
+	`, table)
+	if err != nil {
+		// 조회 실패 시 FULLTEXT 없이 검색 (다음 요청에서 다시 확인)
+		return nil
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cols string
+		if rows.Scan(&cols) == nil {
+			sets = append(sets, cols)
+		}
+	}
+	fulltextIndexes.Store(table, sets)
+	return sets
+}
+
+// 불린 모드 연산자 제거
+var booleanOperators = strings.NewReplacer("+", " ", "-", " ", "<", " ", ">", " ", "(", " ", ")", " ", "~", " ", "*", " ", `"`, " ", "@", " ")
+
+// searchWhere builds the WHERE condition of a search
+func (r *MySQL) searchWhere(ctx context.Context, t string, opts SearchOptions) (string, []interface{}) {
+	q := opts.Query
+	// 구간 경계 글이 두 구간에 중복되지 않도록 [spt, spt+part)
+	where := []string{"wr_is_comment = 0", "wr_num >= ? AND wr_num < ?"}
+	args := []interface{}{opts.SegmentStart, opts.SegmentStart + opts.SegmentSize}
+	if q.Empty() {
+		return strings.Join(where, " AND "), args
+	}
+
+	// 검색 컬럼과 똑같은 FULLTEXT 인덱스가 있으면 MATCH 사용
+	cols := q.Columns()
+	sorted := append([]string(nil), cols...)
+	sort.Strings(sorted)
+	if q.Fields[0].Match == search.MatchContains {
+		for _, set := range r.fulltextColumns(ctx, t) {
+			if set != strings.Join(sorted, ",") {
+				continue
+			}
+			var terms []string
+			for _, w := range q.Words {
+				for _, term := range strings.Fields(booleanOperators.Replace(w)) {
+					if q.And {
+						term = "+" + term
+					}
+					terms = append(terms, term)
+				}
+			}
+			if len(terms) > 0 {
+				where = append(where, "MATCH("+strings.Join(cols, ", ")+") AGAINST (? IN BOOLEAN MODE)")
+				args = append(args, strings.Join(terms, " "))
+				return strings.Join(where, " AND "), args
+			}
+		}
+	}
+
+	// 그누보드 get_sql_search 와 같은 조건
+	var wordConds []string
+	for _, w := range q.Words {
+		var fieldConds []string
+		for _, f := range q.Fields {
+			switch f.Match {
+			case search.MatchExact:
+				fieldConds = append(fieldConds, f.Column+" = ?")
+				args = append(args, w)
+			case search.MatchPrefix:
+				fieldConds = append(fieldConds, f.Column+" LIKE ?")
+				args = append(args, likeEscaper.Replace(w)+"%")
+			default:
+				fieldConds = append(fieldConds, "INSTR(LOWER("+f.Column+"), LOWER(?)) > 0")
+				args = append(args, w)
+			}
+		}
+		wordConds = append(wordConds, "("+strings.Join(fieldConds, " OR ")+")")
+	}
+	op := " OR "
+	if q.And {
+		op = " AND "
+	}
+	where = append(where, "("+strings.Join(wordConds, op)+")")
+	return strings.Join(where, " AND "), args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// MinNum implements PostRepository
+func (r *MySQL) MinNum(ctx context.Context, table string) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	var num sql.NullInt64
+	err = r.db.QueryRowContext(ctx, `SELECT MIN(wr_num) FROM `+t).Scan(&num)
+	return int(num.Int64), err
+}
+
+// SearchPosts implements PostRepository
+func (r *MySQL) SearchPosts(ctx context.Context, table string, opts SearchOptions) ([]models.Post, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	where, args := r.searchWhere(ctx, t, opts)
+	args = append(args, opts.Limit, opts.Offset)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+postColumns+`
+		FROM `+t+`
+		WHERE `+where+`
+		ORDER BY wr_num, wr_reply
+		LIMIT ? OFFSET ?
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+// CountSearch implements PostRepository
+func (r *MySQL) CountSearch(ctx context.Context, table string, opts SearchOptions) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	where, args := r.searchWhere(ctx, t, opts)
+	var count int
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+t+` WHERE `+where, args...).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// 폐기된 JWT 목록 테이블. 스키마는 docs/api-v1.md 참고
+const denylistTable = "g5_jwt_denylist"
+
+// 만료된 항목을 한 번에 지우는 최대 행 수 (잠금을 짧게)
+const purgeBatch = 1000
+
+// RevokeToken implements TokenDenylist. jti 가 PRIMARY KEY 이므로 동시에 같은 토큰을
+// 폐기하면 한 쪽만 행을 추가함
+func (r *MySQL) RevokeToken(ctx context.Context, jti, mbID string, expiresAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT IGNORE INTO `+denylistTable+` (jti, mb_id, expires_at) VALUES (?, ?, ?)
+	`, jti, mbID, expiresAt)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// PurgeTokens implements TokenDenylist, deleting purgeBatch rows at a time
+func (r *MySQL) PurgeTokens(ctx context.Context, t time.Time) error {
+	for {
+		res, err := r.db.ExecContext(ctx, `DELETE FROM `+denylistTable+` WHERE expires_at < ? LIMIT ?`, t, purgeBatch)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n < purgeBatch {
+			return err
+		}
+	}
+}
+
+// IsTokenRevoked implements TokenDenylist
+func (r *MySQL) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+denylistTable+` WHERE jti = ?`, jti).Scan(&n)
+	return n > 0, err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Vote implements VoteRepository. The post row is locked first so two
+// requests of the same member can't both pass the duplicate check.
+func (r *MySQL) Vote(ctx context.Context, table string, postID int, mbID, flag string) (int, int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, 0, err
+	}
+	if flag != VoteGood && flag != VoteNogood {
+		return 0, 0, fmt.Errorf("repository: invalid vote %q", flag)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	var good, nogood int
+	err = tx.QueryRowContext(ctx, `
+		SELECT wr_good, wr_nogood FROM `+t+` WHERE wr_id = ? AND wr_is_comment = 0 FOR UPDATE
+	`, postID).Scan(&good, &nogood)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var voted string
+	err = tx.QueryRowContext(ctx, `
+		SELECT bg_flag FROM g5_board_good
+		WHERE bo_table = ? AND wr_id = ? AND mb_id = ? AND bg_flag IN ('good', 'nogood')
+		LIMIT 1
+	`, table, postID, mbID).Scan(&voted)
+	switch {
+	case err == nil:
+		return good, nogood, ErrAlreadyVoted
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, 0, err
+	}
+
+	// flag 는 위에서 검사했으므로 컬럼명에 그대로 사용
+	if _, err := tx.ExecContext(ctx, `UPDATE `+t+` SET wr_`+flag+` = wr_`+flag+` + 1 WHERE wr_id = ?`, postID); err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO g5_board_good SET bo_table = ?, wr_id = ?, mb_id = ?, bg_flag = ?, bg_datetime = ?
+	`, table, postID, mbID, flag, time.Now()); err != nil {
+		return 0, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	if flag == VoteGood {
+		good++
+	} else {
+		nogood++
+	}
+	return good, nogood, nil
+}
+
+// MyVote implements VoteRepository
+func (r *MySQL) MyVote(ctx context.Context, table string, postID int, mbID string) (string, error) {
+	var flag string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT bg_flag FROM g5_board_good
+		WHERE bo_table = ? AND wr_id = ? AND mb_id = ? AND bg_flag IN ('good', 'nogood')
+		LIMIT 1
+	`, table, postID, mbID).Scan(&flag)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return flag, err
+}
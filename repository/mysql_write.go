@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+)
+
+// 그누보드 wr_datetime, wr_last 형식
+const gnuboardTimeFormat = "2006-01-02 15:04:05"
+
+// CreatePost implements PostWriter. A new thread takes wr_num one below the
+// board's smallest, an answer shares the parent's wr_num with the next
+// wr_reply, and wr_parent is set to the new wr_id.
+func (r *MySQL) CreatePost(ctx context.Context, bo board.Board, p *models.Post, parent *models.Post) error {
+	t, err := writeTable(bo.Table)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if parent == nil {
+		// get_next_num (동시에 쓰는 글이 같은 번호를 받지 않도록 잠금)
+		var min sql.NullInt64
+		if err := tx.QueryRowContext(ctx, `SELECT MIN(wr_num) FROM `+t+` FOR UPDATE`).Scan(&min); err != nil {
+			return err
+		}
+		p.Num, p.Reply = int(min.Int64)-1, ""
+	} else {
+		n := len(parent.Reply) + 1
+		var last sql.NullString
+		err := tx.QueryRowContext(ctx, `
+			SELECT MAX(SUBSTRING(wr_reply, ?, 1)) FROM `+t+`
+			WHERE wr_num = ? AND SUBSTRING(wr_reply, 1, ?) = ? AND SUBSTRING(wr_reply, ?, 1) <> ''
+			FOR UPDATE
+		`, n, parent.Num, n-1, parent.Reply, n).Scan(&last)
+		if err != nil {
+			return err
+		}
+		if p.Reply, err = NextReply(parent.Reply, last.String); err != nil {
+			return err
+		}
+		p.Num = parent.Num
+	}
+
+	if p.Datetime.IsZero() {
+		p.Datetime = time.Now()
+	}
+	p.Last = p.Datetime.Format(gnuboardTimeFormat)
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO `+t+` SET wr_num = ?, wr_reply = ?, wr_comment = 0, wr_is_comment = 0,
+			ca_name = ?, wr_option = ?, wr_subject = ?, wr_content = ?, wr_link1 = ?, wr_link2 = ?,
+			mb_id = ?, wr_password = ?, wr_name = ?, wr_email = ?, wr_homepage = ?,
+			wr_datetime = ?, wr_last = ?, wr_ip = ?,
+			wr_1 = ?, wr_2 = ?, wr_3 = ?, wr_4 = ?, wr_5 = ?, wr_6 = ?, wr_7 = ?, wr_8 = ?, wr_9 = ?, wr_10 = ?
+	`, p.Num, p.Reply,
+		p.CategoryName, p.Option, p.Subject, p.Content, p.Link1, p.Link2,
+		p.MemberID, p.Password, p.Name, p.Email, p.Homepage,
+		p.Datetime, p.Last, p.IP,
+		p.Extra[0], p.Extra[1], p.Extra[2], p.Extra[3], p.Extra[4],
+		p.Extra[5], p.Extra[6], p.Extra[7], p.Extra[8], p.Extra[9],
+	)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	p.ID, p.Parent = int(id), int(id)
+
+	if _, err := tx.ExecContext(ctx, `UPDATE `+t+` SET wr_parent = wr_id WHERE wr_id = ?`, p.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO g5_board_new (bo_table, wr_id, wr_parent, bn_datetime, mb_id) VALUES (?, ?, ?, ?, ?)
+	`, bo.Table, p.ID, p.ID, p.Datetime, p.MemberID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE g5_board SET bo_count_write = bo_count_write + 1 WHERE bo_table = ?`, bo.Table); err != nil {
+		return err
+	}
+	if pt, ok := BoardPoint(bo, models.Point{
+		MemberID:  p.MemberID,
+		Datetime:  p.Datetime,
+		Content:   fmt.Sprintf("%s %d 글쓰기", bo.Subject, p.ID),
+		Point:     bo.WritePoint,
+		RelTable:  bo.Table,
+		RelID:     strconv.Itoa(p.ID),
+		RelAction: "쓰기",
+	}); ok {
+		if err := insertPoint(ctx, tx, pt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// UpdatePost implements PostWriter
+func (r *MySQL) UpdatePost(ctx context.Context, table string, p models.Post) error {
+	t, err := writeTable(table)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE `+t+` SET ca_name = ?, wr_option = ?, wr_subject = ?, wr_content = ?, wr_link1 = ?, wr_link2 = ?,
+			wr_name = ?, wr_email = ?, wr_homepage = ?, wr_password = ?
+		WHERE wr_id = ? AND wr_is_comment = 0
+	`, p.CategoryName, p.Option, p.Subject, p.Content, p.Link1, p.Link2,
+		p.Name, p.Email, p.Homepage, p.Password, p.ID)
+	return err
+}
+
+// DeletePost implements PostWriter. Like delete.php the points of the post
+// and of every comment are taken back before the rows go.
+func (r *MySQL) DeletePost(ctx context.Context, table string, p models.Post) error {
+	t, err := writeTable(table)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT wr_id, mb_id, wr_is_comment FROM `+t+` WHERE wr_parent = ?`, p.ID)
+	if err != nil {
+		return err
+	}
+	type row struct {
+		id        int
+		mbID      string
+		isComment bool
+	}
+	var list []row
+	for rows.Next() {
+		var rw row
+		if err := rows.Scan(&rw.id, &rw.mbID, &rw.isComment); err != nil {
+			rows.Close()
+			return err
+		}
+		list = append(list, rw)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	comments := 0
+	for _, rw := range list {
+		action := "쓰기"
+		if rw.isComment {
+			action = "댓글"
+			comments++
+		}
+		if err := deletePoint(ctx, tx, rw.mbID, table, strconv.Itoa(rw.id), action); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`DELETE FROM ` + t + ` WHERE wr_parent = ?`, []interface{}{p.ID}},
+		{`DELETE FROM g5_board_new WHERE bo_table = ? AND wr_parent = ?`, []interface{}{table, p.ID}},
+		{`DELETE FROM g5_board_file WHERE bo_table = ? AND wr_id = ?`, []interface{}{table, p.ID}},
+		{`DELETE FROM g5_scrap WHERE bo_table = ? AND wr_id = ?`, []interface{}{table, p.ID}},
+		{`UPDATE g5_board SET bo_count_write = bo_count_write - 1, bo_count_comment = bo_count_comment - ? WHERE bo_table = ?`, []interface{}{comments, table}},
+	} {
+		if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CountReplies implements PostWriter
+func (r *MySQL) CountReplies(ctx context.Context, table string, p models.Post) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM `+t+`
+		WHERE wr_num = ? AND wr_reply LIKE CONCAT(?, '%') AND wr_id <> ? AND wr_is_comment = 0
+	`, p.Num, p.Reply, p.ID).Scan(&n)
+	return n, err
+}
+
+// CountOtherComments implements PostWriter
+func (r *MySQL) CountOtherComments(ctx context.Context, table string, postID int, mbID string) (int, error) {
+	t, err := writeTable(table)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM `+t+` WHERE wr_parent = ? AND mb_id <> ? AND wr_is_comment = 1
+	`, postID, mbID).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+)
+
+// 포인트 유효기간을 쓰지 않을 때 (cf_point_term = 0) 의 po_expire_date
+const noExpireDate = "9999-12-31"
+
+// BoardPoint fills in po_expired and po_expire_date of a point charged on
+// bo like insert_point. ok is false when cf_use_point is off, in which case
+// Gnuboard charges nothing at all.
+func BoardPoint(bo board.Board, pt models.Point) (models.Point, bool) {
+	if !bo.UsePoint {
+		return pt, false
+	}
+	if pt.Datetime.IsZero() {
+		pt.Datetime = time.Now()
+	}
+	pt.Expired, pt.ExpireDate = 0, noExpireDate
+	if bo.PointTerm > 0 {
+		// 적립일을 포함해 cf_point_term 일 동안 유효, 차감은 바로 만료 처리
+		pt.ExpireDate = pt.Datetime.AddDate(0, 0, bo.PointTerm-1).Format("2006-01-02")
+		if pt.Point < 0 {
+			pt.Expired, pt.ExpireDate = 1, pt.Datetime.Format("2006-01-02")
+		}
+	}
+	return pt, true
+}
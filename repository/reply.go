@@ -0,0 +1,32 @@
+package repository
+
+// 그누보드의 답변글은 10단계, 댓글의 답글은 5단계까지
+const (
+	maxReplyDepth        = 10
+	maxCommentReplyDepth = 5
+)
+
+// NextReply returns the wr_reply of a new answer to a post whose wr_reply is
+// parent, given the largest character already used on that level ("" if none).
+// Answers on one level are A, B, ... Z like bo_reply_order = 1.
+func NextReply(parent, last string) (string, error) {
+	return nextReply(parent, last, maxReplyDepth)
+}
+
+// NextCommentReply is NextReply for wr_comment_reply
+func NextCommentReply(parent, last string) (string, error) {
+	return nextReply(parent, last, maxCommentReplyDepth)
+}
+
+func nextReply(parent, last string, depth int) (string, error) {
+	if len(parent) >= depth {
+		return "", ErrReplyLimit
+	}
+	switch {
+	case last == "":
+		return parent + "A", nil
+	case last >= "Z":
+		return "", ErrReplyLimit
+	}
+	return parent + string(last[0]+1), nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/search"
+)
+
+// ErrNotFound is returned when the requested row does not exist
+var ErrNotFound = errors.New("repository: not found")
+
+// ErrInvalidTable is returned for a bo_table that is not a safe table name
+var ErrInvalidTable = errors.New("repository: invalid board table")
+
+// ErrReplyLimit is returned when no more replies can be added to a post or
+// comment (10 or 5 levels deep, or A to Z used up on one level)
+var ErrReplyLimit = errors.New("repository: reply limit reached")
+
+// ErrAlreadyVoted is returned when the member has already recommended or
+// not-recommended the post
+var ErrAlreadyVoted = errors.New("repository: already voted")
+
+// ErrTooManyFiles is returned when a post would have more attachments than
+// the board's bo_upload_count
+var ErrTooManyFiles = errors.New("repository: too many files")
+
+// ErrInsufficientPoints is returned when a negative point would leave the
+// member's mb_point below 0
+var ErrInsufficientPoints = errors.New("repository: insufficient points")
+
+// ErrTokenRevoked is returned by RevokeToken when the token id is already
+// on the denylist, i.e. the token was used or revoked before
+var ErrTokenRevoked = errors.New("repository: token already revoked")
+
+// ListOptions controls post list paging. If After is set the list
+// starts right after that position (keyset paging) and Offset is ignored.
+type ListOptions struct {
+	Offset int
+	Limit  int
+	After  *Cursor
+}
+
+// SearchOptions is a board search limited to the wr_num segment
+// [SegmentStart, SegmentStart+SegmentSize) like Gnuboard's spt paging
+type SearchOptions struct {
+	Query        search.Query
+	SegmentStart int
+	SegmentSize  int
+	Offset       int
+	Limit        int
+}
+
+// PostRepository reads posts of a board. table is the bo_table value.
+type PostRepository interface {
+	// ListPosts returns a page of posts without wr_content and wr_password
+	ListPosts(ctx context.Context, table string, opts ListOptions) ([]models.Post, error)
+	CountPosts(ctx context.Context, table string) (int, error)
+	GetPost(ctx context.Context, table string, id int) (*models.Post, error)
+	// PostContents returns wr_content of several posts by wr_id in one query
+	PostContents(ctx context.Context, table string, ids []int) (map[int]string, error)
+	// AddHits raises wr_hit of several posts at once (wr_id → count)
+	AddHits(ctx context.Context, table string, hits map[int]int) error
+
+	// MinNum returns the smallest wr_num of the board (the newest post), 0 if empty
+	MinNum(ctx context.Context, table string) (int, error)
+	SearchPosts(ctx context.Context, table string, opts SearchOptions) ([]models.Post, error)
+	CountSearch(ctx context.Context, table string, opts SearchOptions) (int, error)
+
+	// LatestPosts returns the newest posts (wr_id descending), without
+	// wr_content and wr_password like ListPosts
+	LatestPosts(ctx context.Context, table string, limit int) ([]models.Post, error)
+	// GetPosts returns the posts with the given wr_id values, in no particular
+	// order and without wr_content and wr_password
+	GetPosts(ctx context.Context, table string, ids []int) ([]models.Post, error)
+	// ThreadOrigin returns the original post (wr_reply = '') of the wr_num
+	// thread without wr_content and wr_password, ErrNotFound if it is gone
+	ThreadOrigin(ctx context.Context, table string, num int) (*models.Post, error)
+}
+
+// PostWriter changes posts the way Gnuboard's write_update.php and
+// delete.php do, keeping wr_num/wr_reply, g5_board_new, bo_count_write
+// and g5_point consistent
+type PostWriter interface {
+	// CreatePost fills in p.ID, Num, Reply and Parent. parent is the post
+	// replied to, nil for a new thread.
+	CreatePost(ctx context.Context, bo board.Board, p *models.Post, parent *models.Post) error
+	// UpdatePost saves the editable fields of p (subject, content, options,
+	// category, links and the guest's name, email, homepage and password)
+	UpdatePost(ctx context.Context, table string, p models.Post) error
+	// DeletePost removes p with its comments, their points and g5_board_new rows
+	DeletePost(ctx context.Context, table string, p models.Post) error
+
+	// CountReplies returns the number of answer posts under p
+	CountReplies(ctx context.Context, table string, p models.Post) (int, error)
+	// CountOtherComments returns the comments of postID not written by mbID
+	CountOtherComments(ctx context.Context, table string, postID int, mbID string) (int, error)
+}
+
+// BoardNew is a row of g5_board_new, Gnuboard's index of recent writes
+type BoardNew struct {
+	ID       int    // bn_id
+	Table    string // bo_table
+	PostID   int    // wr_id
+	Datetime time.Time
+}
+
+// BoardNewRepository reads g5_board_new
+type BoardNewRepository interface {
+	// RecentPosts returns new posts (not comments) of the given boards, newest first
+	RecentPosts(ctx context.Context, tables []string, offset, limit int) ([]BoardNew, error)
+}
+
+// MemberRepository reads g5_member
+type MemberRepository interface {
+	// GetMember returns ErrNotFound if mb_id does not exist
+	GetMember(ctx context.Context, id string) (*models.Member, error)
+}
+
+// TokenDenylist stores revoked JWT ids until they expire (g5_jwt_denylist)
+type TokenDenylist interface {
+	// RevokeToken adds jti, or returns ErrTokenRevoked if it is already
+	// there. 확인과 추가가 한 번에 일어나므로 refresh 토큰을 한 번만 쓰게 하는 데 사용
+	RevokeToken(ctx context.Context, jti, mbID string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// PurgeTokens removes the entries that expired before t
+	PurgeTokens(ctx context.Context, t time.Time) error
+}
+
+// CommentRepository reads comments of a post
+type CommentRepository interface {
+	// ListComments returns comments in wr_comment, wr_comment_reply order with
+	// ReplyTo filled in. Limit <= 0 returns every comment from Offset.
+	ListComments(ctx context.Context, table string, postID int, opts ListOptions) ([]models.Comment, error)
+	// CountComments returns the number of comments of a post
+	CountComments(ctx context.Context, table string, postID int) (int, error)
+	// GetComment returns one comment with ReplyTo filled in
+	GetComment(ctx context.Context, table string, id int) (*models.Comment, error)
+}
+
+// CommentWriter changes comments the way write_comment_update.php and
+// delete_comment.php do, keeping the post's wr_comment and wr_last,
+// g5_board_new, bo_count_comment and g5_point consistent
+type CommentWriter interface {
+	// CreateComment fills in c.ID, Comment and CommentReply. parent is the
+	// comment answered, nil for a new comment on post.
+	CreateComment(ctx context.Context, bo board.Board, post models.Post, c *models.Comment, parent *models.Comment) error
+	// UpdateComment saves the content and options of c
+	UpdateComment(ctx context.Context, table string, c models.Comment) error
+	// DeleteComment removes c and takes back its point
+	DeleteComment(ctx context.Context, table string, c models.Comment) error
+	// CountCommentReplies returns the number of answers under c
+	CountCommentReplies(ctx context.Context, table string, c models.Comment) (int, error)
+}
+
+// g5_board_good.bg_flag 값
+const (
+	VoteGood   = "good"
+	VoteNogood = "nogood"
+)
+
+// VoteRepository records recommendations in g5_board_good like good.php
+type VoteRepository interface {
+	// Vote adds one good or nogood vote of mbID to the post and returns the
+	// new wr_good and wr_nogood. A member votes once per post (either flag).
+	Vote(ctx context.Context, table string, postID int, mbID, flag string) (good, nogood int, err error)
+	// MyVote returns the bg_flag of mbID's vote on the post, "" if none
+	MyVote(ctx context.Context, table string, postID int, mbID string) (string, error)
+}
+
+// FileRepository reads g5_board_file rows of a post
+type FileRepository interface {
+	// ListFiles returns the attachments of a post in bf_no order
+	ListFiles(ctx context.Context, table string, postID int) ([]models.BoardFile, error)
+	// ListPostFiles returns the attachments of several posts by wr_id in one query
+	ListPostFiles(ctx context.Context, table string, postIDs []int) (map[int][]models.BoardFile, error)
+	GetFile(ctx context.Context, table string, postID, no int) (*models.BoardFile, error)
+	IncrementDownload(ctx context.Context, table string, postID, no int) error
+}
+
+// FileWriter stores uploaded attachments
+type FileWriter interface {
+	// AddFiles gives files the lowest unused bf_no, inserts them and sets
+	// the post's wr_file. ErrTooManyFiles if the post would have more than
+	// bo_upload_count files.
+	AddFiles(ctx context.Context, bo board.Board, postID int, files []*models.BoardFile) error
+}
+
+// PointRepository keeps the g5_point ledger and g5_member.mb_point
+type PointRepository interface {
+	// ChargePoint is insert_point in its own transaction. Nothing happens
+	// if the same rel_table/rel_id/rel_action was already charged; a new
+	// negative point that mb_point can't cover fails with ErrInsufficientPoints.
+	ChargePoint(ctx context.Context, pt models.Point) error
+	// ListPoints returns the member's g5_point rows, newest first
+	ListPoints(ctx context.Context, mbID string, offset, limit int) ([]models.Point, error)
+	CountPoints(ctx context.Context, mbID string) (int, error)
+}
@@ -0,0 +1,440 @@
+package routes
+
+import (
+	"fibergo/openapi"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Route is an /api route. The same table registers handlers and
+// generates the OpenAPI document, so the two cannot drift apart.
+type Route struct {
+	openapi.Route
+	Handler fiber.Handler
+}
+
+func route(method, path string, handler fiber.Handler, doc openapi.Operation) Route {
+	return Route{Route: openapi.Route{Method: method, Path: path, Doc: doc}, Handler: handler}
+}
+
+// 자주 쓰는 에러 응답 문서
+var (
+	v1InvalidResponse  = openapi.ResponseDoc{Status: 400, Description: "invalid_board, invalid_id", Body: ErrorEnvelope{}}
+	v1NotFoundResponse = openapi.ResponseDoc{Status: 404, Description: "not_found", Body: ErrorEnvelope{}}
+	v1ErrorResponse    = openapi.ResponseDoc{Status: 500, Description: "internal_error", Body: ErrorEnvelope{}}
+
+	// 게시판 권한 (bo_list_level, bo_read_level)
+	v1LoginResponse     = openapi.ResponseDoc{Status: 401, Description: "unauthorized (손님에게 권한 없음)", Body: ErrorEnvelope{}}
+	v1ForbiddenResponse = openapi.ResponseDoc{Status: 403, Description: "forbidden (회원 레벨 부족)", Body: ErrorEnvelope{}}
+	v1SecretResponse    = openapi.ResponseDoc{Status: 403, Description: "forbidden, secret_post (비밀글)", Body: ErrorEnvelope{}}
+
+	legacyInvalidResponse  = openapi.ResponseDoc{Status: 400, Body: LegacyError{}}
+	legacyNotFoundResponse = openapi.ResponseDoc{Status: 404, Body: LegacyError{}}
+	legacyErrorResponse    = openapi.ResponseDoc{Status: 500, Body: LegacyError{}}
+
+	legacyLoginResponse     = openapi.ResponseDoc{Status: 401, Body: LegacyError{}}
+	legacyForbiddenResponse = openapi.ResponseDoc{Status: 403, Body: LegacyError{}}
+)
+
+var pageQuery = []openapi.Param{
+	{Name: "page", Type: "integer", Description: "1부터 시작하는 페이지"},
+	{Name: "per_page", Type: "integer", Description: "페이지당 게시글 수 (기본 bo_page_rows, 최대 MAX_PAGE_SIZE)"},
+	{Name: "cursor", Type: "string", Description: "keyset 페이지. 빈 값이면 처음부터, 이후 meta.next_cursor 사용"},
+	{Name: "count", Type: "boolean", Description: "false 이면 전체 개수 (meta.total) 생략"},
+}
+
+var searchQuery = []openapi.Param{
+	{Name: "sfl", Type: "string", Description: "wr_subject (기본), wr_content, wr_subject||wr_content, mb_id,1, mb_id,0, wr_name,1, wr_name,0"},
+	{Name: "stx", Type: "string", Description: "검색어 (공백으로 구분, 앞의 두 단어)"},
+	{Name: "sop", Type: "string", Description: "and (기본) 또는 or"},
+	{Name: "spt", Type: "integer", Description: "검색 구간 시작 wr_num (기본 가장 최신 글)"},
+	{Name: "page", Type: "integer", Description: "구간 안에서의 페이지"},
+	{Name: "per_page", Type: "integer"},
+}
+
+// V1Routes returns the /api/v1 routes, relative to /api
+func V1Routes() []Route {
+	tags := []string{"v1"}
+	return []Route{
+		route(fiber.MethodGet, "/v1/boards", HandleBoardsV1, openapi.Operation{
+			Summary: "게시판 목록", Tags: tags,
+			Responses: []openapi.ResponseDoc{{Status: 200, Body: Envelope[[]BoardInfo]{}}},
+		}),
+		route(fiber.MethodGet, "/v1/boards/:type", HandleBoardV1, openapi.Operation{
+			Summary: "게시판 정보", Tags: tags,
+			Responses: []openapi.ResponseDoc{{Status: 200, Body: Envelope[BoardInfo]{}}, v1InvalidResponse},
+		}),
+		route(fiber.MethodGet, "/v1/boards/:type/posts", HandlePostsV1, openapi.Operation{
+			Summary: "게시글 목록", Tags: tags, Query: pageQuery,
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[[]PostSummary]{}},
+				{Status: 400, Description: "invalid_board, invalid_cursor", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				v1ForbiddenResponse,
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodGet, "/v1/boards/:type/search", HandleSearchV1, openapi.Operation{
+			Summary: "게시판 검색", Tags: tags, Query: searchQuery,
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[[]SearchResult]{}},
+				{Status: 400, Description: "invalid_board, invalid_search", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				v1ForbiddenResponse,
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodGet, "/v1/boards/:type/posts/:id", HandlePostV1, openapi.Operation{
+			Summary: "게시글 상세 (bo_read_point 적용)", Tags: tags,
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[PostDetail]{}},
+				v1InvalidResponse,
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, secret_post (비밀글), insufficient_points (bo_read_point)", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPost, "/v1/boards/:type/posts/:id/password", HandleUnlockV1, openapi.Operation{
+			Summary: "손님 비밀글 열기 (세션에 기록)", Tags: tags, Body: UnlockRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[PostDetail]{}},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, secret_post (회원의 비밀글), invalid_password, insufficient_points", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodGet, "/v1/boards/:type/posts/:id/comments", HandleCommentsV1, openapi.Operation{
+			Summary: "댓글 목록", Tags: tags,
+			Query: []openapi.Param{
+				{Name: "page", Type: "integer"},
+				{Name: "per_page", Type: "integer", Description: "기본 100, 최대 MAX_PAGE_SIZE"},
+			},
+			Responses: []openapi.ResponseDoc{{Status: 200, Body: Envelope[[]CommentItem]{}}, v1InvalidResponse, v1LoginResponse, v1SecretResponse, v1NotFoundResponse, v1ErrorResponse},
+		}),
+	}
+}
+
+// FeedRoutes returns the cross-board routes, relative to /api
+func FeedRoutes() []Route {
+	tags := []string{"feed"}
+	feedQuery := []openapi.Param{
+		{Name: "boards", Type: "string", Description: "쉼표로 구분한 bo_table (기본 전체 게시판). 목록 권한이 없는 게시판은 제외"},
+		{Name: "page", Type: "integer", Description: "page * per_page 는 1000 이하"},
+		{Name: "per_page", Type: "integer", Description: "기본 20"},
+	}
+	return []Route{
+		route(fiber.MethodGet, "/latest", HandleLatest, openapi.Operation{
+			Summary: "전체 게시판 최신글", Tags: tags, Query: feedQuery,
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[[]FeedItem]{}},
+				{Status: 400, Description: "invalid_page", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodGet, "/search", HandleCrossSearch, openapi.Operation{
+			Summary: "전체 게시판 검색", Tags: tags,
+			Query: append(searchQuery[:3:3], feedQuery...),
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[[]FeedItem]{}},
+				{Status: 400, Description: "invalid_search, invalid_page", Body: ErrorEnvelope{}},
+			},
+		}),
+	}
+}
+
+// AuthRoutes returns the login and member routes, relative to /api
+func AuthRoutes() []Route {
+	tags := []string{"auth"}
+	return []Route{
+		route(fiber.MethodPost, "/auth/login", HandleLogin, openapi.Operation{
+			Summary: "로그인 (세션 쿠키 발급)", Tags: tags, Body: LoginRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[MemberInfo]{}},
+				{Status: 400, Description: "invalid_request", Body: ErrorEnvelope{}},
+				{Status: 401, Description: "invalid_credentials", Body: ErrorEnvelope{}},
+				{Status: 403, Description: "member_left, member_intercepted", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPost, "/auth/token", HandleToken, openapi.Operation{
+			Summary: "JWT 발급", Tags: tags, Body: LoginRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[TokenPair]{}},
+				{Status: 400, Description: "invalid_request", Body: ErrorEnvelope{}},
+				{Status: 401, Description: "invalid_credentials", Body: ErrorEnvelope{}},
+				{Status: 403, Description: "member_left, member_intercepted", Body: ErrorEnvelope{}},
+				{Status: 503, Description: "tokens_disabled", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPost, "/auth/refresh", HandleRefresh, openapi.Operation{
+			Summary: "JWT 재발급 (refresh_token 은 한 번만 사용)", Tags: tags, Body: RefreshRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[TokenPair]{}},
+				{Status: 400, Description: "invalid_request", Body: ErrorEnvelope{}},
+				{Status: 401, Description: "invalid_token, token_expired", Body: ErrorEnvelope{}},
+				{Status: 503, Description: "tokens_disabled", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPost, "/auth/logout", HandleLogout, openapi.Operation{
+			Summary: "로그아웃 (Bearer 토큰과 본문의 refresh_token 도 폐기)", Tags: tags,
+			Responses: []openapi.ResponseDoc{{Status: 204}},
+		}),
+		route(fiber.MethodGet, "/me", HandleMe, openapi.Operation{
+			Summary: "로그인한 회원 정보", Tags: tags,
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[MemberInfo]{}},
+				{Status: 401, Description: "unauthorized", Body: ErrorEnvelope{}},
+			},
+		}),
+		route(fiber.MethodGet, "/me/points", HandleMyPoints, openapi.Operation{
+			Summary: "로그인한 회원의 포인트 내역 (g5_point, 최신순)", Tags: tags,
+			Query: []openapi.Param{
+				{Name: "page", Type: "integer"},
+				{Name: "per_page", Type: "integer", Description: "기본 15, 최대 MAX_PAGE_SIZE"},
+			},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[[]PointItem]{}},
+				{Status: 401, Description: "unauthorized", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+	}
+}
+
+// WriteRoutes returns the post write routes, relative to /api. They answer
+// in the v1 format.
+func WriteRoutes() []Route {
+	tags := []string{"write"}
+	return []Route{
+		route(fiber.MethodPost, "/:type", HandleCreatePost, openapi.Operation{
+			Summary: "글쓰기 (reply_to 가 있으면 답변)", Tags: tags, Body: PostRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 201, Body: Envelope[PostDetail]{}},
+				{Status: 400, Description: "invalid_board, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				v1SecretResponse,
+				v1NotFoundResponse,
+				{Status: 409, Description: "reply_limit", Body: ErrorEnvelope{}},
+				{Status: 422, Description: "validation_failed", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPut, "/:type/:id", HandleUpdatePost, openapi.Operation{
+			Summary: "글 수정 (손님 글은 password 확인)", Tags: tags, Body: PostRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[PostDetail]{}},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, invalid_password", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				{Status: 409, Description: "has_comments (bo_count_modify)", Body: ErrorEnvelope{}},
+				{Status: 422, Description: "validation_failed", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodDelete, "/:type/:id", HandleDeletePost, openapi.Operation{
+			Summary: "글 삭제 (댓글 포함)", Tags: tags, Body: DeleteRequest{}, BodyOptional: true,
+			Responses: []openapi.ResponseDoc{
+				{Status: 204},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, invalid_password", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				{Status: 409, Description: "has_replies, has_comments (bo_count_delete)", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPost, "/:type/:id/good", HandleGood, voteOperation("추천 (회원만, 글마다 한 번)")),
+		route(fiber.MethodPost, "/:type/:id/nogood", HandleNogood, voteOperation("비추천 (회원만, 글마다 한 번)")),
+		route(fiber.MethodPost, "/:type/:id/comments", HandleCreateComment, openapi.Operation{
+			Summary: "댓글 쓰기 (reply_to 가 있으면 답글)", Tags: tags, Body: CommentRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 201, Body: Envelope[CommentItem]{}},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				v1SecretResponse,
+				v1NotFoundResponse,
+				{Status: 409, Description: "reply_limit", Body: ErrorEnvelope{}},
+				{Status: 422, Description: "validation_failed", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodPut, "/:type/:id/comments/:comment_id", HandleUpdateComment, openapi.Operation{
+			Summary: "댓글 수정 (손님 댓글은 password 확인)", Tags: tags, Body: CommentRequest{},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Body: Envelope[CommentItem]{}},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, invalid_password", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				{Status: 409, Description: "has_replies", Body: ErrorEnvelope{}},
+				{Status: 422, Description: "validation_failed", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodDelete, "/:type/:id/comments/:comment_id", HandleDeleteComment, openapi.Operation{
+			Summary: "댓글 삭제 (답글이 있으면 관리자만)", Tags: tags, Body: DeleteRequest{}, BodyOptional: true,
+			Responses: []openapi.ResponseDoc{
+				{Status: 204},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, invalid_password", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				{Status: 409, Description: "has_replies", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+	}
+}
+
+// FileRoutes returns the attachment upload and download routes, relative to /api
+func FileRoutes() []Route {
+	return []Route{
+		route(fiber.MethodPost, "/:type/:id/files", HandleUploadFiles, openapi.Operation{
+			Summary: "첨부파일 올리기 (글 작성자, bo_upload_level)", Tags: []string{"files"},
+			Body: UploadRequest{}, BodyType: fiber.MIMEMultipartForm,
+			Responses: []openapi.ResponseDoc{
+				{Status: 201, Body: Envelope[[]Attachment]{}},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "upload_disabled, forbidden, invalid_password", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				{Status: 409, Description: "too_many_files (bo_upload_count)", Body: ErrorEnvelope{}},
+				{Status: 413, Description: "file_too_large (bo_upload_size)", Body: ErrorEnvelope{}},
+				{Status: 415, Description: "file_type_not_allowed", Body: ErrorEnvelope{}},
+				{Status: 422, Description: "validation_failed", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodGet, "/:type/:id/files/:no", HandleFileDownload, openapi.Operation{
+			Summary: "첨부파일 다운로드 (Range 지원, bo_download_level, bo_download_point)", Tags: []string{"files"},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Description: "파일 (application/octet-stream)"},
+				{Status: 206, Description: "Range 요청의 일부"},
+				{Status: 400, Description: "invalid_board, invalid_id", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				{Status: 403, Description: "forbidden, secret_post (비밀글), insufficient_points (bo_download_point)", Body: ErrorEnvelope{}},
+				v1NotFoundResponse,
+				{Status: 416, Description: "파일 크기를 벗어난 Range", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+		route(fiber.MethodGet, "/:type/:id/thumbnail", HandleThumbnail, openapi.Operation{
+			Summary: "갤러리 썸네일 (목록의 thumbnail 주소)", Tags: []string{"files"},
+			Query: []openapi.Param{
+				{Name: "size", Type: "string", Description: "WxH, 기본 bo_gallery_width x bo_gallery_height, 그 밖에는 THUMBNAIL_SIZES 만"},
+				{Name: "v", Type: "string", Description: "캐시 구분용, 서버는 무시"},
+			},
+			Responses: []openapi.ResponseDoc{
+				{Status: 200, Description: "썸네일 이미지 (원본 형식), Cache-Control 1년"},
+				{Status: 400, Description: "invalid_board, invalid_id, invalid_request (허용하지 않는 size)", Body: ErrorEnvelope{}},
+				v1LoginResponse,
+				v1SecretResponse,
+				{Status: 404, Description: "not_found (갤러리 게시판이 아니거나 이미지가 없는 글)", Body: ErrorEnvelope{}},
+				v1ErrorResponse,
+			},
+		}),
+	}
+}
+
+func voteOperation(summary string) openapi.Operation {
+	return openapi.Operation{
+		Summary: summary, Tags: []string{"write"},
+		Responses: []openapi.ResponseDoc{
+			{Status: 200, Body: Envelope[VoteResult]{}},
+			{Status: 400, Description: "invalid_board, invalid_id", Body: ErrorEnvelope{}},
+			v1LoginResponse,
+			{Status: 403, Description: "vote_disabled, own_post, forbidden, secret_post", Body: ErrorEnvelope{}},
+			v1NotFoundResponse,
+			{Status: 409, Description: "already_voted", Body: ErrorEnvelope{}},
+			v1ErrorResponse,
+		},
+	}
+}
+
+// LegacyRoutes returns the frozen Korean-keyed routes, relative to /api
+func LegacyRoutes() []Route {
+	tags := []string{"legacy"}
+	return []Route{
+		route(fiber.MethodGet, "/:type", HandleBoardAPI, openapi.Operation{
+			Summary: "게시글 목록 (레거시)", Tags: tags,
+			Query: []openapi.Param{
+				{Name: "page", Type: "integer"},
+				{Name: "limit", Type: "integer", Description: "최대 MAX_PAGE_SIZE"},
+			},
+			Responses: []openapi.ResponseDoc{{Status: 200, Body: LegacyPostList{}}, legacyInvalidResponse, legacyLoginResponse, legacyForbiddenResponse, legacyErrorResponse},
+		}),
+		route(fiber.MethodGet, "/:type/:id", HandlePostAPI, openapi.Operation{
+			Summary: "게시글 상세 (레거시)", Tags: tags,
+			Responses: []openapi.ResponseDoc{{Status: 200, Body: LegacyPost{}}, legacyInvalidResponse, legacyLoginResponse, legacyForbiddenResponse, legacyNotFoundResponse, legacyErrorResponse},
+		}),
+		route(fiber.MethodGet, "/:type/:id/comments", HandleCommentsAPI, openapi.Operation{
+			Summary: "댓글 목록 (레거시)", Tags: tags,
+			Query: []openapi.Param{
+				{Name: "page", Type: "integer"},
+				{Name: "limit", Type: "integer", Description: "기본 100, 최대 MAX_PAGE_SIZE"},
+			},
+			Responses: []openapi.ResponseDoc{{Status: 200, Body: LegacyCommentList{}}, legacyInvalidResponse, legacyLoginResponse, legacyForbiddenResponse, legacyNotFoundResponse, legacyErrorResponse},
+		}),
+	}
+}
+
+// APIRoutes returns every documented /api route in registration order
+func APIRoutes() []Route {
+	list := append(V1Routes(), FeedRoutes()...)
+	list = append(list, AuthRoutes()...)
+	list = append(list, WriteRoutes()...)
+	list = append(list, FileRoutes()...)
+	return append(list, LegacyRoutes()...)
+}
+
+// APIDocs returns the documentation of APIRoutes
+func APIDocs() []openapi.Route {
+	var docs []openapi.Route
+	for _, r := range APIRoutes() {
+		docs = append(docs, r.Route)
+	}
+	return docs
+}
+
+// RegisterAPI mounts all /api routes on the group
+func RegisterAPI(api fiber.Router) {
+	// v1 은 레거시 /:type/:id 보다 먼저 등록
+	for _, r := range V1Routes() {
+		api.Add(r.Method, r.Path, r.Handler)
+	}
+
+	// v1 아래 없는 경로는 레거시 라우트로 넘기지 않음
+	api.All("/v1/*", func(c *fiber.Ctx) error {
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "요청하신 API 를 찾을 수 없습니다")
+	})
+
+	// /latest, /search 도 /:type 보다 먼저
+	for _, r := range FeedRoutes() {
+		api.Add(r.Method, r.Path, r.Handler)
+	}
+
+	// /auth/*, /me
+	for _, r := range AuthRoutes() {
+		api.Add(r.Method, r.Path, r.Handler)
+	}
+
+	// 글쓰기 (POST/PUT/DELETE 라 레거시 GET 과 겹치지 않음)
+	for _, r := range WriteRoutes() {
+		api.Add(r.Method, r.Path, r.Handler)
+	}
+
+	for _, r := range FileRoutes() {
+		api.Add(r.Method, r.Path, r.Handler)
+	}
+
+	// 레거시 (한글 키, 변경 없음)
+	for _, r := range LegacyRoutes() {
+		api.Add(r.Method, r.Path, r.Handler)
+	}
+}
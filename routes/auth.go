@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fibergo/auth"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// SessionCookie is the name of the session cookie. The store passed to
+// InitAuth must read its id from this cookie.
+const SessionCookie = "g5go_session"
+
+// 세션에 저장하는 회원 아이디 키 (그누보드와 같은 이름)
+const sessionMemberKey = "ss_mb_id"
+
+var members repository.MemberRepository
+var sessions *session.Store
+
+// InitAuth sets the member repository and the session store used by login
+func InitAuth(memberRepo repository.MemberRepository, store *session.Store) {
+	members = memberRepo
+	sessions = store
+}
+
+// LoginRequest is the body of POST /api/auth/login (JSON or form)
+type LoginRequest struct {
+	ID       string `json:"mb_id" form:"mb_id"`
+	Password string `json:"mb_password" form:"mb_password"`
+}
+
+// MemberInfo is the logged-in member
+type MemberInfo struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Nick     string    `json:"nick"`
+	Email    string    `json:"email"`
+	Level    int       `json:"level"`
+	Point    int       `json:"point"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+func toMemberInfo(m *models.Member) MemberInfo {
+	return MemberInfo{
+		ID:       m.ID,
+		Name:     m.Name,
+		Nick:     m.Nick,
+		Email:    m.Email,
+		Level:    m.Level,
+		Point:    m.Point,
+		JoinedAt: m.Datetime,
+	}
+}
+
+// LoadMember attaches the logged-in member to the request: first from the
+// session cookie of this service, then from the PHP session if enabled.
+// Sessions of members who have since left or been blocked are dropped.
+func LoadMember(c *fiber.Ctx) error {
+	if m := sessionMember(c); m != nil {
+		auth.SetMember(c, m)
+	} else if m := phpSessionMember(c); m != nil {
+		auth.SetMember(c, m)
+	}
+	return c.Next()
+}
+
+func sessionMember(c *fiber.Ctx) *models.Member {
+	if sessions == nil || members == nil || c.Cookies(SessionCookie) == "" {
+		return nil
+	}
+	sess, err := sessions.Get(c)
+	if err != nil {
+		return nil
+	}
+	id, _ := sess.Get(sessionMemberKey).(string)
+	if id == "" {
+		return nil
+	}
+
+	m, err := members.GetMember(c.UserContext(), id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		sess.Destroy()
+	case err != nil:
+		log.Printf("회원 조회 실패: %v", err)
+	case auth.CheckMember(m, time.Now()) != nil:
+		sess.Destroy()
+	default:
+		return m
+	}
+	return nil
+}
+
+// HandleLogin verifies mb_id / mb_password and starts a session
+func HandleLogin(c *fiber.Ctx) error {
+	m, err := authenticate(c)
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	sess, err := sessions.Get(c)
+	if err == nil {
+		// 세션 고정 방지
+		err = sess.Regenerate()
+	}
+	if err == nil {
+		sess.Set(sessionMemberKey, m.ID)
+		err = sess.Save()
+	}
+	if err != nil {
+		log.Printf("세션 저장 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "로그인 중 오류가 발생했습니다")
+	}
+
+	return c.JSON(Envelope[MemberInfo]{Data: toMemberInfo(m)})
+}
+
+// authenticate checks the LoginRequest body against g5_member
+func authenticate(c *fiber.Ctx) (*models.Member, error) {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil || strings.TrimSpace(req.ID) == "" || req.Password == "" {
+		return nil, errEmptyLogin
+	}
+	return auth.Authenticate(c.UserContext(), members, strings.TrimSpace(req.ID), req.Password)
+}
+
+var errEmptyLogin = errors.New("empty login request")
+
+// loginError answers a failed authenticate
+func loginError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errEmptyLogin):
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "아이디와 비밀번호를 입력하세요")
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return v1Error(c, fiber.StatusUnauthorized, ErrCodeInvalidCredentials, "가입된 회원아이디가 아니거나 비밀번호가 틀립니다")
+	case errors.Is(err, auth.ErrIntercepted):
+		return v1Error(c, fiber.StatusForbidden, ErrCodeMemberIntercepted, "접근이 금지된 아이디입니다")
+	case errors.Is(err, auth.ErrLeft):
+		return v1Error(c, fiber.StatusForbidden, ErrCodeMemberLeft, "탈퇴한 아이디입니다")
+	}
+	log.Printf("로그인 실패: %v", err)
+	return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "로그인 중 오류가 발생했습니다")
+}
+
+// HandleLogout ends the session and revokes the bearer token and the
+// refresh_token in the body, if any
+func HandleLogout(c *fiber.Ctx) error {
+	revokeTokens(c)
+
+	if c.Cookies(SessionCookie) != "" {
+		if sess, err := sessions.Get(c); err == nil {
+			if err := sess.Destroy(); err != nil {
+				log.Printf("세션 삭제 실패: %v", err)
+			}
+		}
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// HandleMe returns the logged-in member
+func HandleMe(c *fiber.Ctx) error {
+	m := auth.CurrentMember(c)
+	if m == nil {
+		return v1Unauthorized(c)
+	}
+	return c.JSON(Envelope[MemberInfo]{Data: toMemberInfo(m)})
+}
+
+func v1Unauthorized(c *fiber.Ctx) error {
+	return v1Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, "로그인이 필요합니다")
+}
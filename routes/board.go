@@ -1,192 +1,243 @@
 package routes
 
 import (
-	"database/sql"
+	"html/template"
+	"net/url"
+	"strconv"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+	"fibergo/search"
+
 	"github.com/gofiber/fiber/v2"
-	"log"
 )
 
-var db *sql.DB
+// 레거시 API 의 날짜 형식
+const legacyTimeFormat = "2006-01-02 15:04:05"
+
+var posts repository.PostRepository
+var comments repository.CommentRepository
+var boards *board.Registry
+
+// InitRepositories sets the data access used by the handlers
+func InitRepositories(postRepo repository.PostRepository, commentRepo repository.CommentRepository) {
+	posts = postRepo
+	comments = commentRepo
+}
+
+// InitBoards sets the board registry consulted by every handler
+func InitBoards(registry *board.Registry) {
+	boards = registry
+}
 
-// InitDB initializes the database connection for routes
-func InitDB(database *sql.DB) {
-	db = database
+// LookupBoard returns the registered board for bo_table
+func LookupBoard(boardType string) (board.Board, bool) {
+	if boards == nil {
+		return board.Board{}, false
+	}
+	return boards.Get(boardType)
 }
 
 // HandleBoardSSR handles server-side rendering for board pages
 func HandleBoardSSR(c *fiber.Ctx) error {
 	boardType := c.Params("type")
-	postId := c.Params("id")
-	
+
 	// 게시판 타입 검증
-	allowedBoards := map[string]bool{
-		"free":    true,
-		"notice":  true,
-		"gallery": true,
-	}
-	
-	if !allowedBoards[boardType] {
+	bo, ok := LookupBoard(boardType)
+	if !ok {
 		return c.Status(400).JSON(fiber.Map{
 			"error": "유효하지 않은 게시판입니다",
 		})
 	}
 
 	// 상세 페이지도 SSR로 처리
-	if postId != "" {
-		// 게시글 데이터 조회
-		tableName := "g5_write_" + boardType
-		query := `
-			SELECT wr_id, wr_subject, wr_name, wr_datetime, wr_hit, wr_good, wr_content
-			FROM ` + tableName + `
-			WHERE wr_id = ?
-		`
-		
-		var post struct {
-			ID       int    `json:"id"`
-			제목     string `json:"제목"`
-			이름     string `json:"이름"`
-			날짜     string `json:"날짜"`
-			조회     int    `json:"조회"`
-			추천     int    `json:"추천"`
-			내용     string `json:"내용"`
-		}
-		
-		err := db.QueryRow(query, postId).Scan(
-			&post.ID, &post.제목, &post.이름, &post.날짜, 
-			&post.조회, &post.추천, &post.내용,
-		)
-		
+	if c.Params("id") != "" {
+		postId, err := c.ParamsInt("id")
 		if err != nil {
-			if err == sql.ErrNoRows {
-				return c.Status(404).SendFile("templates/404.html")
+			return fiber.ErrNotFound
+		}
+		if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+			return denied
+		}
+
+		// 게시글 데이터 조회 (손님의 비밀글은 비밀번호 입력 화면)
+		post, e := readablePost(c, bo, postId)
+		if e != nil {
+			if e.Code == fiber.StatusForbidden && post.MemberID == "" && post.Password != "" {
+				return renderPasswordForm(c, bo, *post, "")
 			}
-			return c.Status(500).SendFile("templates/500.html")
+			return e
+		}
+		if e := chargeReadPoint(c, bo, *post); e != nil {
+			return e
 		}
 
 		// 조회수 증가
-		updateQuery := `UPDATE ` + tableName + ` SET wr_hit = wr_hit + 1 WHERE wr_id = ?`
-		_, err = db.Exec(updateQuery, postId)
+		countHit(c, bo, postId)
+
+		// 댓글도 SSR (cpage 로 페이지 이동)
+		cpage := c.QueryInt("cpage", 1)
+		if cpage < 1 {
+			cpage = 1
+		}
+		perPage := min(defaultCommentPageSize, maxPageSize)
+		list, total, err := commentPage(c.UserContext(), bo.Table, postId, cpage, perPage)
 		if err != nil {
-			log.Printf("조회수 증가 실패: %v", err)
+			return fiber.ErrInternalServerError
+		}
+		redactComments(c, bo, *post, list)
+
+		data := fiber.Map{
+			"Title":        bo.Subject,
+			"BoardType":    boardType,
+			"Post":         post,
+			"ContentHTML":  template.HTML(postHTML(*post)),
+			"Comments":     commentRows(list),
+			"CommentTotal": total,
+			"Files":        attachments(c, bo, *post),
+		}
+		postURL := "/" + bo.Table + "/" + strconv.Itoa(postId)
+		if cpage > 1 {
+			data["PrevCommentsURL"] = postURL + "?cpage=" + strconv.Itoa(cpage-1) + "#comments"
 		}
+		if cpage*perPage < total {
+			data["NextCommentsURL"] = postURL + "?cpage=" + strconv.Itoa(cpage+1) + "#comments"
+		}
+		return c.Render("board_view", data)
+	}
 
-		// SSR로 상세 페이지 렌더링
-		return c.Render("board_view", fiber.Map{
-			"Title": getBoardTitle(boardType),
-			"BoardType": boardType,
-			"Post": post,
-		})
+	if denied := boardAccess(c, bo, board.ActionList); denied != nil {
+		return denied
 	}
 
-	// 목록 페이지는 SSR로 처리
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 20)
-	offset := (page - 1) * limit
+	// 검색어가 있으면 검색 결과 목록
+	if c.Query("stx") != "" {
+		return renderSearch(c, bo)
+	}
 
-	// DB에서 게시글 목록 조회
-	tableName := "g5_write_" + boardType
-	query := `
-		SELECT wr_id, wr_subject, wr_name, wr_datetime, wr_hit, wr_good
-		FROM ` + tableName + `
-		WHERE wr_is_comment = 0
-		ORDER BY wr_num DESC
-		LIMIT ? OFFSET ?
-	`
+	// 목록 페이지 처리
+	page := pageNumber(c)
+	limit := pageSize(c, "limit", bo.PageRows)
+	offset := (page - 1) * limit
 
 	// 전체 게시글 수 조회
-	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM ` + tableName + ` WHERE wr_is_comment = 0`
-	err := db.QueryRow(countQuery).Scan(&totalCount)
+	totalCount, err := posts.CountPosts(c.UserContext(), bo.Table)
 	if err != nil {
-		return c.Status(500).SendFile("templates/500.html")
+		return fiber.ErrInternalServerError
 	}
 
-	// 게시글 목록 조회
-	rows, err := db.Query(query, limit, offset)
+	list, err := posts.ListPosts(c.UserContext(), bo.Table, repository.ListOptions{Offset: offset, Limit: limit})
 	if err != nil {
-		return c.Status(500).SendFile("templates/500.html")
+		return fiber.ErrInternalServerError
+	}
+
+	data := listPageData(c, bo, list, nil)
+	data["Total"] = totalCount
+	data["Page"] = page
+	if page > 1 {
+		data["PrevURL"] = listURL(bo, url.Values{"page": {strconv.Itoa(page - 1)}})
 	}
-	defer rows.Close()
+	if offset+len(list) < totalCount {
+		data["NextURL"] = listURL(bo, url.Values{"page": {strconv.Itoa(page + 1)}})
+	}
+	return c.Render("board_list", data)
+}
 
-	var posts []map[string]interface{}
-	for rows.Next() {
-		var (
-			wr_id                             int
-			wr_subject, wr_name, wr_datetime string
-			wr_hit, wr_good                  int
-		)
+// renderSearch renders the board list with search results of the current spt segment
+func renderSearch(c *fiber.Ctx, bo board.Board) error {
+	result, err := runSearch(c, bo, pageSize(c, "limit", bo.PageRows))
+	if err == search.ErrInvalidField {
+		return fiber.NewError(fiber.StatusBadRequest, "지원하지 않는 검색 필드입니다")
+	}
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
 
-		err := rows.Scan(&wr_id, &wr_subject, &wr_name, &wr_datetime, &wr_hit, &wr_good)
-		if err != nil {
-			continue
+	params := func(spt, page int) url.Values {
+		return url.Values{
+			"sfl":  {result.Query.SFL},
+			"stx":  {c.Query("stx")},
+			"sop":  {c.Query("sop", "and")},
+			"spt":  {strconv.Itoa(spt)},
+			"page": {strconv.Itoa(page)},
 		}
+	}
 
-		posts = append(posts, map[string]interface{}{
-			"id":       wr_id,
-			"제목":      wr_subject,
-			"작성자":     wr_name,
-			"작성일":     wr_datetime,
-			"조회수":     wr_hit,
-			"추천수":     wr_good,
-			"BoardType": boardType,
-		})
+	data := listPageData(c, bo, result.Posts, result.Query.Words)
+	data["Total"] = result.Total
+	data["Page"] = result.Page
+	data["Search"] = true
+	data["SFL"] = result.Query.SFL
+	data["STX"] = c.Query("stx")
+	data["SOP"] = c.Query("sop", "and")
+	if result.Page > 1 {
+		data["PrevURL"] = listURL(bo, params(result.Spt, result.Page-1))
+	}
+	if result.Page*result.PerPage < result.Total {
+		data["NextURL"] = listURL(bo, params(result.Spt, result.Page+1))
 	}
+	if result.PrevSpt != nil {
+		data["PrevSptURL"] = listURL(bo, params(*result.PrevSpt, 1))
+	}
+	if result.NextSpt != nil {
+		data["NextSptURL"] = listURL(bo, params(*result.NextSpt, 1))
+	}
+	return c.Render("board_list", data)
+}
 
-	// SSR 템플릿 렌더링
-	return c.Render("board_list", fiber.Map{
-		"BoardType": boardType,
-		"Title":     getBoardTitle(boardType),
-		"Posts":     posts,
-		"Total":     totalCount,
-		"Page":      page,
-	})
+func listPageData(c *fiber.Ctx, bo board.Board, list []models.Post, words []string) fiber.Map {
+	return fiber.Map{
+		"BoardType":    bo.Table,
+		"Title":        bo.Subject,
+		"Gallery":      bo.IsGallery(),
+		"Posts":        listRows(c, bo, list, words),
+		"SFL":          search.DefaultField,
+		"SOP":          "and",
+		"FieldOptions": search.FieldOptions,
+	}
 }
 
-func getBoardTitle(boardType string) string {
-	titles := map[string]string{
-		"free":    "자유게시판",
-		"notice":  "공지사항",
-		"gallery": "갤러리",
+// commentRow is a comment of the SSR view with its converted content
+type commentRow struct {
+	models.Comment
+	ContentHTML template.HTML
+}
+
+func commentRows(list []models.Comment) []commentRow {
+	rows := make([]commentRow, 0, len(list))
+	for _, cm := range list {
+		rows = append(rows, commentRow{Comment: cm, ContentHTML: template.HTML(commentHTML(cm))})
 	}
-	return titles[boardType]
+	return rows
+}
+
+func listURL(bo board.Board, params url.Values) string {
+	return "/" + bo.Table + "?" + params.Encode()
 }
 
 // HandleBoardAPI handles API requests for board data
 func HandleBoardAPI(c *fiber.Ctx) error {
 	boardType := c.Params("type")
-	
+
 	// 게시판 타입 검증
-	allowedBoards := map[string]bool{
-		"free":    true,
-		"notice":  true,
-		"gallery": true,
-	}
-	
-	if !allowedBoards[boardType] {
+	bo, ok := LookupBoard(boardType)
+	if !ok {
 		return c.Status(400).JSON(fiber.Map{
 			"error": "유효하지 않은 게시판입니다",
 		})
 	}
+	if denied := boardAccess(c, bo, board.ActionList); denied != nil {
+		return legacyDenied(c, denied)
+	}
 
 	// 페이지 정보
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 20)
+	page := pageNumber(c)
+	limit := pageSize(c, "limit", bo.PageRows)
 	offset := (page - 1) * limit
 
-	// DB에서 게시글 목록 조회
-	tableName := "g5_write_" + boardType
-	query := `
-		SELECT wr_id, wr_subject, wr_name, wr_datetime, wr_hit, wr_good
-		FROM ` + tableName + `
-		WHERE wr_is_comment = 0
-		ORDER BY wr_num DESC
-		LIMIT ? OFFSET ?
-	`
-
 	// 전체 게시글 수 조회
-	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM ` + tableName + ` WHERE wr_is_comment = 0`
-	err := db.QueryRow(countQuery).Scan(&totalCount)
+	totalCount, err := posts.CountPosts(c.UserContext(), bo.Table)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "게시글 수 조회 중 오류가 발생했습니다",
@@ -194,103 +245,97 @@ func HandleBoardAPI(c *fiber.Ctx) error {
 	}
 
 	// 게시글 목록 조회
-	rows, err := db.Query(query, limit, offset)
+	list, err := posts.ListPosts(c.UserContext(), bo.Table, repository.ListOptions{Offset: offset, Limit: limit})
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "게시글 목록 조회 중 오류가 발생했습니다",
 		})
 	}
-	defer rows.Close()
 
-	var posts []fiber.Map
-	for rows.Next() {
-		var (
-			wr_id                                     int
-			wr_subject, wr_name, wr_datetime         string
-			wr_hit, wr_good                          int
-		)
-
-		err := rows.Scan(&wr_id, &wr_subject, &wr_name, &wr_datetime, &wr_hit, &wr_good)
-		if err != nil {
-			continue
-		}
-
-		posts = append(posts, fiber.Map{
-			"id":     wr_id,
-			"제목":    wr_subject,
-			"작성자":   wr_name,
-			"작성일":   wr_datetime,
-			"조회수":   wr_hit,
-			"추천수":   wr_good,
-		})
+	var result []LegacyPostSummary
+	for _, p := range list {
+		result = append(result, legacyPostSummary(p))
 	}
 
-	return c.JSON(fiber.Map{
-		"게시판":   boardType,
-		"현재페이지": page,
-		"전체개수":  totalCount,
-		"게시글":   posts,
+	return c.JSON(LegacyPostList{
+		Board: boardType,
+		Page:  page,
+		Total: totalCount,
+		Posts: result,
 	})
 }
 
 // HandleCommentsAPI handles comment loading for a post
 func HandleCommentsAPI(c *fiber.Ctx) error {
 	boardType := c.Params("type")
-	postId := c.Params("id")
 
 	// 게시판 타입 검증
-	if !isValidBoardType(boardType) {
+	bo, ok := LookupBoard(boardType)
+	if !ok {
 		return c.Status(400).JSON(fiber.Map{
 			"error": "유효하지 않은 게시판입니다",
 		})
 	}
 
-	tableName := "g5_write_" + boardType
-	query := `
-		SELECT 
-			wr_id,
-			wr_content,
-			wr_name,
-			wr_datetime,
-			wr_parent
-		FROM ` + tableName + `
-		WHERE wr_is_comment = 1 
-		AND wr_parent = ?
-		ORDER BY wr_comment, wr_comment_reply
-	`
-
-	rows, err := db.Query(query, postId)
+	postId, err := c.ParamsInt("id")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "잘못된 게시글 ID입니다",
+		})
+	}
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return legacyDenied(c, denied)
+	}
+
+	post, e := readablePost(c, bo, postId)
+	if e != nil {
+		return c.Status(e.Code).JSON(fiber.Map{
+			"error": e.Message,
+		})
+	}
+
+	page := pageNumber(c)
+	limit := pageSize(c, "limit", defaultCommentPageSize)
+	list, total, err := commentPage(c.UserContext(), bo.Table, postId, page, limit)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"error": "댓글 조회 중 오류가 발생했습니다",
 		})
 	}
-	defer rows.Close()
+	redactComments(c, bo, *post, list)
 
-	var comments []fiber.Map
-	for rows.Next() {
-		var (
-			wr_id                             int
-			wr_content, wr_name, wr_datetime string
-			wr_parent                        int
-		)
+	result := make([]LegacyComment, 0, len(list))
+	for _, cm := range list {
+		result = append(result, legacyComment(cm))
+	}
 
-		err := rows.Scan(&wr_id, &wr_content, &wr_name, &wr_datetime, &wr_parent)
-		if err != nil {
-			continue
-		}
+	return c.JSON(LegacyCommentList{
+		Count:    total,
+		Page:     page,
+		Limit:    limit,
+		Comments: result,
+	})
+}
 
-		comments = append(comments, fiber.Map{
-			"id":     wr_id,
-			"내용":    wr_content,
-			"작성자":   wr_name,
-			"날짜":    wr_datetime,
-			"부모글ID": wr_parent,
-		})
+func legacyPostSummary(p models.Post) LegacyPostSummary {
+	return LegacyPostSummary{
+		ID:       p.ID,
+		Subject:  p.Subject,
+		Name:     p.Name,
+		Datetime: p.Datetime.Format(legacyTimeFormat),
+		Hit:      p.Hit,
+		Good:     p.Good,
 	}
+}
 
-	return c.JSON(fiber.Map{
-		"count":    len(comments),
-		"comments": comments,
-	})
-}
\ No newline at end of file
+func legacyComment(cm models.Comment) LegacyComment {
+	return LegacyComment{
+		ID:       cm.ID,
+		Content:  commentHTML(cm),
+		Name:     cm.Name,
+		Datetime: cm.Datetime.Format(legacyTimeFormat),
+		Parent:   cm.Parent,
+		Depth:    cm.Depth(),
+		ReplyTo:  cm.ReplyTo,
+	}
+}
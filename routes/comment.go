@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"context"
+
+	"fibergo/models"
+	"fibergo/repository"
+)
+
+// 댓글 한 페이지 기본 개수 (최대 MAX_PAGE_SIZE)
+const defaultCommentPageSize = 100
+
+// commentPage returns one page of a post's comments and the total count.
+// Replies keep ReplyTo even when the parent is on an earlier page.
+func commentPage(ctx context.Context, table string, postID, page, perPage int) ([]models.Comment, int, error) {
+	total, err := comments.CountComments(ctx, table, postID)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := comments.ListComments(ctx, table, postID, repository.ListOptions{
+		Offset: (page - 1) * perPage,
+		Limit:  perPage,
+	})
+	return list, total, err
+}
@@ -0,0 +1,237 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CommentRequest is the body of POST /api/{board}/{id}/comments and
+// PUT /api/{board}/{id}/comments/{comment_id}
+type CommentRequest struct {
+	Content string `json:"content" form:"wr_content"`
+	Secret  bool   `json:"secret,omitempty" form:"secret"`
+	ReplyTo int    `json:"reply_to,omitempty" form:"comment_id" doc:"답글을 달 댓글 ID (작성할 때만)"`
+
+	// 손님 댓글: 작성할 때 이름과 비밀번호 필수, 수정할 때는 비밀번호로 확인
+	Name     string `json:"name,omitempty" form:"wr_name"`
+	Password string `json:"password,omitempty" form:"wr_password"`
+	Email    string `json:"email,omitempty" form:"wr_email"`
+	Homepage string `json:"homepage,omitempty" form:"wr_homepage"`
+}
+
+// validateComment checks the request like write_comment_update.php
+func validateComment(req CommentRequest, guest bool) map[string]string {
+	fields := map[string]string{}
+	if strings.TrimSpace(req.Content) == "" {
+		fields["content"] = "내용을 입력하세요"
+	}
+	if guest {
+		if strings.TrimSpace(req.Name) == "" {
+			fields["name"] = "이름을 입력하세요"
+		}
+		if req.Password == "" {
+			fields["password"] = "비밀번호를 입력하세요"
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+func commentOptions(req CommentRequest) string {
+	if req.Secret {
+		return "secret"
+	}
+	return ""
+}
+
+func v1CommentWriteFailed(c *fiber.Ctx, err error) error {
+	log.Printf("댓글 저장 실패: %v", err)
+	return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "댓글 저장 중 오류가 발생했습니다")
+}
+
+// findComment loads a comment of post id: 400 for a bad ID, 404 if it is
+// missing or belongs to another post
+func findComment(c *fiber.Ctx, bo board.Board, postID int) (*models.Comment, *fiber.Error) {
+	id, err := c.ParamsInt("comment_id")
+	if err != nil || id <= 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "잘못된 댓글 ID입니다")
+	}
+	return commentOf(c, bo, postID, id)
+}
+
+func commentOf(c *fiber.Ctx, bo board.Board, postID, id int) (*models.Comment, *fiber.Error) {
+	cm, err := comments.GetComment(c.UserContext(), bo.Table, id)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && cm.Parent != postID) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "댓글이 존재하지 않습니다")
+	}
+	if err != nil {
+		log.Printf("댓글 조회 실패: %v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return cm, nil
+}
+
+func v1CommentError(c *fiber.Ctx, e *fiber.Error) error {
+	if e.Code == fiber.StatusBadRequest {
+		return v1Error(c, e.Code, ErrCodeInvalidID, e.Message)
+	}
+	return v1PostError(c, e)
+}
+
+// commentHasReplies reports whether cm has answers; admins may change it anyway
+func commentHasReplies(c *fiber.Ctx, bo board.Board, cm models.Comment) (bool, error) {
+	if isBoardAdmin(c, bo) {
+		return false, nil
+	}
+	n, err := commentWriter.CountCommentReplies(c.UserContext(), bo.Table, cm)
+	return n > 0, err
+}
+
+func v1CommentHasReplies(c *fiber.Ctx, verb string) error {
+	return v1Error(c, fiber.StatusConflict, ErrCodeHasReplies, "답글이 달린 댓글은 "+verb+"할 수 없습니다")
+}
+
+// HandleCreateComment writes a comment, or an answer to one if reply_to is set
+func HandleCreateComment(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	var req CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+	}
+	if denied := boardAccess(c, bo, board.ActionComment); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	m := auth.CurrentMember(c)
+	if fields := validateComment(req, m == nil); fields != nil {
+		return v1ValidationError(c, fields)
+	}
+
+	// 댓글은 글을 읽을 수 있어야 작성
+	post, e := readablePost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	var parent *models.Comment
+	if req.ReplyTo != 0 {
+		if parent, e = commentOf(c, bo, post.ID, req.ReplyTo); e != nil {
+			return v1PostError(c, e)
+		}
+	}
+
+	cm := models.Comment{
+		Option:  commentOptions(req),
+		Content: req.Content,
+		IP:      c.IP(),
+	}
+	if m != nil {
+		cm.MemberID, cm.Name, cm.Email = m.ID, m.Nick, m.Email
+	} else {
+		hash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			return v1CommentWriteFailed(c, err)
+		}
+		cm.Name, cm.Password = strings.TrimSpace(req.Name), hash
+		cm.Email, cm.Homepage = req.Email, req.Homepage
+	}
+
+	if err := commentWriter.CreateComment(c.UserContext(), bo, *post, &cm, parent); err != nil {
+		if errors.Is(err, repository.ErrReplyLimit) {
+			return v1Error(c, fiber.StatusConflict, ErrCodeReplyLimit, "더 이상 답글을 달 수 없습니다. 답글은 5단계까지만 가능합니다")
+		}
+		return v1CommentWriteFailed(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(Envelope[CommentItem]{Data: toCommentItem(c, bo, cm)})
+}
+
+// HandleUpdateComment edits a comment
+func HandleUpdateComment(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	var req CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+	}
+
+	cm, e := findComment(c, bo, id)
+	if e != nil {
+		return v1CommentError(c, e)
+	}
+	if status, code, message := ownerDenied(c, bo, cm.MemberID, cm.Password, req.Password); status != 0 {
+		return v1Error(c, status, code, message)
+	}
+	if replied, err := commentHasReplies(c, bo, *cm); err != nil {
+		return v1CommentWriteFailed(c, err)
+	} else if replied {
+		return v1CommentHasReplies(c, "수정")
+	}
+	if fields := validateComment(req, false); fields != nil {
+		return v1ValidationError(c, fields)
+	}
+
+	cm.Option = commentOptions(req)
+	cm.Content = req.Content
+	if err := commentWriter.UpdateComment(c.UserContext(), bo.Table, *cm); err != nil {
+		return v1CommentWriteFailed(c, err)
+	}
+	return c.JSON(Envelope[CommentItem]{Data: toCommentItem(c, bo, *cm)})
+}
+
+// HandleDeleteComment deletes a comment without answers
+func HandleDeleteComment(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	var req DeleteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+		}
+	}
+
+	cm, e := findComment(c, bo, id)
+	if e != nil {
+		return v1CommentError(c, e)
+	}
+	if status, code, message := ownerDenied(c, bo, cm.MemberID, cm.Password, req.Password); status != 0 {
+		return v1Error(c, status, code, message)
+	}
+	if replied, err := commentHasReplies(c, bo, *cm); err != nil {
+		return v1CommentWriteFailed(c, err)
+	} else if replied {
+		return v1CommentHasReplies(c, "삭제")
+	}
+
+	if err := commentWriter.DeleteComment(c.UserContext(), bo.Table, *cm); err != nil {
+		return v1CommentWriteFailed(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/sanitize"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 본문 HTML 허용 목록 (HTML_ALLOWED_TAGS)
+var htmlPolicy = sanitize.Default()
+
+// InitSanitizer sets the allowlist of HTML posts
+func InitSanitizer(p *sanitize.Policy) {
+	if p != nil {
+		htmlPolicy = p
+	}
+}
+
+// contentFormat reads the html1/html2 option of wr_option
+func contentFormat(p models.Post) sanitize.Format {
+	switch {
+	case p.HasOption("html2"):
+		return sanitize.HTMLBreaks
+	case p.HasOption("html1"):
+		return sanitize.HTML
+	default:
+		return sanitize.Text
+	}
+}
+
+func formatName(f sanitize.Format) string {
+	switch f {
+	case sanitize.HTMLBreaks:
+		return "html2"
+	case sanitize.HTML:
+		return "html1"
+	default:
+		return "text"
+	}
+}
+
+// postHTML is wr_content as the view shows it (conv_content)
+func postHTML(p models.Post) string {
+	return htmlPolicy.Content(p.Content, contentFormat(p))
+}
+
+func postText(p models.Post) string {
+	return sanitize.PlainText(p.Content, contentFormat(p))
+}
+
+// commentHTML converts a comment, always plain text in Gnuboard
+func commentHTML(cm models.Comment) string {
+	return sanitize.TextHTML(cm.Content)
+}
+
+// contentSource returns the stored wr_content for the edit form when the
+// caller wrote it or administers bo. Other readers only get content_html
+// and content_text, so a raw payload never reaches them.
+func contentSource(c *fiber.Ctx, bo board.Board, mbID, content string) string {
+	if m := auth.CurrentMember(c); (m != nil && mbID != "" && m.ID == mbID) || isBoardAdmin(c, bo) {
+		return content
+	}
+	return ""
+}
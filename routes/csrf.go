@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"net/url"
+
+	"fibergo/auth"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RejectCrossSite refuses state-changing requests that a browser sent from
+// another site with the member's cookies (CSRF). It must run after
+// LoadMember and LoadToken. Bearer token requests carry no cookies and
+// pass, as do guests and clients that send neither Sec-Fetch-Site nor Origin.
+func RejectCrossSite(c *fiber.Ctx) error {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return c.Next()
+	}
+	if auth.CurrentMember(c) == nil || c.Locals(tokenClaimsKey) != nil {
+		return c.Next()
+	}
+	if !sameOrigin(c) {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeForbidden, "다른 사이트에서 보낸 요청은 처리할 수 없습니다")
+	}
+	return c.Next()
+}
+
+// sameOrigin reports whether the browser says the request came from this
+// site. Sec-Fetch-Site 가 있으면 그것을, 없으면 Origin 의 호스트를 비교
+func sameOrigin(c *fiber.Ctx) bool {
+	switch c.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return false
+	}
+	origin := c.Get(fiber.HeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && u.Host == c.Hostname()
+}
@@ -0,0 +1,299 @@
+package routes
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+	"fibergo/search"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// 통합 목록 기본 페이지 크기
+	defaultFeedSize = 20
+	// 게시판마다 page*per_page 개를 읽어 합치므로 너무 깊은 페이지는 거부
+	maxFeedDepth = 1000
+	// 동시에 조회하는 게시판 수
+	fanOutWorkers = 8
+)
+
+var boardNew repository.BoardNewRepository
+var fanOutTimeout = 3 * time.Second
+
+// InitFeed configures /api/latest and /api/search. If boardNewRepo is set
+// /api/latest reads g5_board_new instead of querying every board.
+func InitFeed(boardNewRepo repository.BoardNewRepository, timeout time.Duration) {
+	boardNew = boardNewRepo
+	if timeout > 0 {
+		fanOutTimeout = timeout
+	}
+}
+
+type feedPost struct {
+	Board board.Board
+	Post  models.Post
+}
+
+// feedBoards returns the boards selected by the boards query parameter (default all)
+func feedBoards(c *fiber.Ctx) []board.Board {
+	if boards == nil {
+		return nil
+	}
+	all := boards.List()
+	selected := board.ParseAllowed(c.Query("boards"))
+	if len(selected) == 0 {
+		return all
+	}
+	want := map[string]bool{}
+	for _, t := range selected {
+		want[t] = true
+	}
+	var list []board.Board
+	for _, b := range all {
+		if want[b.Table] {
+			list = append(list, b)
+		}
+	}
+	return list
+}
+
+// fanOut runs fetch for every board concurrently and merges the results
+// newest first. Boards that fail or miss the deadline are returned in failed.
+func fanOut(ctx context.Context, list []board.Board, fetch func(ctx context.Context, b board.Board) ([]models.Post, error)) ([]feedPost, []string) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		merged []feedPost
+		failed []string
+		sem    = make(chan struct{}, fanOutWorkers)
+	)
+	for _, b := range list {
+		wg.Add(1)
+		go func(b board.Board) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				failed = append(failed, b.Table)
+				mu.Unlock()
+				return
+			}
+
+			result, err := fetch(ctx, b)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("%s 게시판 조회 실패: %v", b.Table, err)
+				failed = append(failed, b.Table)
+				return
+			}
+			for _, p := range result {
+				merged = append(merged, feedPost{Board: b, Post: p})
+			}
+		}(b)
+	}
+	wg.Wait()
+
+	sortFeed(merged)
+	sort.Strings(failed)
+	return merged, failed
+}
+
+// 작성일 내림차순, 같으면 게시판/글 번호 순
+func sortFeed(list []feedPost) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if !a.Post.Datetime.Equal(b.Post.Datetime) {
+			return a.Post.Datetime.After(b.Post.Datetime)
+		}
+		if a.Board.Table != b.Board.Table {
+			return a.Board.Table < b.Board.Table
+		}
+		return a.Post.ID > b.Post.ID
+	})
+}
+
+// feedPage validates page/per_page and returns offset, limit
+func feedPage(c *fiber.Ctx) (page, perPage int, ok bool) {
+	page = pageNumber(c)
+	perPage = pageSize(c, "per_page", defaultFeedSize)
+	return page, perPage, page*perPage <= maxFeedDepth
+}
+
+func invalidFeedPage(c *fiber.Ctx) error {
+	return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidPage, "page * per_page 는 1000 을 넘을 수 없습니다")
+}
+
+// slicePage returns the requested page of a merged list and whether more follow
+func slicePage(list []feedPost, page, perPage int) ([]feedPost, bool) {
+	offset := (page - 1) * perPage
+	if offset >= len(list) {
+		return nil, false
+	}
+	list = list[offset:]
+	if len(list) > perPage {
+		return list[:perPage], true
+	}
+	return list, false
+}
+
+func toFeedItem(fp feedPost) FeedItem {
+	return FeedItem{PostSummary: toPostSummary(fp.Board.Table, fp.Post), BoardName: fp.Board.Subject}
+}
+
+// HandleLatest returns the newest posts across all boards
+func HandleLatest(c *fiber.Ctx) error {
+	page, perPage, ok := feedPage(c)
+	if !ok {
+		return invalidFeedPage(c)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), fanOutTimeout)
+	defer cancel()
+
+	list := permittedBoards(c, feedBoards(c), board.ActionList)
+	var (
+		items   []feedPost
+		failed  []string
+		hasMore bool
+	)
+	if boardNew != nil {
+		var err error
+		items, hasMore, failed, err = latestFromBoardNew(ctx, list, page, perPage)
+		if err != nil {
+			log.Printf("최신글 조회 실패: %v", err)
+			return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "최신글 조회 중 오류가 발생했습니다")
+		}
+	} else {
+		var merged []feedPost
+		merged, failed = fanOut(ctx, list, func(ctx context.Context, b board.Board) ([]models.Post, error) {
+			return posts.LatestPosts(ctx, b.Table, page*perPage+1)
+		})
+		items, hasMore = slicePage(merged, page, perPage)
+	}
+
+	data := make([]FeedItem, 0, len(items))
+	for _, fp := range items {
+		data = append(data, toFeedItem(fp))
+	}
+	return c.JSON(Envelope[[]FeedItem]{
+		Data: data,
+		Meta: &Meta{Page: page, PerPage: perPage, HasMore: hasMore, FailedBoards: failed},
+	})
+}
+
+// latestFromBoardNew reads one page of g5_board_new and loads the posts it points to
+func latestFromBoardNew(ctx context.Context, list []board.Board, page, perPage int) ([]feedPost, bool, []string, error) {
+	byTable := map[string]board.Board{}
+	var tables []string
+	for _, b := range list {
+		byTable[b.Table] = b
+		tables = append(tables, b.Table)
+	}
+
+	entries, err := boardNew.RecentPosts(ctx, tables, (page-1)*perPage, perPage+1)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	hasMore := len(entries) > perPage
+	if hasMore {
+		entries = entries[:perPage]
+	}
+
+	// 게시판별로 한 번에 조회
+	ids := map[string][]int{}
+	for _, e := range entries {
+		ids[e.Table] = append(ids[e.Table], e.PostID)
+	}
+	found := map[string]map[int]models.Post{}
+	var failed []string
+	for table, tableIDs := range ids {
+		result, err := posts.GetPosts(ctx, table, tableIDs)
+		if err != nil {
+			log.Printf("%s 게시판 조회 실패: %v", table, err)
+			failed = append(failed, table)
+			continue
+		}
+		found[table] = map[int]models.Post{}
+		for _, p := range result {
+			found[table][p.ID] = p
+		}
+	}
+	sort.Strings(failed)
+
+	var items []feedPost
+	for _, e := range entries {
+		// 삭제된 글은 g5_board_new 에 남아 있을 수 있음
+		if p, ok := found[e.Table][e.PostID]; ok {
+			items = append(items, feedPost{Board: byTable[e.Table], Post: p})
+		}
+	}
+	return items, hasMore, failed, nil
+}
+
+// HandleCrossSearch searches every board and merges the results by date.
+// Each board is searched within its newest SEARCH_PART posts.
+func HandleCrossSearch(c *fiber.Ctx) error {
+	q, err := search.Parse(c.Query("sfl"), c.Query("stx"), c.Query("sop"))
+	if err != nil {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidSearch, "지원하지 않는 검색 필드입니다")
+	}
+	if q.Empty() {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidSearch, "검색어(stx)를 입력하세요")
+	}
+	page, perPage, ok := feedPage(c)
+	if !ok {
+		return invalidFeedPage(c)
+	}
+
+	ctx, cancel := context.WithTimeout(c.UserContext(), fanOutTimeout)
+	defer cancel()
+
+	// 본문 요약을 보여주므로 읽기 권한까지 필요
+	searchable := permittedBoards(c, feedBoards(c), board.ActionList, board.ActionRead)
+	merged, failed := fanOut(ctx, searchable, func(ctx context.Context, b board.Board) ([]models.Post, error) {
+		minNum, err := posts.MinNum(ctx, b.Table)
+		if err != nil {
+			return nil, err
+		}
+		return posts.SearchPosts(ctx, b.Table, repository.SearchOptions{
+			Query:        q,
+			SegmentStart: minNum,
+			SegmentSize:  searchPart,
+			Limit:        page*perPage + 1,
+		})
+	})
+	items, hasMore := slicePage(merged, page, perPage)
+
+	data := make([]FeedItem, 0, len(items))
+	for _, fp := range items {
+		item := toFeedItem(fp)
+		item.SubjectHTML = search.Highlight(fp.Post.Subject, q.Words)
+		item.SnippetHTML = secretSnippet(c, fp.Board, fp.Post, search.Snippet(fp.Post.Content, q.Words, snippetWidth))
+		data = append(data, item)
+	}
+
+	operator := "or"
+	if q.And {
+		operator = "and"
+	}
+	return c.JSON(Envelope[[]FeedItem]{
+		Data: data,
+		Meta: &Meta{
+			Page:         page,
+			PerPage:      perPage,
+			HasMore:      hasMore,
+			FailedBoards: failed,
+			Search:       &SearchMeta{Field: q.SFL, Words: q.Words, Operator: operator},
+		},
+	})
+}
@@ -0,0 +1,285 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Last-Modified, If-Range 날짜 형식
+const httpTimeFormat = "Mon, 02 Jan 2006 15:04:05 GMT"
+
+var files repository.FileRepository
+
+// 그누보드 data 디렉터리 (첨부파일은 data/file/{bo_table})
+var dataDir string
+
+// InitFiles sets the attachment repository and Gnuboard's data directory
+func InitFiles(repo repository.FileRepository, dir string) {
+	files = repo
+	dataDir = dir
+}
+
+// attachmentURL is the download path of an attachment
+func attachmentURL(f models.BoardFile) string {
+	return "/api/" + f.Table + "/" + strconv.Itoa(f.PostID) + "/files/" + strconv.Itoa(f.No)
+}
+
+func toAttachment(f models.BoardFile) Attachment {
+	return Attachment{
+		No:          f.No,
+		Name:        f.Source,
+		Description: f.Content,
+		Size:        f.Filesize,
+		Width:       f.Width,
+		Height:      f.Height,
+		IsImage:     f.Type > 0,
+		Downloads:   f.Download,
+		URL:         attachmentURL(f),
+	}
+}
+
+// postFiles returns the attachments of a post (wr_file > 0). Errors are
+// logged and give an empty list so the post itself can still be shown.
+func postFiles(c *fiber.Ctx, bo board.Board, p models.Post) []models.BoardFile {
+	if p.File == 0 || files == nil {
+		return nil
+	}
+	list, err := files.ListFiles(c.UserContext(), bo.Table, p.ID)
+	if err != nil {
+		log.Printf("첨부파일 조회 실패: %v", err)
+		return nil
+	}
+	return list
+}
+
+func attachments(c *fiber.Ctx, bo board.Board, p models.Post) []Attachment {
+	result := []Attachment{}
+	for _, f := range postFiles(c, bo, p) {
+		result = append(result, toAttachment(f))
+	}
+	return result
+}
+
+// attachmentPath returns data/file/{bo_table}/{bf_file}, or false if bf_file
+// is not a plain file name
+func attachmentPath(f models.BoardFile) (string, bool) {
+	if f.File == "" || f.File == "." || f.File == ".." || f.File != filepath.Base(f.File) || strings.ContainsAny(f.File, `/\`) {
+		return "", false
+	}
+	return filepath.Join(dataDir, "file", f.Table, f.File), true
+}
+
+// contentDisposition sends name as an RFC 6266 attachment: an ASCII
+// fallback for old clients and the UTF-8 name in filename* (RFC 5987)
+func contentDisposition(name string) string {
+	var fallback, encoded strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20 || r == 0x7f || r == '"' || r == '\\' || r > 0x7e:
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+	for i := 0; i < len(name); i++ {
+		b := name[i]
+		if isAttrChar(b) {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return `attachment; filename="` + fallback.String() + `"; filename*=UTF-8''` + encoded.String()
+}
+
+// RFC 5987 attr-char
+func isAttrChar(b byte) bool {
+	switch {
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", b) >= 0
+}
+
+// errRangeNotSatisfiable is returned by parseRange for a range outside the file
+var errRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// parseRange reads a single "bytes=" range of a file of size bytes. ok is
+// false when the whole file should be sent (no header, another unit, several
+// ranges or a malformed value, which RFC 9110 allows us to ignore).
+func parseRange(header string, size int64) (start, end int64, ok bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false, nil
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false, nil
+	}
+	if first == "" {
+		// 마지막 n 바이트
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, errRangeNotSatisfiable
+		}
+		return max(size-n, 0), size - 1, true, nil
+	}
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false, nil
+	}
+	end = size - 1
+	if last != "" {
+		if end, err = strconv.ParseInt(last, 10, 64); err != nil || end < start {
+			return 0, 0, false, nil
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return 0, 0, false, errRangeNotSatisfiable
+	}
+	return start, end, true, nil
+}
+
+// fileSection is the requested part of an open file; the response closes it
+type fileSection struct {
+	*io.SectionReader
+	file *os.File
+}
+
+func (s fileSection) Close() error {
+	return s.file.Close()
+}
+
+func downloadSessionKey(f models.BoardFile) string {
+	return "ss_down_" + f.Table + "_" + strconv.Itoa(f.PostID)
+}
+
+// downloaded reports whether this session already downloaded from the post.
+// 세션을 못 읽으면 포인트와 다운로드 수를 건드리지 않도록 받은 것으로 봄
+func downloaded(c *fiber.Ctx, f models.BoardFile) bool {
+	sess, err := sessions.Get(c)
+	if err != nil {
+		log.Printf("세션 조회 실패: %v", err)
+		return true
+	}
+	return sess.Get(downloadSessionKey(f)) != nil
+}
+
+// countDownload raises bf_download and records download.php's
+// ss_down_{bo_table}_{wr_id}, so resumed downloads aren't counted or charged again
+func countDownload(c *fiber.Ctx, f models.BoardFile) {
+	sess, err := sessions.Get(c)
+	if err != nil {
+		log.Printf("세션 조회 실패: %v", err)
+		return
+	}
+	if err := files.IncrementDownload(c.UserContext(), f.Table, f.PostID, f.No); err != nil {
+		log.Printf("다운로드 수 증가 실패: %v", err)
+		return
+	}
+	sess.Set(downloadSessionKey(f), true)
+	if err := sess.Save(); err != nil {
+		log.Printf("세션 저장 실패: %v", err)
+	}
+}
+
+// HandleFileDownload streams an attachment from data/file/{bo_table}
+func HandleFileDownload(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	no, err := c.ParamsInt("no")
+	if err != nil || no < 0 {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidID, "잘못된 파일 번호입니다")
+	}
+	if denied := boardAccess(c, bo, board.ActionDownload); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	// 비밀글의 첨부파일은 글을 볼 수 있어야 받음
+	post, e := readablePost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	f, err := files.GetFile(c.UserContext(), bo.Table, id, no)
+	if errors.Is(err, repository.ErrNotFound) {
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "파일 정보가 존재하지 않습니다")
+	}
+	if err != nil {
+		log.Printf("첨부파일 조회 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "파일 조회 중 오류가 발생했습니다")
+	}
+
+	path, ok := attachmentPath(*f)
+	if !ok {
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "파일이 존재하지 않습니다")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("첨부파일 열기 실패: %v", err)
+		}
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "파일이 존재하지 않습니다")
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "파일이 존재하지 않습니다")
+	}
+	size := info.Size()
+
+	start, end, partial, err := parseRange(c.Get(fiber.HeaderRange), size)
+	// If-Range 가 현재 파일과 다르면 전체를 보냄
+	if ifRange := c.Get(fiber.HeaderIfRange); ifRange != "" && ifRange != info.ModTime().UTC().Format(httpTimeFormat) {
+		partial, err = false, nil
+	}
+	if err != nil {
+		file.Close()
+		c.Set(fiber.HeaderContentRange, "bytes */"+strconv.FormatInt(size, 10))
+		return v1Error(c, fiber.StatusRequestedRangeNotSatisfiable, ErrCodeInvalidRequest, "요청한 범위가 파일 크기를 벗어났습니다")
+	}
+
+	// 세션에서 처음 받을 때만 bo_download_point 를 적용하고 bf_download 를 올림
+	if !downloaded(c, *f) {
+		if e := chargeDownloadPoint(c, bo, *post); e != nil {
+			file.Close()
+			return v1PointError(c, e)
+		}
+		countDownload(c, *f)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	c.Set(fiber.HeaderContentDisposition, contentDisposition(f.Source))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+	c.Set(fiber.HeaderLastModified, info.ModTime().UTC().Format(httpTimeFormat))
+	if !partial {
+		start, end = 0, size-1
+	} else {
+		c.Status(fiber.StatusPartialContent)
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	}
+	length := end - start + 1
+	return c.SendStream(fileSection{io.NewSectionReader(file, start, length), file}, int(length))
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"log"
+
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/hits"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var hitCounter *hits.Counter
+
+// InitHits sets the counter of wr_hit
+func InitHits(c *hits.Counter) {
+	hitCounter = c
+}
+
+// viewer identifies who is reading: the member, or the IP for guests
+func viewer(c *fiber.Ctx) string {
+	if m := auth.CurrentMember(c); m != nil {
+		return "mb:" + m.ID
+	}
+	return "ip:" + c.IP()
+}
+
+// countHit counts a view of the post once per viewer within HIT_WINDOW,
+// logging failures
+func countHit(c *fiber.Ctx, bo board.Board, id int) {
+	if _, err := hitCounter.View(c.UserContext(), viewer(c), hits.Post{Table: bo.Table, ID: id}); err != nil {
+		log.Printf("조회수 증가 실패: %v", err)
+	}
+}
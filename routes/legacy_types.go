@@ -0,0 +1,56 @@
+package routes
+
+// 레거시 /api/:type 계열 응답. 키 이름과 형식은 변경하지 않음 (v1 사용)
+
+// LegacyError is the error body of the legacy routes
+type LegacyError struct {
+	Error string `json:"error"`
+}
+
+// LegacyPostList is the body of GET /api/:type
+type LegacyPostList struct {
+	Board string              `json:"게시판"`
+	Page  int                 `json:"현재페이지"`
+	Total int                 `json:"전체개수"`
+	Posts []LegacyPostSummary `json:"게시글" openapi:"nullable"`
+}
+
+// LegacyPostSummary is a post of LegacyPostList
+type LegacyPostSummary struct {
+	ID       int    `json:"id"`
+	Subject  string `json:"제목"`
+	Name     string `json:"작성자"`
+	Datetime string `json:"작성일"`
+	Hit      int    `json:"조회수"`
+	Good     int    `json:"추천수"`
+}
+
+// LegacyPost is the body of GET /api/:type/:id
+type LegacyPost struct {
+	ID       int    `json:"id"`
+	Good     int    `json:"추천"`
+	Subject  string `json:"제목"`
+	Name     string `json:"이름"`
+	Datetime string `json:"날짜"`
+	Hit      int    `json:"조회"`
+	Content  string `json:"내용" doc:"정리한 본문 HTML (content_html 과 같음)"`
+}
+
+// LegacyCommentList is the body of GET /api/:type/:id/comments
+type LegacyCommentList struct {
+	Count    int             `json:"count" doc:"전체 댓글 수"`
+	Page     int             `json:"page"`
+	Limit    int             `json:"limit"`
+	Comments []LegacyComment `json:"comments"`
+}
+
+// LegacyComment is a comment of LegacyCommentList
+type LegacyComment struct {
+	ID       int    `json:"id"`
+	Content  string `json:"내용" doc:"이스케이프한 댓글 HTML (content_html 과 같음)"`
+	Name     string `json:"작성자"`
+	Datetime string `json:"날짜"`
+	Parent   int    `json:"부모글ID"`
+	Depth    int    `json:"depth" doc:"대댓글 단계 (원댓글 0)"`
+	ReplyTo  int    `json:"parent_comment_id" doc:"부모 댓글 ID (원댓글이면 0)"`
+}
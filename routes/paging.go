@@ -0,0 +1,34 @@
+package routes
+
+import "github.com/gofiber/fiber/v2"
+
+// 목록 한 페이지 최대 게시글 수 (limit, per_page 상한)
+var maxPageSize = 100
+
+// SetMaxPageSize sets the upper bound of the limit/per_page query parameters
+func SetMaxPageSize(n int) {
+	if n > 0 {
+		maxPageSize = n
+	}
+}
+
+// pageNumber returns the 1-based page query parameter
+func pageNumber(c *fiber.Ctx) int {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		return 1
+	}
+	return page
+}
+
+// pageSize returns the named query parameter clamped to 1..maxPageSize
+func pageSize(c *fiber.Ctx, name string, def int) int {
+	n := c.QueryInt(name, def)
+	if n < 1 {
+		n = def
+	}
+	if n > maxPageSize {
+		n = maxPageSize
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package routes
+
+import (
+	"fibergo/auth"
+	"fibergo/board"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrCodeForbidden is returned when a member's mb_level is below the board's
+const ErrCodeForbidden = "forbidden"
+
+// 권한이 없을 때 메시지 (그누보드 문구)
+var deniedMessages = map[board.Action]string{
+	board.ActionList:     "목록을 볼 권한이 없습니다",
+	board.ActionRead:     "글을 읽을 권한이 없습니다",
+	board.ActionComment:  "댓글을 쓸 권한이 없습니다",
+	board.ActionWrite:    "글을 쓸 권한이 없습니다",
+	board.ActionReply:    "글을 답변할 권한이 없습니다",
+	board.ActionDownload: "다운로드 권한이 없습니다",
+	board.ActionUpload:   "파일을 올릴 권한이 없습니다",
+}
+
+// boardAccess compares the caller's mb_level with the level action needs on
+// bo. Guests are told to log in (401), members are refused (403).
+func boardAccess(c *fiber.Ctx, bo board.Board, action board.Action) *fiber.Error {
+	if bo.Allows(auth.Level(c), action) {
+		return nil
+	}
+	if auth.CurrentMember(c) == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, deniedMessages[action]+". 회원이시라면 로그인 후 이용해 주세요")
+	}
+	return fiber.NewError(fiber.StatusForbidden, deniedMessages[action])
+}
+
+// v1Denied writes a boardAccess error in the v1 envelope
+func v1Denied(c *fiber.Ctx, denied *fiber.Error) error {
+	code := ErrCodeForbidden
+	if denied.Code == fiber.StatusUnauthorized {
+		code = ErrCodeUnauthorized
+	}
+	return v1Error(c, denied.Code, code, denied.Message)
+}
+
+// legacyDenied writes a boardAccess error in the legacy {"error"} form
+func legacyDenied(c *fiber.Ctx, denied *fiber.Error) error {
+	return c.Status(denied.Code).JSON(fiber.Map{
+		"error": denied.Message,
+	})
+}
+
+// permittedBoards keeps the boards whose action the caller may perform
+func permittedBoards(c *fiber.Ctx, list []board.Board, actions ...board.Action) []board.Board {
+	level := auth.Level(c)
+	var allowed []board.Board
+	for _, b := range list {
+		ok := true
+		for _, a := range actions {
+			ok = ok && b.Allows(level, a)
+		}
+		if ok {
+			allowed = append(allowed, b)
+		}
+	}
+	return allowed
+}
+
+// boardPermissions tells the caller what they may do on bo
+func boardPermissions(c *fiber.Ctx, bo board.Board) BoardPermissions {
+	level := auth.Level(c)
+	return BoardPermissions{
+		List:    bo.Allows(level, board.ActionList),
+		Read:    bo.Allows(level, board.ActionRead),
+		Comment: bo.Allows(level, board.ActionComment),
+		Write:   bo.Allows(level, board.ActionWrite),
+		Reply:   bo.Allows(level, board.ActionReply),
+	}
+}
@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"fibergo/auth"
+	"fibergo/models"
+	"fibergo/phpsession"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PHPSession configures reading the login state of the PHP Gnuboard
+// pages served on the same domain
+type PHPSession struct {
+	Reader phpsession.Reader
+	// session.name (기본 PHPSESSID)
+	Cookie string
+	// 그누보드 common.php 와 같이 ss_mb_key 로 IP, User-Agent 확인
+	CheckKey bool
+}
+
+var phpSession *PHPSession
+
+// InitPHPSession enables PHP sessions in LoadMember. nil disables them.
+func InitPHPSession(cfg *PHPSession) {
+	if cfg != nil && cfg.Cookie == "" {
+		cfg.Cookie = "PHPSESSID"
+	}
+	phpSession = cfg
+}
+
+// phpSessionMember returns the member logged in on the PHP side, or nil
+func phpSessionMember(c *fiber.Ctx) *models.Member {
+	if phpSession == nil || members == nil {
+		return nil
+	}
+	id := c.Cookies(phpSession.Cookie)
+	if id == "" {
+		return nil
+	}
+
+	sess, err := phpSession.Reader.Load(c.UserContext(), id)
+	if err != nil {
+		if !errors.Is(err, phpsession.ErrNotFound) {
+			log.Printf("PHP 세션 읽기 실패: %v", err)
+		}
+		return nil
+	}
+	mbID := phpsession.String(sess, "ss_mb_id")
+	if mbID == "" {
+		return nil
+	}
+
+	m, err := members.GetMember(c.UserContext(), mbID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Printf("회원 조회 실패: %v", err)
+		}
+		return nil
+	}
+	if auth.CheckMember(m, time.Now()) != nil {
+		return nil
+	}
+	if phpSession.CheckKey {
+		want := phpsession.MemberKey(m.Datetime.Format(legacyTimeFormat), c.IP(), c.Get(fiber.HeaderUserAgent))
+		if phpsession.String(sess, "ss_mb_key") != want {
+			return nil
+		}
+	}
+	return m
+}
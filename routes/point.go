@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 포인트가 모자라 글읽기, 다운로드를 할 수 없음
+const ErrCodeInsufficientPoints = "insufficient_points"
+
+// 포인트 내역 한 페이지 기본 개수 (그누보드 point.php 의 cf_page_rows)
+const defaultPointPageSize = 15
+
+var points repository.PointRepository
+
+// InitPoints sets the repository of bo_read_point, bo_download_point and /api/me/points
+func InitPoints(repo repository.PointRepository) {
+	points = repo
+}
+
+// chargePoint applies a board point like view.php and download.php. The
+// post's author, board admins and guests are not charged, and a member is
+// charged once per post (rel_table/rel_id/rel_action). Nothing happens when
+// g5_config.cf_use_point is off. It returns 403 when a negative point is
+// more than the member has.
+func chargePoint(c *fiber.Ctx, bo board.Board, p models.Post, point int, label, action string) *fiber.Error {
+	m := auth.CurrentMember(c)
+	if point == 0 || points == nil || m == nil || p.MemberID == m.ID || isBoardAdmin(c, bo) {
+		return nil
+	}
+	pt, ok := repository.BoardPoint(bo, models.Point{
+		MemberID:  m.ID,
+		Content:   fmt.Sprintf("%s %d %s", bo.Subject, p.ID, label),
+		Point:     point,
+		RelTable:  bo.Table,
+		RelID:     strconv.Itoa(p.ID),
+		RelAction: action,
+	})
+	if !ok {
+		// cf_use_point 가 꺼져 있으면 포인트가 모자라도 읽기, 다운로드를 막지 않음
+		return nil
+	}
+	err := points.ChargePoint(c.UserContext(), pt)
+	if errors.Is(err, repository.ErrInsufficientPoints) {
+		return fiber.NewError(fiber.StatusForbidden,
+			fmt.Sprintf("보유하신 포인트(%d)가 없거나 모자라서 %s(%d)가 불가합니다", m.Point, label, point))
+	}
+	if err != nil {
+		log.Printf("포인트 처리 실패: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "포인트 처리 중 오류가 발생했습니다")
+	}
+	return nil
+}
+
+// chargeReadPoint applies bo_read_point for reading p
+func chargeReadPoint(c *fiber.Ctx, bo board.Board, p models.Post) *fiber.Error {
+	return chargePoint(c, bo, p, bo.ReadPoint, "글읽기", "읽기")
+}
+
+// chargeDownloadPoint applies bo_download_point for the attachments of p
+func chargeDownloadPoint(c *fiber.Ctx, bo board.Board, p models.Post) *fiber.Error {
+	return chargePoint(c, bo, p, bo.DownloadPoint, "파일 다운로드", "다운로드")
+}
+
+// v1PointError writes a chargePoint error in the v1 format
+func v1PointError(c *fiber.Ctx, e *fiber.Error) error {
+	if e.Code == fiber.StatusForbidden {
+		return v1Error(c, e.Code, ErrCodeInsufficientPoints, e.Message)
+	}
+	return v1Error(c, e.Code, ErrCodeInternal, e.Message)
+}
+
+func toPointItem(pt models.Point) PointItem {
+	return PointItem{
+		ID:        pt.ID,
+		Content:   pt.Content,
+		Point:     pt.Point,
+		Balance:   pt.MemberPoint,
+		CreatedAt: pt.Datetime,
+		ExpiresOn: pt.ExpireDate,
+		RelTable:  pt.RelTable,
+		RelID:     pt.RelID,
+		RelAction: pt.RelAction,
+	}
+}
+
+// HandleMyPoints lists the g5_point history of the logged-in member, newest first
+func HandleMyPoints(c *fiber.Ctx) error {
+	m := auth.CurrentMember(c)
+	if m == nil {
+		return v1Unauthorized(c)
+	}
+	page := pageNumber(c)
+	perPage := pageSize(c, "per_page", defaultPointPageSize)
+
+	total, err := points.CountPoints(c.UserContext(), m.ID)
+	if err != nil {
+		log.Printf("포인트 내역 수 조회 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "포인트 내역 조회 중 오류가 발생했습니다")
+	}
+	list, err := points.ListPoints(c.UserContext(), m.ID, (page-1)*perPage, perPage)
+	if err != nil {
+		log.Printf("포인트 내역 조회 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "포인트 내역 조회 중 오류가 발생했습니다")
+	}
+
+	data := make([]PointItem, 0, len(list))
+	for _, pt := range list {
+		data = append(data, toPointItem(pt))
+	}
+	return c.JSON(Envelope[[]PointItem]{Data: data, Meta: &Meta{Page: page, PerPage: perPage, Total: &total}})
+}
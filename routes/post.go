@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"fibergo/board"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandlePostAPI handles API requests for a single post
+func HandlePostAPI(c *fiber.Ctx) error {
+	boardType := c.Params("type")
+
+	// 입력값 검증 추가
+	wrID, err := c.ParamsInt("id")
+	if err != nil || wrID <= 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "잘못된 게시글 ID입니다",
+		})
+	}
+
+	// 게시판 타입 검증
+	bo, ok := LookupBoard(boardType)
+	if !ok {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "유효하지 않은 게시판입니다",
+		})
+	}
+
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return legacyDenied(c, denied)
+	}
+
+	// 비밀글은 403
+	post, e := readablePost(c, bo, wrID)
+	if e != nil {
+		return c.Status(e.Code).JSON(fiber.Map{
+			"error": e.Message,
+		})
+	}
+
+	if e := chargeReadPoint(c, bo, *post); e != nil {
+		return c.Status(e.Code).JSON(fiber.Map{
+			"error": e.Message,
+		})
+	}
+
+	// 조회수 증가
+	countHit(c, bo, wrID)
+
+	// 내용은 v1 의 content_html 과 같이 정리한 HTML (innerHTML 로 넣는 클라이언트가 있음)
+	return c.JSON(LegacyPost{
+		ID:       post.ID,
+		Good:     post.Good,
+		Subject:  post.Subject,
+		Name:     post.Name,
+		Datetime: post.Datetime.Format(legacyTimeFormat),
+		Hit:      post.Hit,
+		Content:  postHTML(*post),
+	})
+}
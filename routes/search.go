@@ -0,0 +1,99 @@
+package routes
+
+import (
+	"html/template"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+	"fibergo/search"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 검색 구간 크기 (그누보드 cf_search_part)
+var searchPart = 10000
+
+// 검색 결과 본문 미리보기 길이
+const snippetWidth = 120
+
+// SetSearchPart sets the wr_num segment size of one search (spt paging)
+func SetSearchPart(n int) {
+	if n > 0 {
+		searchPart = n
+	}
+}
+
+// searchPage is one page of search results within a wr_num segment
+type searchPage struct {
+	Query   search.Query
+	Posts   []models.Post
+	Total   int
+	Page    int
+	PerPage int
+	Spt     int
+	PrevSpt *int
+	NextSpt *int
+}
+
+// runSearch reads sfl, stx, sop, spt and page parameters and searches the board
+func runSearch(c *fiber.Ctx, bo board.Board, perPage int) (*searchPage, error) {
+	q, err := search.Parse(c.Query("sfl"), c.Query("stx"), c.Query("sop"))
+	if err != nil {
+		return nil, err
+	}
+
+	// 가장 최신 글부터 searchPart 단위로 나눠 검색
+	minSpt, err := posts.MinNum(c.UserContext(), bo.Table)
+	if err != nil {
+		return nil, err
+	}
+	spt := c.QueryInt("spt", minSpt)
+	if spt < minSpt {
+		spt = minSpt
+	}
+
+	result := &searchPage{Query: q, Page: pageNumber(c), PerPage: perPage, Spt: spt}
+	if prev := spt - searchPart; prev >= minSpt {
+		result.PrevSpt = &prev
+	}
+	if next := spt + searchPart; next < 0 {
+		result.NextSpt = &next
+	}
+
+	opts := repository.SearchOptions{
+		Query:        q,
+		SegmentStart: spt,
+		SegmentSize:  searchPart,
+		Offset:       (result.Page - 1) * perPage,
+		Limit:        perPage,
+	}
+	if result.Total, err = posts.CountSearch(c.UserContext(), bo.Table, opts); err != nil {
+		return nil, err
+	}
+	if result.Posts, err = posts.SearchPosts(c.UserContext(), bo.Table, opts); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// listRow is a post of the SSR list with its highlighted subject and, on
+// gallery boards, its thumbnail
+type listRow struct {
+	models.Post
+	SubjectHTML template.HTML
+	Thumbnail   string
+}
+
+func listRows(c *fiber.Ctx, bo board.Board, list []models.Post, words []string) []listRow {
+	rows := make([]listRow, 0, len(list))
+	thumbs := galleryThumbnails(c, bo, list)
+	for _, p := range list {
+		rows = append(rows, listRow{
+			Post:        p,
+			SubjectHTML: template.HTML(search.Highlight(p.Subject, words)),
+			Thumbnail:   thumbs[p.ID],
+		})
+	}
+	return rows
+}
@@ -0,0 +1,269 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 비밀글, 비밀 댓글 내용 대신 보여주는 문구 (그누보드와 같음)
+const secretPlaceholder = "비밀글 입니다."
+
+// 비밀글 관련 v1 에러 코드
+const (
+	ErrCodeSecretPost      = "secret_post"
+	ErrCodeInvalidPassword = "invalid_password"
+)
+
+var superAdmin string
+
+// SetSuperAdmin sets cf_admin, the member who administers every board
+func SetSuperAdmin(id string) {
+	superAdmin = id
+}
+
+// isBoardAdmin reports whether the caller is the super, group or board
+// admin of bo, like Gnuboard's $is_admin
+func isBoardAdmin(c *fiber.Ctx, bo board.Board) bool {
+	m := auth.CurrentMember(c)
+	if m == nil {
+		return false
+	}
+	for _, id := range []string{superAdmin, bo.GroupAdmin, bo.Admin} {
+		if id != "" && id == m.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// canReadPost reports whether the caller may see the content of p. Secret
+// posts are open to admins, the author, the author of the original post
+// of a reply thread and sessions that entered the password.
+func canReadPost(c *fiber.Ctx, bo board.Board, p models.Post) (bool, error) {
+	if !p.HasOption("secret") || isBoardAdmin(c, bo) {
+		return true, nil
+	}
+	if m := auth.CurrentMember(c); m != nil {
+		if p.MemberID == m.ID {
+			return true, nil
+		}
+		// 회원의 비밀글에 관리자가 답변하면 원글 작성자도 답변을 볼 수 있음
+		if p.Reply != "" {
+			origin, err := posts.ThreadOrigin(c.UserContext(), bo.Table, p.Num)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				return false, err
+			}
+			if origin != nil && origin.MemberID == m.ID {
+				return true, nil
+			}
+		}
+	}
+	return secretUnlocked(c, bo, p), nil
+}
+
+// findPost loads a post. The error is 404 or 500.
+func findPost(c *fiber.Ctx, bo board.Board, id int) (*models.Post, *fiber.Error) {
+	post, err := posts.GetPost(c.UserContext(), bo.Table, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "게시글을 찾을 수 없습니다")
+		}
+		log.Printf("게시글 조회 실패: %v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "서버 오류가 발생했습니다")
+	}
+	return post, nil
+}
+
+// readablePost loads a post and checks its secret flag. The error is 404,
+// 403 (secret) or 500.
+func readablePost(c *fiber.Ctx, bo board.Board, id int) (*models.Post, *fiber.Error) {
+	post, e := findPost(c, bo, id)
+	if e != nil {
+		return nil, e
+	}
+	ok, err := canReadPost(c, bo, *post)
+	if err != nil {
+		log.Printf("비밀글 권한 확인 실패: %v", err)
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "서버 오류가 발생했습니다")
+	}
+	if !ok {
+		return post, fiber.NewError(fiber.StatusForbidden, secretDeniedMessage(*post))
+	}
+	return post, nil
+}
+
+func secretDeniedMessage(p models.Post) string {
+	if p.MemberID == "" && p.Password != "" {
+		return "비밀글입니다. 비밀번호를 입력하세요"
+	}
+	return "비밀글은 작성자와 관리자만 볼 수 있습니다"
+}
+
+// v1PostError writes a readablePost error in the v1 envelope
+func v1PostError(c *fiber.Ctx, e *fiber.Error) error {
+	code := ErrCodeInternal
+	switch e.Code {
+	case fiber.StatusNotFound:
+		code = ErrCodeNotFound
+	case fiber.StatusForbidden:
+		code = ErrCodeSecretPost
+	}
+	return v1Error(c, e.Code, code, e.Message)
+}
+
+// 그누보드와 같이 비밀번호를 확인한 글타래(wr_num)를 세션에 기록
+func secretSessionKey(bo board.Board, p models.Post) string {
+	return "ss_secret_" + bo.Table + "_" + strconv.Itoa(p.Num)
+}
+
+func secretUnlocked(c *fiber.Ctx, bo board.Board, p models.Post) bool {
+	if sessions == nil || c.Cookies(SessionCookie) == "" {
+		return false
+	}
+	sess, err := sessions.Get(c)
+	if err != nil {
+		return false
+	}
+	ok, _ := sess.Get(secretSessionKey(bo, p)).(bool)
+	return ok
+}
+
+// unlockSecret checks password against wr_password of a guest's post and
+// remembers the thread in the session
+func unlockSecret(c *fiber.Ctx, bo board.Board, p models.Post, password string) bool {
+	if p.MemberID != "" || password == "" || !auth.CheckPassword(password, p.Password) {
+		return false
+	}
+	if sessions == nil {
+		return true
+	}
+	sess, err := sessions.Get(c)
+	if err != nil {
+		log.Printf("세션 조회 실패: %v", err)
+		return true
+	}
+	sess.Set(secretSessionKey(bo, p), true)
+	if err := sess.Save(); err != nil {
+		log.Printf("세션 저장 실패: %v", err)
+	}
+	return true
+}
+
+// redactComments hides secret comments from everyone except admins, the
+// comment's author and the post's author
+func redactComments(c *fiber.Ctx, bo board.Board, post models.Post, list []models.Comment) {
+	if isBoardAdmin(c, bo) {
+		return
+	}
+	var mbID string
+	if m := auth.CurrentMember(c); m != nil {
+		mbID = m.ID
+	}
+	for i := range list {
+		if !list[i].HasOption("secret") {
+			continue
+		}
+		if mbID != "" && (list[i].MemberID == mbID || post.MemberID == mbID) {
+			continue
+		}
+		list[i].Content = secretPlaceholder
+	}
+}
+
+// secretSnippet returns the placeholder instead of a search snippet of a
+// secret post the caller may not read
+func secretSnippet(c *fiber.Ctx, bo board.Board, p models.Post, snippet string) string {
+	ok, err := canReadPost(c, bo, p)
+	if err != nil {
+		log.Printf("비밀글 권한 확인 실패: %v", err)
+	}
+	if ok && err == nil {
+		return snippet
+	}
+	return secretPlaceholder
+}
+
+// UnlockRequest is the body of POST /api/v1/boards/{board}/posts/{id}/password
+type UnlockRequest struct {
+	Password string `json:"password" form:"wr_password"`
+}
+
+// HandleUnlockV1 opens a guest's secret post with its password
+func HandleUnlockV1(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	var req UnlockRequest
+	if err := c.BodyParser(&req); err != nil || req.Password == "" {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "비밀번호를 입력하세요")
+	}
+
+	// 비밀번호로 열 수 있는 것은 손님이 쓴 비밀글뿐
+	post, e := readablePost(c, bo, id)
+	if e != nil && (e.Code != fiber.StatusForbidden || post.MemberID != "") {
+		return v1PostError(c, e)
+	}
+	if e != nil && !unlockSecret(c, bo, *post, req.Password) {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeInvalidPassword, "비밀번호가 틀립니다")
+	}
+	if e := chargeReadPoint(c, bo, *post); e != nil {
+		return v1PointError(c, e)
+	}
+	countHit(c, bo, id)
+	return c.JSON(Envelope[PostDetail]{Data: toPostDetail(c, bo, *post)})
+}
+
+// HandleSecretPasswordSSR handles the password form of a guest's secret post
+func HandleSecretPasswordSSR(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return fiber.ErrNotFound
+	}
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return denied
+	}
+
+	post, e := readablePost(c, bo, id)
+	postURL := "/" + bo.Table + "/" + strconv.Itoa(id)
+	if e == nil {
+		return c.Redirect(postURL, fiber.StatusSeeOther)
+	}
+	if e.Code != fiber.StatusForbidden || post.MemberID != "" {
+		return e
+	}
+	if unlockSecret(c, bo, *post, c.FormValue("wr_password")) {
+		return c.Redirect(postURL, fiber.StatusSeeOther)
+	}
+	return renderPasswordForm(c, bo, *post, "비밀번호가 틀립니다")
+}
+
+// renderPasswordForm asks for the password of a guest's secret post
+func renderPasswordForm(c *fiber.Ctx, bo board.Board, p models.Post, message string) error {
+	return c.Status(fiber.StatusForbidden).Render("board_password", fiber.Map{
+		"Title":     bo.Subject,
+		"BoardType": bo.Table,
+		"Post":      p,
+		"Error":     message,
+	})
+}
@@ -0,0 +1,236 @@
+package routes
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"html"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/thumbnail"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 그누보드 갤러리 스킨의 bo_gallery_width, bo_gallery_height 기본값
+var defaultGallerySize = thumbnail.Size{Width: 202, Height: 150}
+
+// 썸네일 주소에 원본 이름이 들어가므로 오래 캐시해도 됨
+const thumbnailCacheControl = "public, max-age=31536000, immutable"
+
+// 본문의 이미지 (<img src="...">)
+var contentImagePattern = regexp.MustCompile(`(?i)<img\s[^>]*?src\s*=\s*["']?([^"'\s>]+)`)
+
+// 게시판 크기 외에 ?size= 로 요청할 수 있는 썸네일 크기
+var thumbnailSizes []thumbnail.Size
+
+// InitThumbnails sets the thumbnail sizes allowed besides each gallery
+// board's bo_gallery_width x bo_gallery_height
+func InitThumbnails(sizes []thumbnail.Size) {
+	thumbnailSizes = sizes
+}
+
+func gallerySize(bo board.Board) thumbnail.Size {
+	if bo.GalleryWidth <= 0 || bo.GalleryHeight <= 0 {
+		return defaultGallerySize
+	}
+	return thumbnail.Size{Width: bo.GalleryWidth, Height: bo.GalleryHeight}
+}
+
+func thumbnailSizeAllowed(bo board.Board, size thumbnail.Size) bool {
+	if size == gallerySize(bo) {
+		return true
+	}
+	for _, s := range thumbnailSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// contentImages returns the files of the <img> tags in wr_content that the
+// editor saved under data/editor/. Images on other hosts or elsewhere in
+// the data directory (data/file, data/session ...) are skipped.
+func contentImages(c *fiber.Ctx, content string) []string {
+	var list []string
+	for _, m := range contentImagePattern.FindAllStringSubmatch(content, -1) {
+		u, err := url.Parse(html.UnescapeString(m[1]))
+		if err != nil || (u.Host != "" && u.Host != c.Hostname()) {
+			continue
+		}
+		rel, ok := strings.CutPrefix(u.Path, "/data/editor/")
+		if !ok {
+			continue
+		}
+		// /data/editor/../ 같은 경로로 editor 디렉터리를 벗어나지 않도록
+		rel = filepath.FromSlash(rel)
+		if !filepath.IsLocal(rel) {
+			continue
+		}
+		list = append(list, filepath.Join(dataDir, "editor", rel))
+	}
+	return list
+}
+
+// thumbnailSource finds the image a post's thumbnail is made from like
+// get_list_thumbnail: the first image attachment, else the first editor
+// image of wr_content. ok is false if the post has neither. 파일이 있는지는
+// 썸네일을 만들 때 확인하므로 목록에서는 디스크를 보지 않음
+func thumbnailSource(c *fiber.Ctx, p models.Post, attached []models.BoardFile) (string, bool) {
+	for _, f := range attached {
+		if f.Type < 1 || f.Type > 3 {
+			continue
+		}
+		if path, ok := attachmentPath(f); ok {
+			return path, true
+		}
+	}
+	if images := contentImages(c, p.Content); len(images) > 0 {
+		return images[0], true
+	}
+	return "", false
+}
+
+// galleryThumbnails returns the list thumbnail URL of each post by wr_id on
+// a gallery board, leaving out secret posts and posts without an image.
+// 목록에는 wr_content 가 없으므로 첨부 이미지가 없는 글만 본문을 따로 읽음.
+// The attachments and contents of the whole page are read in one query each.
+func galleryThumbnails(c *fiber.Ctx, bo board.Board, list []models.Post) map[int]string {
+	if !bo.IsGallery() {
+		return nil
+	}
+	var ids []int
+	for _, p := range list {
+		if p.File > 0 && !p.HasOption("secret") {
+			ids = append(ids, p.ID)
+		}
+	}
+	var attached map[int][]models.BoardFile
+	if len(ids) > 0 && files != nil {
+		var err error
+		if attached, err = files.ListPostFiles(c.UserContext(), bo.Table, ids); err != nil {
+			log.Printf("첨부파일 조회 실패: %v", err)
+		}
+	}
+
+	var needContent []int
+	for _, p := range list {
+		if !p.HasOption("secret") && p.Content == "" && !hasImageAttachment(attached[p.ID]) {
+			needContent = append(needContent, p.ID)
+		}
+	}
+	var contents map[int]string
+	if len(needContent) > 0 {
+		var err error
+		if contents, err = posts.PostContents(c.UserContext(), bo.Table, needContent); err != nil {
+			log.Printf("본문 조회 실패: %v", err)
+		}
+	}
+
+	thumbs := make(map[int]string, len(list))
+	for _, p := range list {
+		if p.HasOption("secret") {
+			continue
+		}
+		if content, ok := contents[p.ID]; ok {
+			p.Content = content
+		}
+		src, ok := thumbnailSource(c, p, attached[p.ID])
+		if !ok {
+			continue
+		}
+		// 원본이 바뀌면 주소도 바뀌도록 원본 파일명을 v 에 넣음
+		sum := sha1.Sum([]byte(filepath.Base(src)))
+		thumbs[p.ID] = "/api/" + bo.Table + "/" + strconv.Itoa(p.ID) + "/thumbnail?size=" + gallerySize(bo).String() +
+			"&v=" + hex.EncodeToString(sum[:4])
+	}
+	return thumbs
+}
+
+// hasImageAttachment reports whether thumbnailSource would use one of the attachments
+func hasImageAttachment(attached []models.BoardFile) bool {
+	for _, f := range attached {
+		if f.Type < 1 || f.Type > 3 {
+			continue
+		}
+		if _, ok := attachmentPath(f); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// removeThumbnails deletes the thumbnails made from the attachments and
+// content images of a deleted post
+func removeThumbnails(c *fiber.Ctx, list []models.BoardFile, content string) {
+	var sources []string
+	for _, f := range list {
+		if path, ok := attachmentPath(f); ok {
+			sources = append(sources, path)
+		}
+	}
+	sources = append(sources, contentImages(c, content)...)
+	for _, src := range sources {
+		if err := thumbnail.Remove(src); err != nil {
+			log.Printf("썸네일 삭제 실패: %v", err)
+		}
+	}
+}
+
+// HandleThumbnail serves the thumbnail of a gallery post, made on first
+// request and cached next to the original
+func HandleThumbnail(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	if !bo.IsGallery() {
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "갤러리 게시판이 아닙니다")
+	}
+	// 목록에 보이는 그림이므로 목록 권한으로 충분
+	if denied := boardAccess(c, bo, board.ActionList); denied != nil {
+		return v1Denied(c, denied)
+	}
+	size := gallerySize(bo)
+	if raw := c.Query("size"); raw != "" {
+		s, err := thumbnail.ParseSize(raw)
+		if err != nil || !thumbnailSizeAllowed(bo, s) {
+			return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "지원하지 않는 썸네일 크기입니다")
+		}
+		size = s
+	}
+
+	post, e := readablePost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	src, ok := thumbnailSource(c, *post, postFiles(c, bo, *post))
+	if !ok {
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "썸네일을 만들 이미지가 없습니다")
+	}
+	path, err := thumbnail.Make(src, size)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("썸네일 생성 실패 (%s): %v", src, err)
+		}
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "썸네일을 만들 수 없습니다")
+	}
+	if err := c.SendFile(path); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, thumbnailCacheControl)
+	return nil
+}
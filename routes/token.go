@@ -0,0 +1,220 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fibergo/auth"
+	"fibergo/jwt"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var tokenKeys *jwt.KeySet
+var denylist repository.TokenDenylist
+var accessTTL = 15 * time.Minute
+var refreshTTL = 14 * 24 * time.Hour
+
+// InitTokens enables /api/auth/token. keys nil disables JWT issuance and
+// rejects every bearer token.
+func InitTokens(keys *jwt.KeySet, revoked repository.TokenDenylist, access, refresh time.Duration) {
+	tokenKeys = keys
+	denylist = revoked
+	if access > 0 {
+		accessTTL = access
+	}
+	if refresh > 0 {
+		refreshTTL = refresh
+	}
+}
+
+// TokenPair is issued by /api/auth/token and /api/auth/refresh
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type" doc:"Bearer"`
+	ExpiresIn    int    `json:"expires_in" doc:"access_token 유효 시간 (초)"`
+}
+
+// RefreshRequest is the body of POST /api/auth/refresh
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token"`
+}
+
+const tokenClaimsKey = "token_claims"
+
+func tokensDisabled(c *fiber.Ctx) error {
+	return v1Error(c, fiber.StatusServiceUnavailable, ErrCodeTokensDisabled, "토큰 발급이 설정되지 않았습니다")
+}
+
+func invalidToken(c *fiber.Ctx, err error) error {
+	if errors.Is(err, jwt.ErrExpired) {
+		return v1Error(c, fiber.StatusUnauthorized, ErrCodeTokenExpired, "만료된 토큰입니다")
+	}
+	return v1Error(c, fiber.StatusUnauthorized, ErrCodeInvalidToken, "유효하지 않은 토큰입니다")
+}
+
+// issueTokens signs a new access/refresh pair for m
+func issueTokens(m *models.Member) (TokenPair, error) {
+	access, err := jwt.NewClaims(m.ID, m.Level, jwt.TypeAccess, accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := jwt.NewClaims(m.ID, m.Level, jwt.TypeRefresh, refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	pair := TokenPair{TokenType: "Bearer", ExpiresIn: int(accessTTL.Seconds())}
+	if pair.AccessToken, err = tokenKeys.Sign(access); err != nil {
+		return TokenPair{}, err
+	}
+	if pair.RefreshToken, err = tokenKeys.Sign(refresh); err != nil {
+		return TokenPair{}, err
+	}
+	return pair, nil
+}
+
+// verifyToken parses token, checks its type and the denylist and loads
+// the member it was issued to
+func verifyToken(c *fiber.Ctx, token, typ string) (jwt.Claims, *models.Member, error) {
+	claims, err := tokenKeys.Parse(token, time.Now())
+	if err != nil {
+		return claims, nil, err
+	}
+	if claims.Type != typ {
+		return claims, nil, jwt.ErrInvalid
+	}
+	if denylist != nil {
+		revoked, err := denylist.IsTokenRevoked(c.UserContext(), claims.ID)
+		if err != nil {
+			return claims, nil, err
+		}
+		if revoked {
+			return claims, nil, jwt.ErrInvalid
+		}
+	}
+
+	m, err := members.GetMember(c.UserContext(), claims.Subject)
+	if errors.Is(err, repository.ErrNotFound) {
+		return claims, nil, jwt.ErrInvalid
+	}
+	if err != nil {
+		return claims, nil, err
+	}
+	if auth.CheckMember(m, time.Now()) != nil {
+		return claims, nil, jwt.ErrInvalid
+	}
+	return claims, m, nil
+}
+
+// tokenFailure answers a verifyToken error
+func tokenFailure(c *fiber.Ctx, err error) error {
+	if errors.Is(err, jwt.ErrInvalid) || errors.Is(err, jwt.ErrExpired) || errors.Is(err, jwt.ErrUnknownKey) {
+		return invalidToken(c, err)
+	}
+	log.Printf("토큰 확인 실패: %v", err)
+	return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "토큰 확인 중 오류가 발생했습니다")
+}
+
+// LoadToken authenticates /api requests carrying "Authorization: Bearer".
+// A bad token is rejected rather than treated as a guest. The access token
+// is bound to mb_level: if the level changed the client has to refresh.
+func LoadToken(c *fiber.Ctx) error {
+	header := c.Get(fiber.HeaderAuthorization)
+	if header == "" {
+		return c.Next()
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || tokenKeys == nil {
+		return invalidToken(c, jwt.ErrInvalid)
+	}
+
+	claims, m, err := verifyToken(c, strings.TrimSpace(token), jwt.TypeAccess)
+	if err != nil {
+		return tokenFailure(c, err)
+	}
+	if m.Level != claims.Level {
+		return invalidToken(c, jwt.ErrExpired)
+	}
+	auth.SetMember(c, m)
+	c.Locals(tokenClaimsKey, claims)
+	return c.Next()
+}
+
+// HandleToken issues tokens for mb_id / mb_password
+func HandleToken(c *fiber.Ctx) error {
+	if tokenKeys == nil {
+		return tokensDisabled(c)
+	}
+	m, err := authenticate(c)
+	if err != nil {
+		return loginError(c, err)
+	}
+	return respondTokens(c, m)
+}
+
+// HandleRefresh exchanges a refresh token for a new pair. The used refresh
+// token is revoked, so each one works only once.
+func HandleRefresh(c *fiber.Ctx) error {
+	if tokenKeys == nil {
+		return tokensDisabled(c)
+	}
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "refresh_token 을 입력하세요")
+	}
+
+	claims, m, err := verifyToken(c, req.RefreshToken, jwt.TypeRefresh)
+	if err != nil {
+		return tokenFailure(c, err)
+	}
+	if denylist != nil {
+		// 확인 후 동시에 들어온 같은 토큰은 여기서 한 쪽만 통과
+		err := denylist.RevokeToken(c.UserContext(), claims.ID, claims.Subject, claims.Expires())
+		if errors.Is(err, repository.ErrTokenRevoked) {
+			return invalidToken(c, jwt.ErrInvalid)
+		}
+		if err != nil {
+			log.Printf("토큰 폐기 실패: %v", err)
+			return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "토큰 발급 중 오류가 발생했습니다")
+		}
+	}
+	return respondTokens(c, m)
+}
+
+func respondTokens(c *fiber.Ctx, m *models.Member) error {
+	pair, err := issueTokens(m)
+	if err != nil {
+		log.Printf("토큰 발급 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "토큰 발급 중 오류가 발생했습니다")
+	}
+	return c.JSON(Envelope[TokenPair]{Data: pair})
+}
+
+// revokeTokens adds the request's access token and the refresh_token in
+// the body to the denylist
+func revokeTokens(c *fiber.Ctx) {
+	if tokenKeys == nil || denylist == nil {
+		return
+	}
+	var list []jwt.Claims
+	if claims, ok := c.Locals(tokenClaimsKey).(jwt.Claims); ok {
+		list = append(list, claims)
+	}
+	var req RefreshRequest
+	if len(c.Body()) > 0 && c.BodyParser(&req) == nil && req.RefreshToken != "" {
+		if claims, err := tokenKeys.Parse(req.RefreshToken, time.Now()); err == nil {
+			list = append(list, claims)
+		}
+	}
+	for _, claims := range list {
+		err := denylist.RevokeToken(c.UserContext(), claims.ID, claims.Subject, claims.Expires())
+		if err != nil && !errors.Is(err, repository.ErrTokenRevoked) {
+			log.Printf("토큰 폐기 실패: %v", err)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package routes
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 업로드 에러 코드
+const (
+	ErrCodeUploadDisabled = "upload_disabled"
+	ErrCodeTooManyFiles   = "too_many_files"
+	ErrCodeFileTooLarge   = "file_too_large"
+	ErrCodeFileType       = "file_type_not_allowed"
+)
+
+// 그누보드 글쓰기 폼의 필드
+const (
+	uploadFileField     = "bf_file[]"
+	uploadContentField  = "bf_content[]"
+	uploadPasswordField = "wr_password"
+	// 손님 글의 비밀번호를 본문 대신 보내는 헤더
+	uploadPasswordHeader = "X-Wr-Password"
+)
+
+// 파일이 아닌 필드 (설명, 비밀번호) 의 최대 합계 바이트
+const maxUploadFieldBytes = 64 << 10
+
+// HWP, DOC, XLS, PPT 의 OLE 복합 문서 시그니처 (http.DetectContentType 은 모름)
+var oleSignature = []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}
+
+const oleType = "application/x-ole-storage"
+
+// 올릴 수 있는 확장자와 내용으로 판별해야 하는 형식. 이름만 바꾼 파일은 거부
+var uploadTypes = map[string]string{
+	"jpg":  "image/jpeg",
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"pdf":  "application/pdf",
+	"txt":  "text/plain",
+	"csv":  "text/plain",
+	"hwp":  oleType,
+	"doc":  oleType,
+	"xls":  oleType,
+	"ppt":  oleType,
+	"hwpx": "application/zip",
+	"docx": "application/zip",
+	"xlsx": "application/zip",
+	"pptx": "application/zip",
+	"zip":  "application/zip",
+	"gz":   "application/x-gzip",
+	"rar":  "application/x-rar-compressed",
+	"mp3":  "audio/mpeg",
+	"mp4":  "video/mp4",
+}
+
+// 이미지 형식별 bf_type (PHP 의 IMAGETYPE_*)
+var imageTypes = map[string]int{
+	"image/gif":  1,
+	"image/jpeg": 2,
+	"image/png":  3,
+}
+
+// UploadRequest documents the multipart body of POST /api/{board}/{id}/files
+// (the field names of Gnuboard's write form)
+type UploadRequest struct {
+	Files    []string `json:"bf_file[]" doc:"파일 (여러 개)"`
+	Contents []string `json:"bf_content[],omitempty" doc:"파일 설명 (bf_file[] 와 같은 순서)"`
+	Password string   `json:"wr_password,omitempty" doc:"손님 글의 비밀번호"`
+}
+
+var fileWriter repository.FileWriter
+
+// InitUploads sets the repository that stores uploaded attachments
+func InitUploads(writer repository.FileWriter) {
+	fileWriter = writer
+}
+
+// isUploadRequest reports whether c is POST /api/{board}/{id}/files, whose
+// body is streamed instead of read into memory
+func isUploadRequest(c *fiber.Ctx) bool {
+	if c.Method() != fiber.MethodPost {
+		return false
+	}
+	parts := strings.Split(strings.Trim(c.Path(), "/"), "/")
+	return len(parts) == 4 && parts[0] == "api" && parts[1] != "v1" && parts[3] == "files"
+}
+
+// LimitBody reads request bodies of up to limit bytes into memory. The
+// server streams request bodies so uploads go straight to disk; every other
+// request keeps Fiber's usual BodyLimit through this middleware.
+func LimitBody(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		stream := c.Context().RequestBodyStream()
+		if stream == nil || isUploadRequest(c) {
+			return c.Next()
+		}
+		if c.Request().Header.ContentLength() > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			c.Context().SetConnectionClose()
+			return fiber.ErrBadRequest
+		}
+		if len(body) > limit {
+			c.Context().SetConnectionClose()
+			return fiber.ErrRequestEntityTooLarge
+		}
+		c.Request().SetBody(body)
+		return c.Next()
+	}
+}
+
+// uploadError is a rejected file, answered with status and code
+type uploadError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *uploadError) Error() string {
+	return e.message
+}
+
+func tooManyFiles(bo board.Board) *uploadError {
+	return &uploadError{fiber.StatusConflict, ErrCodeTooManyFiles, fmt.Sprintf("첨부파일은 글 하나에 %d개까지 올릴 수 있습니다", bo.UploadCount)}
+}
+
+func fileTypeNotAllowed(name string) *uploadError {
+	return &uploadError{fiber.StatusUnsupportedMediaType, ErrCodeFileType, fmt.Sprintf("'%s' 파일은 올릴 수 없는 형식입니다", name)}
+}
+
+// uploadForm is the multipart body of an upload
+type uploadForm struct {
+	files    []*models.BoardFile
+	slots    []int // files[i] 가 몇 번째 bf_file[] 칸인지 (설명과 맞춤)
+	contents []string
+	// 손님 글의 비밀번호 확인. 확인하기 전에 온 파일은 디스크에 쓰지 않고 거부
+	checkPassword func(password string) *uploadError
+}
+
+// remove deletes the files already written to disk
+func (f *uploadForm) remove() {
+	for _, bf := range f.files {
+		if path, ok := attachmentPath(*bf); ok {
+			os.Remove(path)
+		}
+	}
+}
+
+// sniffType is the MIME type of a file judged from its first bytes
+func sniffType(head []byte) string {
+	if bytes.HasPrefix(head, oleSignature) {
+		return oleType
+	}
+	t, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return t
+}
+
+// uploadSourceName cleans an uploaded file name for bf_source like
+// get_safe_filename: no directories, control characters or \/:*?"<>|
+func uploadSourceName(name string) string {
+	name = name[strings.LastIndexAny(name, `/\`)+1:]
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`:*?"<>|`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for utf8.RuneCountInString(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// storedFileName makes a bf_file like write_update.php:
+// md5(sha1(ip))_8 random characters_sha1(random).ext
+func storedFileName(ip, ext string) (string, error) {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	random := make([]byte, 28)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	shuffle := make([]byte, 8)
+	for i, b := range random[:8] {
+		shuffle[i] = letters[int(b)%len(letters)]
+	}
+	ipHash := sha1.Sum([]byte(ip))
+	prefix := md5.Sum([]byte(hex.EncodeToString(ipHash[:])))
+	return fmt.Sprintf("%x_%s_%x.%s", prefix, shuffle, sha1.Sum(random[8:]), ext), nil
+}
+
+// saveUpload streams one file part to data/file/{bo_table} after checking
+// its extension against the sniffed content. It returns nil for an empty part.
+func saveUpload(bo board.Board, part *multipart.Part, ip string) (*models.BoardFile, error) {
+	name := uploadSourceName(part.FileName())
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	want, ok := uploadTypes[ext]
+	if !ok {
+		return nil, fileTypeNotAllowed(name)
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	head = head[:n]
+	detected := sniffType(head)
+	if detected != want {
+		return nil, fileTypeNotAllowed(name)
+	}
+
+	stored, err := storedFileName(ip, ext)
+	if err != nil {
+		return nil, err
+	}
+	bf := &models.BoardFile{Table: bo.Table, Source: name, File: stored, Datetime: time.Now()}
+	path, _ := attachmentPath(*bf)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// 설정보다 1바이트 더 읽어 넘치는지 확인
+	written, err := io.Copy(out, io.LimitReader(io.MultiReader(bytes.NewReader(head), part), bo.UploadSize+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written > bo.UploadSize {
+		err = &uploadError{fiber.StatusRequestEntityTooLarge, ErrCodeFileTooLarge,
+			fmt.Sprintf("'%s' 파일이 게시판에 설정된 용량 (%d 바이트) 보다 큽니다", name, bo.UploadSize)}
+	}
+	if err == nil {
+		bf.Filesize = written
+		err = readImageSize(bf, path, detected)
+	}
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return bf, nil
+}
+
+// readImageSize fills bf_width, bf_height and bf_type of an image, which
+// must decode as the type its content claims
+func readImageSize(bf *models.BoardFile, path, mimeType string) error {
+	imageType, ok := imageTypes[mimeType]
+	if !ok {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return fileTypeNotAllowed(bf.Source)
+	}
+	bf.Width, bf.Height, bf.Type = cfg.Width, cfg.Height, imageType
+	return nil
+}
+
+// readUpload streams the multipart body: bf_file[] parts to disk, the
+// bf_content[] descriptions and wr_password. At most room files are taken.
+// If checkPassword is set, wr_password must come before the first file.
+// On error the files already written are removed.
+func readUpload(c *fiber.Ctx, bo board.Board, boundary string, room int, checkPassword func(string) *uploadError) (*uploadForm, error) {
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+	form := &uploadForm{checkPassword: checkPassword}
+	fieldBudget := int64(maxUploadFieldBytes)
+	mr := multipart.NewReader(body, boundary)
+	slot := 0
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return form, nil
+		}
+		if err != nil {
+			form.remove()
+			return nil, err
+		}
+		err = readUploadPart(c, bo, part, form, &slot, &fieldBudget, room)
+		part.Close()
+		if err != nil {
+			form.remove()
+			return nil, err
+		}
+	}
+}
+
+func readUploadPart(c *fiber.Ctx, bo board.Board, part *multipart.Part, form *uploadForm, slot *int, budget *int64, room int) error {
+	switch part.FormName() {
+	case uploadFileField:
+		index := *slot
+		*slot++
+		// 파일을 고르지 않은 칸
+		if part.FileName() == "" {
+			return nil
+		}
+		if form.checkPassword != nil {
+			return &uploadError{fiber.StatusForbidden, ErrCodeInvalidPassword, "손님 글은 파일보다 먼저 wr_password 를 보내세요"}
+		}
+		if len(form.files) == room {
+			return tooManyFiles(bo)
+		}
+		bf, err := saveUpload(bo, part, c.IP())
+		if err != nil || bf == nil {
+			return err
+		}
+		form.files = append(form.files, bf)
+		form.slots = append(form.slots, index)
+	case uploadContentField, uploadPasswordField:
+		value, err := io.ReadAll(io.LimitReader(part, *budget+1))
+		if err != nil {
+			return err
+		}
+		if *budget -= int64(len(value)); *budget < 0 {
+			return &uploadError{fiber.StatusRequestEntityTooLarge, ErrCodeInvalidRequest, "파일 설명이 너무 깁니다"}
+		}
+		if part.FormName() == uploadPasswordField {
+			if form.checkPassword != nil {
+				if err := form.checkPassword(string(value)); err != nil {
+					return err
+				}
+				form.checkPassword = nil
+			}
+		} else {
+			form.contents = append(form.contents, string(value))
+		}
+	}
+	return nil
+}
+
+// fileContent is the trimmed bf_content of a file, at most 255 characters
+func fileContent(contents []string, slot int) string {
+	if slot >= len(contents) {
+		return ""
+	}
+	content := strings.TrimSpace(contents[slot])
+	for utf8.RuneCountInString(content) > 255 {
+		_, size := utf8.DecodeLastRuneInString(content)
+		content = content[:len(content)-size]
+	}
+	return content
+}
+
+// removeAttachments deletes the files of deleted g5_board_file rows
+func removeAttachments(list []models.BoardFile) {
+	for _, f := range list {
+		path, ok := attachmentPath(f)
+		if !ok {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("첨부파일 삭제 실패: %v", err)
+		}
+	}
+}
+
+// HandleUploadFiles attaches the multipart bf_file[] files to a post, like
+// the file part of write_update.php
+func HandleUploadFiles(c *fiber.Ctx) error {
+	// 본문을 끝까지 읽지 않고 응답할 수 있으므로 연결을 재사용하지 않음
+	c.Context().SetConnectionClose()
+
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	if bo.UploadCount <= 0 {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeUploadDisabled, "이 게시판은 파일을 올릴 수 없습니다")
+	}
+	if denied := boardAccess(c, bo, board.ActionUpload); denied != nil {
+		return v1Denied(c, denied)
+	}
+	post, e := findPost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	// 회원 글은 본문을 받기 전에 확인. 손님 글은 X-Wr-Password 헤더나 파일보다 앞의 wr_password 로 확인
+	var checkPassword func(string) *uploadError
+	if password := c.Get(uploadPasswordHeader); post.MemberID != "" || password != "" {
+		if status, code, message := ownerDenied(c, bo, post.MemberID, post.Password, password); status != 0 {
+			return v1Error(c, status, code, message)
+		}
+	} else if status, _, _ := ownerDenied(c, bo, "", post.Password, ""); status != 0 {
+		checkPassword = func(password string) *uploadError {
+			if status, code, message := ownerDenied(c, bo, "", post.Password, password); status != 0 {
+				return &uploadError{status, code, message}
+			}
+			return nil
+		}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if err != nil || mediaType != fiber.MIMEMultipartForm || params["boundary"] == "" {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "multipart/form-data 로 보내야 합니다")
+	}
+	existing, err := files.ListFiles(c.UserContext(), bo.Table, post.ID)
+	if err != nil {
+		return v1UploadFailed(c, err)
+	}
+	room := bo.UploadCount - len(existing)
+	if room <= 0 {
+		full := tooManyFiles(bo)
+		return v1Error(c, full.status, full.code, full.message)
+	}
+
+	form, err := readUpload(c, bo, params["boundary"], room, checkPassword)
+	var rejected *uploadError
+	switch {
+	case errors.As(err, &rejected):
+		return v1Error(c, rejected.status, rejected.code, rejected.message)
+	case err != nil:
+		log.Printf("업로드 읽기 실패: %v", err)
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+	}
+
+	if form.checkPassword != nil {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeInvalidPassword, "비밀번호를 입력하세요")
+	}
+	if len(form.files) == 0 {
+		return v1ValidationError(c, map[string]string{uploadFileField: "파일을 선택하세요"})
+	}
+	for i, bf := range form.files {
+		bf.Content = fileContent(form.contents, form.slots[i])
+	}
+
+	if err := fileWriter.AddFiles(c.UserContext(), bo, post.ID, form.files); err != nil {
+		form.remove()
+		if errors.Is(err, repository.ErrTooManyFiles) {
+			full := tooManyFiles(bo)
+			return v1Error(c, full.status, full.code, full.message)
+		}
+		return v1UploadFailed(c, err)
+	}
+	result := make([]Attachment, 0, len(form.files))
+	for _, bf := range form.files {
+		result = append(result, toAttachment(*bf))
+	}
+	return c.Status(fiber.StatusCreated).JSON(Envelope[[]Attachment]{Data: result})
+}
+
+func v1UploadFailed(c *fiber.Ctx, err error) error {
+	log.Printf("첨부파일 저장 실패: %v", err)
+	return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "파일 저장 중 오류가 발생했습니다")
+}
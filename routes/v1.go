@@ -0,0 +1,328 @@
+package routes
+
+import (
+	"log"
+
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+	"fibergo/search"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// v1 에러 코드
+const (
+	ErrCodeInvalidBoard  = "invalid_board"
+	ErrCodeInvalidID     = "invalid_id"
+	ErrCodeInvalidCursor = "invalid_cursor"
+	ErrCodeInvalidSearch = "invalid_search"
+	ErrCodeInvalidPage   = "invalid_page"
+	ErrCodeNotFound      = "not_found"
+	ErrCodeInternal      = "internal_error"
+
+	ErrCodeInvalidRequest     = "invalid_request"
+	ErrCodeInvalidCredentials = "invalid_credentials"
+	ErrCodeMemberLeft         = "member_left"
+	ErrCodeMemberIntercepted  = "member_intercepted"
+	ErrCodeUnauthorized       = "unauthorized"
+	ErrCodeInvalidToken       = "invalid_token"
+	ErrCodeTokenExpired       = "token_expired"
+	ErrCodeTokensDisabled     = "tokens_disabled"
+)
+
+func v1Error(c *fiber.Ctx, status int, code, message string) error {
+	return c.Status(status).JSON(ErrorEnvelope{Error: APIError{Code: code, Message: message}})
+}
+
+func v1InvalidBoard(c *fiber.Ctx) error {
+	return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidBoard, "유효하지 않은 게시판입니다")
+}
+
+// v1PostID parses the :id parameter
+func v1PostID(c *fiber.Ctx) (int, bool) {
+	id, err := c.ParamsInt("id")
+	return id, err == nil && id > 0
+}
+
+func v1InvalidID(c *fiber.Ctx) error {
+	return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidID, "잘못된 게시글 ID입니다")
+}
+
+// HandleBoardsV1 lists the registered boards
+func HandleBoardsV1(c *fiber.Ctx) error {
+	list := []BoardInfo{}
+	if boards != nil {
+		for _, b := range boards.List() {
+			list = append(list, toBoardInfo(c, b))
+		}
+	}
+	return c.JSON(Envelope[[]BoardInfo]{Data: list})
+}
+
+// HandleBoardV1 returns a single board
+func HandleBoardV1(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	return c.JSON(Envelope[BoardInfo]{Data: toBoardInfo(c, bo)})
+}
+
+// HandlePostsV1 lists posts of a board
+func HandlePostsV1(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	if denied := boardAccess(c, bo, board.ActionList); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	perPage := pageSize(c, "per_page", bo.PageRows)
+	meta := &Meta{PerPage: perPage}
+	opts := repository.ListOptions{Limit: perPage + 1}
+
+	// cursor 파라미터가 있으면 keyset 페이지 (빈 값은 처음부터)
+	if c.Context().QueryArgs().Has("cursor") {
+		if raw := c.Query("cursor"); raw != "" {
+			cursor, err := repository.DecodeCursor(raw)
+			if err != nil {
+				return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidCursor, "잘못된 cursor 입니다")
+			}
+			opts.After = &cursor
+		}
+	} else {
+		meta.Page = pageNumber(c)
+		opts.Offset = (meta.Page - 1) * perPage
+	}
+
+	// count=false 이면 전체 개수 조회 생략
+	if c.QueryBool("count", true) {
+		total, err := posts.CountPosts(c.UserContext(), bo.Table)
+		if err != nil {
+			log.Printf("게시글 수 조회 실패: %v", err)
+			return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "게시글 수 조회 중 오류가 발생했습니다")
+		}
+		meta.Total = &total
+	}
+
+	list, err := posts.ListPosts(c.UserContext(), bo.Table, opts)
+	if err != nil {
+		log.Printf("게시글 목록 조회 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "게시글 목록 조회 중 오류가 발생했습니다")
+	}
+
+	// 한 개 더 읽어서 다음 페이지 여부 판단
+	if len(list) > perPage {
+		list = list[:perPage]
+		last := list[perPage-1]
+		meta.NextCursor = repository.Cursor{Num: last.Num, Reply: last.Reply}.Encode()
+	}
+
+	data := make([]PostSummary, 0, len(list))
+	thumbs := galleryThumbnails(c, bo, list)
+	for _, p := range list {
+		summary := toPostSummary(bo.Table, p)
+		summary.Thumbnail = thumbs[p.ID]
+		data = append(data, summary)
+	}
+	return c.JSON(Envelope[[]PostSummary]{Data: data, Meta: meta})
+}
+
+// HandleSearchV1 searches posts of a board with Gnuboard's sfl/stx/sop/spt parameters
+func HandleSearchV1(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	if denied := boardAccess(c, bo, board.ActionList); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	result, err := runSearch(c, bo, pageSize(c, "per_page", bo.PageRows))
+	if err == search.ErrInvalidField {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidSearch, "지원하지 않는 검색 필드입니다")
+	}
+	if err != nil {
+		log.Printf("게시글 검색 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "게시글 검색 중 오류가 발생했습니다")
+	}
+	if result.Query.Empty() {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidSearch, "검색어(stx)를 입력하세요")
+	}
+
+	data := make([]SearchResult, 0, len(result.Posts))
+	for _, p := range result.Posts {
+		data = append(data, SearchResult{
+			PostSummary: toPostSummary(bo.Table, p),
+			SubjectHTML: search.Highlight(p.Subject, result.Query.Words),
+			SnippetHTML: secretSnippet(c, bo, p, search.Snippet(p.Content, result.Query.Words, snippetWidth)),
+		})
+	}
+
+	operator := "or"
+	if result.Query.And {
+		operator = "and"
+	}
+	return c.JSON(Envelope[[]SearchResult]{
+		Data: data,
+		Meta: &Meta{
+			Page:    result.Page,
+			PerPage: result.PerPage,
+			Total:   &result.Total,
+			Search: &SearchMeta{
+				Field:    result.Query.SFL,
+				Words:    result.Query.Words,
+				Operator: operator,
+				Spt:      result.Spt,
+				PrevSpt:  result.PrevSpt,
+				NextSpt:  result.NextSpt,
+			},
+		},
+	})
+}
+
+// HandlePostV1 returns a single post
+func HandlePostV1(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	post, e := readablePost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	if e := chargeReadPoint(c, bo, *post); e != nil {
+		return v1PointError(c, e)
+	}
+
+	// 조회수 증가
+	countHit(c, bo, id)
+
+	detail := toPostDetail(c, bo, *post)
+	detail.MyVote = myVote(c, bo, id)
+	detail.Files = attachments(c, bo, *post)
+	return c.JSON(Envelope[PostDetail]{Data: detail})
+}
+
+// HandleCommentsV1 lists comments of a post
+func HandleCommentsV1(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	// 댓글은 글 보기 화면에 붙어 있으므로 읽기 권한으로 판단
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	// 비밀글의 댓글도 글을 볼 수 있어야 조회
+	post, e := readablePost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+
+	page := pageNumber(c)
+	perPage := pageSize(c, "per_page", defaultCommentPageSize)
+	list, total, err := commentPage(c.UserContext(), bo.Table, id, page, perPage)
+	if err != nil {
+		log.Printf("댓글 조회 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "댓글 조회 중 오류가 발생했습니다")
+	}
+	redactComments(c, bo, *post, list)
+
+	data := make([]CommentItem, 0, len(list))
+	for _, cm := range list {
+		data = append(data, toCommentItem(c, bo, cm))
+	}
+	return c.JSON(Envelope[[]CommentItem]{
+		Data: data,
+		Meta: &Meta{Page: page, PerPage: perPage, Total: &total},
+	})
+}
+
+func toBoardInfo(c *fiber.Ctx, b board.Board) BoardInfo {
+	return BoardInfo{
+		ID:            b.Table,
+		Name:          b.Subject,
+		ListLevel:     b.ListLevel,
+		ReadLevel:     b.ReadLevel,
+		CommentLevel:  b.CommentLevel,
+		WriteLevel:    b.WriteLevel,
+		ReplyLevel:    b.ReplyLevel,
+		UseSecret:     b.UseSecret,
+		UseGood:       b.UseGood,
+		UseNogood:     b.UseNogood,
+		PerPage:       b.PageRows,
+		MobilePerPage: b.MobilePageRows,
+		UploadCount:   b.UploadCount,
+		UploadSize:    b.UploadSize,
+		Permissions:   boardPermissions(c, b),
+	}
+}
+
+func toPostSummary(table string, p models.Post) PostSummary {
+	return PostSummary{
+		ID:           p.ID,
+		BoardID:      table,
+		Subject:      p.Subject,
+		Category:     p.CategoryName,
+		Author:       Author{Name: p.Name, MemberID: p.MemberID},
+		CreatedAt:    p.Datetime,
+		Views:        p.Hit,
+		Recommends:   p.Good,
+		Unrecommends: p.Nogood,
+		CommentCount: p.CommentCount,
+		IsReply:      p.Reply != "",
+		IsSecret:     p.HasOption("secret"),
+	}
+}
+
+func toPostDetail(c *fiber.Ctx, bo board.Board, p models.Post) PostDetail {
+	links := []string{}
+	for _, l := range []string{p.Link1, p.Link2} {
+		if l != "" {
+			links = append(links, l)
+		}
+	}
+	return PostDetail{
+		PostSummary: toPostSummary(bo.Table, p),
+		Source:      contentSource(c, bo, p.MemberID, p.Content),
+		Format:      formatName(contentFormat(p)),
+		ContentHTML: postHTML(p),
+		ContentText: postText(p),
+		Links:       links,
+		Files:       []Attachment{},
+	}
+}
+
+func toCommentItem(c *fiber.Ctx, bo board.Board, cm models.Comment) CommentItem {
+	item := CommentItem{
+		ID:          cm.ID,
+		PostID:      cm.Parent,
+		Source:      contentSource(c, bo, cm.MemberID, cm.Content),
+		ContentHTML: commentHTML(cm),
+		Author:      Author{Name: cm.Name, MemberID: cm.MemberID},
+		CreatedAt:   cm.Datetime,
+		Depth:       cm.Depth(),
+		IsSecret:    cm.HasOption("secret"),
+	}
+	if cm.ReplyTo != 0 {
+		parent := cm.ReplyTo
+		item.ParentCommentID = &parent
+	}
+	return item
+}
@@ -0,0 +1,182 @@
+package routes
+
+import "time"
+
+// Envelope wraps every /api/v1 response
+type Envelope[T any] struct {
+	Data T     `json:"data"`
+	Meta *Meta `json:"meta,omitempty"`
+}
+
+// ErrorEnvelope is the body of every failed /api/v1 response
+type ErrorEnvelope struct {
+	Error APIError `json:"error"`
+}
+
+// APIError describes a failed request
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// validation_failed 일 때 필드별 메시지
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Meta carries paging information of list responses. Page is omitted in
+// cursor mode, Total when the count was skipped and NextCursor on the last page.
+type Meta struct {
+	Page       int         `json:"page,omitempty"`
+	PerPage    int         `json:"per_page"`
+	Total      *int        `json:"total,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Search     *SearchMeta `json:"search,omitempty"`
+
+	// 여러 게시판을 합친 목록 (/api/latest, /api/search)
+	HasMore      bool     `json:"has_more,omitempty"`
+	FailedBoards []string `json:"failed_boards,omitempty"`
+}
+
+// SearchMeta describes a search and its wr_num segment. Results only cover
+// the segment starting at spt; use prev_spt/next_spt to move between segments.
+// Cross-board searches have no segment fields.
+type SearchMeta struct {
+	Field    string   `json:"sfl"`
+	Words    []string `json:"words"`
+	Operator string   `json:"sop"`
+	Spt      int      `json:"spt,omitempty"`
+	PrevSpt  *int     `json:"prev_spt,omitempty"`
+	NextSpt  *int     `json:"next_spt,omitempty"`
+}
+
+// BoardInfo is a board in the v1 schema
+type BoardInfo struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	ListLevel     int    `json:"list_level"`
+	ReadLevel     int    `json:"read_level"`
+	CommentLevel  int    `json:"comment_level"`
+	WriteLevel    int    `json:"write_level"`
+	ReplyLevel    int    `json:"reply_level"`
+	UseSecret     int    `json:"use_secret"`
+	UseGood       bool   `json:"use_good"`
+	UseNogood     bool   `json:"use_nogood"`
+	PerPage       int    `json:"per_page"`
+	MobilePerPage int    `json:"mobile_per_page"`
+	UploadCount   int    `json:"upload_count" doc:"글 하나의 최대 첨부파일 수 (0 이면 업로드 안 함)"`
+	UploadSize    int64  `json:"upload_size" doc:"파일 하나의 최대 바이트"`
+	// 요청한 회원(또는 손님)의 권한
+	Permissions BoardPermissions `json:"permissions"`
+}
+
+// BoardPermissions says which board actions the caller's mb_level allows
+type BoardPermissions struct {
+	List    bool `json:"list"`
+	Read    bool `json:"read"`
+	Comment bool `json:"comment"`
+	Write   bool `json:"write"`
+	Reply   bool `json:"reply"`
+}
+
+// Author identifies who wrote a post or comment
+type Author struct {
+	Name     string `json:"name"`
+	MemberID string `json:"member_id"`
+}
+
+// PostSummary is a post in a board list
+type PostSummary struct {
+	ID           int       `json:"id"`
+	BoardID      string    `json:"board_id"`
+	Subject      string    `json:"subject"`
+	Category     string    `json:"category"`
+	Author       Author    `json:"author"`
+	CreatedAt    time.Time `json:"created_at"`
+	Views        int       `json:"views"`
+	Recommends   int       `json:"recommends"`
+	Unrecommends int       `json:"unrecommends"`
+	CommentCount int       `json:"comment_count"`
+	IsReply      bool      `json:"is_reply"`
+	// wr_option 에 secret (권한이 없으면 상세 조회는 403)
+	IsSecret bool `json:"is_secret"`
+	// 갤러리 게시판 목록에서만: 첫 이미지 첨부파일이나 본문 이미지의 썸네일 주소
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// SearchResult is a post matching a search. The HTML fields are escaped
+// text with matches wrapped in <mark>.
+type SearchResult struct {
+	PostSummary
+	SubjectHTML string `json:"subject_html"`
+	SnippetHTML string `json:"snippet_html"`
+}
+
+// FeedItem is a post of a cross-board list. The HTML fields are only set by search.
+type FeedItem struct {
+	PostSummary
+	BoardName   string `json:"board_name"`
+	SubjectHTML string `json:"subject_html,omitempty"`
+	SnippetHTML string `json:"snippet_html,omitempty"`
+}
+
+// PostDetail is a single post with its body
+type PostDetail struct {
+	PostSummary
+	// 저장된 wr_content 원문 (수정 폼용). 글쓴이와 관리자에게만 주고 화면에는 content_html 을 씀
+	Source      string   `json:"source,omitempty"`
+	Format      string   `json:"format" doc:"text, html1, html2 (wr_option)"`
+	ContentHTML string   `json:"content_html" doc:"허용 목록으로 거른 HTML (텍스트 글은 이스케이프, 자동 링크, 줄바꿈 <br>)"`
+	ContentText string   `json:"content_text" doc:"태그를 뺀 본문"`
+	Links       []string `json:"links"`
+	// 로그인한 회원이 이미 한 추천 (good, nogood). 손님이거나 추천하지 않았으면 생략
+	MyVote string       `json:"my_vote,omitempty"`
+	Files  []Attachment `json:"files"`
+}
+
+// Attachment is a g5_board_file row of a post
+type Attachment struct {
+	No          int    `json:"no" doc:"bf_no (0부터)"`
+	Name        string `json:"name" doc:"올린 파일명 (bf_source)"`
+	Description string `json:"description"`
+	Size        int64  `json:"size" doc:"바이트"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	IsImage     bool   `json:"is_image"`
+	Downloads   int    `json:"downloads"`
+	URL         string `json:"url" doc:"다운로드 경로 (/api/{board}/{id}/files/{no})"`
+}
+
+// PointItem is a g5_point row of the logged-in member
+type PointItem struct {
+	ID        int       `json:"id"`
+	Content   string    `json:"content" doc:"po_content (예: 자유게시판 3 글쓰기)"`
+	Point     int       `json:"point"`
+	Balance   int       `json:"balance" doc:"이 내역 후 보유 포인트 (po_mb_point)"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresOn string    `json:"expires_on" doc:"po_expire_date (9999-12-31 이면 기한 없음)"`
+	RelTable  string    `json:"rel_table"`
+	RelID     string    `json:"rel_id"`
+	RelAction string    `json:"rel_action" doc:"쓰기, 댓글, 읽기, 다운로드 등"`
+}
+
+// VoteResult is the response of POST /api/{board}/{id}/good and /nogood
+type VoteResult struct {
+	Recommends   int    `json:"recommends"`
+	Unrecommends int    `json:"unrecommends"`
+	MyVote       string `json:"my_vote"`
+}
+
+// CommentItem is a comment of a post
+type CommentItem struct {
+	ID     int `json:"id"`
+	PostID int `json:"post_id"`
+	// 저장된 댓글 원문 (수정 폼용). 댓글 작성자와 관리자에게만
+	Source string `json:"source,omitempty"`
+	// 이스케이프하고 자동 링크, 줄바꿈 <br> 을 넣은 댓글
+	ContentHTML string    `json:"content_html"`
+	Author      Author    `json:"author"`
+	CreatedAt   time.Time `json:"created_at"`
+	// 원댓글은 0, 대댓글은 wr_comment_reply 길이
+	Depth           int  `json:"depth"`
+	ParentCommentID *int `json:"parent_comment_id" openapi:"nullable" doc:"부모 댓글 ID (원댓글이면 null)"`
+	// 비밀 댓글 (관리자, 원글 작성자, 댓글 작성자가 아니면 content_html 은 "비밀글 입니다.")
+	IsSecret bool `json:"is_secret"`
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"errors"
+	"log"
+
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 추천 에러 코드
+const (
+	ErrCodeVoteDisabled = "vote_disabled"
+	ErrCodeOwnPost      = "own_post"
+	ErrCodeAlreadyVoted = "already_voted"
+)
+
+var votes repository.VoteRepository
+
+// InitVotes sets the repository used by the good/nogood handlers
+func InitVotes(repo repository.VoteRepository) {
+	votes = repo
+}
+
+// myVote returns the caller's vote on the post, "" for guests
+func myVote(c *fiber.Ctx, bo board.Board, postID int) string {
+	m := auth.CurrentMember(c)
+	if m == nil || votes == nil {
+		return ""
+	}
+	flag, err := votes.MyVote(c.UserContext(), bo.Table, postID, m.ID)
+	if err != nil {
+		log.Printf("추천 여부 조회 실패: %v", err)
+	}
+	return flag
+}
+
+// HandleGood recommends a post
+func HandleGood(c *fiber.Ctx) error {
+	return handleVote(c, repository.VoteGood)
+}
+
+// HandleNogood not-recommends a post
+func HandleNogood(c *fiber.Ctx) error {
+	return handleVote(c, repository.VoteNogood)
+}
+
+// handleVote follows good.php: members only, once per post, never on one's own post
+func handleVote(c *fiber.Ctx, flag string) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	if flag == repository.VoteGood && !bo.UseGood {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeVoteDisabled, "이 게시판은 추천 기능을 사용하지 않습니다")
+	}
+	if flag == repository.VoteNogood && !bo.UseNogood {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeVoteDisabled, "이 게시판은 비추천 기능을 사용하지 않습니다")
+	}
+	m := auth.CurrentMember(c)
+	if m == nil {
+		return v1Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, "회원만 추천할 수 있습니다. 로그인 후 이용해 주세요")
+	}
+	if denied := boardAccess(c, bo, board.ActionRead); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	post, e := readablePost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	if post.MemberID == m.ID {
+		return v1Error(c, fiber.StatusForbidden, ErrCodeOwnPost, "자신의 글에는 추천 또는 비추천 하실 수 없습니다")
+	}
+
+	good, nogood, err := votes.Vote(c.UserContext(), bo.Table, id, m.ID, flag)
+	switch {
+	case errors.Is(err, repository.ErrAlreadyVoted):
+		return v1Error(c, fiber.StatusConflict, ErrCodeAlreadyVoted, "이미 추천 또는 비추천 하신 글입니다")
+	case errors.Is(err, repository.ErrNotFound):
+		return v1Error(c, fiber.StatusNotFound, ErrCodeNotFound, "게시글을 찾을 수 없습니다")
+	case err != nil:
+		log.Printf("추천 저장 실패: %v", err)
+		return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "추천 처리 중 오류가 발생했습니다")
+	}
+	return c.JSON(Envelope[VoteResult]{Data: VoteResult{Recommends: good, Unrecommends: nogood, MyVote: flag}})
+}
@@ -0,0 +1,347 @@
+package routes
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"fibergo/auth"
+	"fibergo/board"
+	"fibergo/models"
+	"fibergo/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 글쓰기 에러 코드
+const (
+	ErrCodeValidation  = "validation_failed"
+	ErrCodeReplyLimit  = "reply_limit"
+	ErrCodeHasReplies  = "has_replies"
+	ErrCodeHasComments = "has_comments"
+)
+
+// 제목 최대 길이 (wr_subject varchar(255))
+const maxSubjectLength = 255
+
+var postWriter repository.PostWriter
+var commentWriter repository.CommentWriter
+
+// InitWriter sets the repositories used by the write handlers
+func InitWriter(writer repository.PostWriter, commentRepo repository.CommentWriter) {
+	postWriter = writer
+	commentWriter = commentRepo
+}
+
+// PostRequest is the body of POST /api/{board} and PUT /api/{board}/{id}
+// (JSON or the field names of Gnuboard's write form)
+type PostRequest struct {
+	Subject  string `json:"subject" form:"wr_subject"`
+	Content  string `json:"content" form:"wr_content"`
+	Category string `json:"category,omitempty" form:"ca_name"`
+	Format   string `json:"format,omitempty" form:"html" doc:"text (기본), html1, html2"`
+	Secret   bool   `json:"secret,omitempty" form:"secret"`
+	Link1    string `json:"link1,omitempty" form:"wr_link1"`
+	Link2    string `json:"link2,omitempty" form:"wr_link2"`
+	ReplyTo  int    `json:"reply_to,omitempty" form:"reply_to" doc:"답변할 글 ID (작성할 때만)"`
+
+	// 손님 글: 작성할 때 이름과 비밀번호 필수, 수정할 때는 비밀번호로 확인
+	Name     string `json:"name,omitempty" form:"wr_name"`
+	Password string `json:"password,omitempty" form:"wr_password"`
+	Email    string `json:"email,omitempty" form:"wr_email"`
+	Homepage string `json:"homepage,omitempty" form:"wr_homepage"`
+}
+
+// DeleteRequest is the optional body of DELETE /api/{board}/{id}
+type DeleteRequest struct {
+	Password string `json:"password,omitempty" form:"wr_password" doc:"손님 글의 비밀번호"`
+}
+
+func v1ValidationError(c *fiber.Ctx, fields map[string]string) error {
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(ErrorEnvelope{Error: APIError{
+		Code:    ErrCodeValidation,
+		Message: "입력값을 확인하세요",
+		Fields:  fields,
+	}})
+}
+
+// validatePost checks the request like write_update.php; guest is set when
+// a guest creates a post and must give a name and password
+func validatePost(req PostRequest, guest bool) map[string]string {
+	fields := map[string]string{}
+	switch subject := strings.TrimSpace(req.Subject); {
+	case subject == "":
+		fields["subject"] = "제목을 입력하세요"
+	case utf8.RuneCountInString(subject) > maxSubjectLength:
+		fields["subject"] = "제목은 255자 이내로 입력하세요"
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		fields["content"] = "내용을 입력하세요"
+	}
+	switch req.Format {
+	case "", "text", "html1", "html2":
+	default:
+		fields["format"] = "text, html1, html2 중 하나여야 합니다"
+	}
+	if guest {
+		if strings.TrimSpace(req.Name) == "" {
+			fields["name"] = "이름을 입력하세요"
+		}
+		if req.Password == "" {
+			fields["password"] = "비밀번호를 입력하세요"
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// postOptions builds wr_option. bo_use_secret 2 makes every post secret,
+// 0 allows secret posts only to admins, and answers to a secret post stay secret.
+func postOptions(c *fiber.Ctx, bo board.Board, req PostRequest, previous string, parent *models.Post) string {
+	var opts []string
+	if req.Format == "html1" || req.Format == "html2" {
+		opts = append(opts, req.Format)
+	}
+	secret := req.Secret
+	switch {
+	case bo.UseSecret == 2 || (parent != nil && parent.HasOption("secret")):
+		secret = true
+	case bo.UseSecret == 0 && !isBoardAdmin(c, bo):
+		secret = false
+	}
+	if secret {
+		opts = append(opts, "secret")
+	}
+	// 메일 답변 옵션은 그대로
+	if hasOptionValue(previous, "mail") {
+		opts = append(opts, "mail")
+	}
+	return strings.Join(opts, ",")
+}
+
+func hasOptionValue(options, opt string) bool {
+	return models.Post{Option: options}.HasOption(opt)
+}
+
+// ownerDenied checks that the caller may change a post or comment written by
+// mbID (hash is its wr_password): admins, the member who wrote it, or anyone
+// with the password of a guest's row. status is 0 if allowed.
+func ownerDenied(c *fiber.Ctx, bo board.Board, mbID, hash, password string) (status int, code, message string) {
+	if isBoardAdmin(c, bo) {
+		return 0, "", ""
+	}
+	m := auth.CurrentMember(c)
+	if mbID != "" {
+		switch {
+		case m != nil && m.ID == mbID:
+			return 0, "", ""
+		case m == nil:
+			return fiber.StatusUnauthorized, ErrCodeUnauthorized, "회원이 쓴 글입니다. 로그인 후 이용해 주세요"
+		}
+		return fiber.StatusForbidden, ErrCodeForbidden, "자신의 글만 수정, 삭제할 수 있습니다"
+	}
+	if !auth.CheckPassword(password, hash) {
+		return fiber.StatusForbidden, ErrCodeInvalidPassword, "비밀번호가 틀립니다"
+	}
+	return 0, "", ""
+}
+
+// commentLimitReached applies bo_count_modify / bo_count_delete: a post
+// with limit or more comments by other people can't be changed (0 = no limit)
+func commentLimitReached(c *fiber.Ctx, bo board.Board, p models.Post, limit int) (bool, error) {
+	if limit <= 0 || isBoardAdmin(c, bo) {
+		return false, nil
+	}
+	var mbID string
+	if m := auth.CurrentMember(c); m != nil {
+		mbID = m.ID
+	}
+	n, err := postWriter.CountOtherComments(c.UserContext(), bo.Table, p.ID, mbID)
+	return n >= limit, err
+}
+
+func v1CommentLimit(c *fiber.Ctx, limit int, verb string) error {
+	return v1Error(c, fiber.StatusConflict, ErrCodeHasComments,
+		"다른 사람의 댓글이 "+strconv.Itoa(limit)+"개 이상 달린 글은 "+verb+"할 수 없습니다")
+}
+
+func v1WriteFailed(c *fiber.Ctx, err error) error {
+	log.Printf("게시글 저장 실패: %v", err)
+	return v1Error(c, fiber.StatusInternalServerError, ErrCodeInternal, "게시글 저장 중 오류가 발생했습니다")
+}
+
+// HandleCreatePost writes a new post, or an answer if reply_to is set
+func HandleCreatePost(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	var req PostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+	}
+
+	action := board.ActionWrite
+	if req.ReplyTo != 0 {
+		action = board.ActionReply
+	}
+	if denied := boardAccess(c, bo, action); denied != nil {
+		return v1Denied(c, denied)
+	}
+
+	m := auth.CurrentMember(c)
+	if fields := validatePost(req, m == nil); fields != nil {
+		return v1ValidationError(c, fields)
+	}
+
+	// 답변은 원글을 읽을 수 있어야 함
+	var parent *models.Post
+	if req.ReplyTo != 0 {
+		var e *fiber.Error
+		if parent, e = readablePost(c, bo, req.ReplyTo); e != nil {
+			return v1PostError(c, e)
+		}
+	}
+
+	post := models.Post{
+		CategoryName: req.Category,
+		Option:       postOptions(c, bo, req, "", parent),
+		Subject:      strings.TrimSpace(req.Subject),
+		Content:      req.Content,
+		Link1:        req.Link1,
+		Link2:        req.Link2,
+		IP:           c.IP(),
+	}
+	if m != nil {
+		post.MemberID, post.Name, post.Email = m.ID, m.Nick, m.Email
+	} else {
+		hash, err := auth.HashPassword(req.Password)
+		if err != nil {
+			return v1WriteFailed(c, err)
+		}
+		post.Name, post.Password = strings.TrimSpace(req.Name), hash
+		post.Email, post.Homepage = req.Email, req.Homepage
+	}
+
+	if err := postWriter.CreatePost(c.UserContext(), bo, &post, parent); err != nil {
+		if errors.Is(err, repository.ErrReplyLimit) {
+			return v1Error(c, fiber.StatusConflict, ErrCodeReplyLimit, "더 이상 답변할 수 없습니다")
+		}
+		return v1WriteFailed(c, err)
+	}
+
+	c.Location("/api/v1/boards/" + bo.Table + "/posts/" + strconv.Itoa(post.ID))
+	return c.Status(fiber.StatusCreated).JSON(Envelope[PostDetail]{Data: toPostDetail(c, bo, post)})
+}
+
+// HandleUpdatePost edits a post
+func HandleUpdatePost(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	var req PostRequest
+	if err := c.BodyParser(&req); err != nil {
+		return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+	}
+
+	post, e := findPost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	if status, code, message := ownerDenied(c, bo, post.MemberID, post.Password, req.Password); status != 0 {
+		return v1Error(c, status, code, message)
+	}
+	if reached, err := commentLimitReached(c, bo, *post, bo.CountModify); err != nil {
+		return v1WriteFailed(c, err)
+	} else if reached {
+		return v1CommentLimit(c, bo.CountModify, "수정")
+	}
+	if fields := validatePost(req, false); fields != nil {
+		return v1ValidationError(c, fields)
+	}
+
+	// 비밀글에 단 답변은 고쳐도 비밀글 (글쓰기와 같이 원글을 봄)
+	var origin *models.Post
+	if post.Reply != "" {
+		var err error
+		if origin, err = posts.ThreadOrigin(c.UserContext(), bo.Table, post.Num); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return v1WriteFailed(c, err)
+		}
+	}
+
+	post.CategoryName = req.Category
+	post.Option = postOptions(c, bo, req, post.Option, origin)
+	post.Subject = strings.TrimSpace(req.Subject)
+	post.Content = req.Content
+	post.Link1, post.Link2 = req.Link1, req.Link2
+	if post.MemberID == "" {
+		if name := strings.TrimSpace(req.Name); name != "" {
+			post.Name = name
+		}
+		post.Email, post.Homepage = req.Email, req.Homepage
+	}
+	if err := postWriter.UpdatePost(c.UserContext(), bo.Table, *post); err != nil {
+		return v1WriteFailed(c, err)
+	}
+	return c.JSON(Envelope[PostDetail]{Data: toPostDetail(c, bo, *post)})
+}
+
+// HandleDeletePost deletes a post with its comments
+func HandleDeletePost(c *fiber.Ctx) error {
+	bo, ok := LookupBoard(c.Params("type"))
+	if !ok {
+		return v1InvalidBoard(c)
+	}
+	id, ok := v1PostID(c)
+	if !ok {
+		return v1InvalidID(c)
+	}
+	var req DeleteRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return v1Error(c, fiber.StatusBadRequest, ErrCodeInvalidRequest, "요청 본문을 읽을 수 없습니다")
+		}
+	}
+
+	post, e := findPost(c, bo, id)
+	if e != nil {
+		return v1PostError(c, e)
+	}
+	if status, code, message := ownerDenied(c, bo, post.MemberID, post.Password, req.Password); status != 0 {
+		return v1Error(c, status, code, message)
+	}
+
+	// 답변글이 있으면 답변부터 삭제해야 함 (관리자 제외)
+	if !isBoardAdmin(c, bo) {
+		n, err := postWriter.CountReplies(c.UserContext(), bo.Table, *post)
+		if err != nil {
+			return v1WriteFailed(c, err)
+		}
+		if n > 0 {
+			return v1Error(c, fiber.StatusConflict, ErrCodeHasReplies, "답변글이 있는 글은 삭제할 수 없습니다. 답변글부터 삭제해 주세요")
+		}
+	}
+	if reached, err := commentLimitReached(c, bo, *post, bo.CountDelete); err != nil {
+		return v1WriteFailed(c, err)
+	} else if reached {
+		return v1CommentLimit(c, bo.CountDelete, "삭제")
+	}
+
+	attached := postFiles(c, bo, *post)
+	if err := postWriter.DeletePost(c.UserContext(), bo.Table, *post); err != nil {
+		return v1WriteFailed(c, err)
+	}
+	// 그누보드처럼 첨부파일과 썸네일도 지움
+	removeThumbnails(c, attached, post.Content)
+	removeAttachments(attached)
+	return c.SendStatus(fiber.StatusNoContent)
+}
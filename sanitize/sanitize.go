@@ -0,0 +1,312 @@
+// Package sanitize renders wr_content like Gnuboard's conv_content: HTML
+// posts are cleaned with an allowlist (html_purifier), plain text posts are
+// escaped, auto-linked and get <br> for newlines.
+package sanitize
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// Format is how wr_content is written (wr_option)
+type Format int
+
+const (
+	Text       Format = iota // html 옵션 없음
+	HTML                     // html1
+	HTMLBreaks               // html2: HTML + 자동 줄바꿈
+)
+
+// DefaultAllowlist is the allowlist used when HTML_ALLOWED_TAGS is not set.
+// Entries are tag or tag:attr|attr, and *:attr allows attr on every tag.
+const DefaultAllowlist = "*:class|style|title|align " +
+	"a:href|target|name abbr b blockquote:cite br caption code col:span|width colgroup:span|width " +
+	"dd del div dl dt em figcaption figure font:color|size|face h1 h2 h3 h4 h5 h6 hr i " +
+	"img:src|alt|width|height ins kbd li mark ol:start|type p pre q:cite s small span strike strong sub sup " +
+	"table:border|cellpadding|cellspacing|width|summary tbody td:colspan|rowspan|width|height|valign tfoot " +
+	"th:colspan|rowspan|width|height|valign|scope thead tr u ul"
+
+// 내용까지 버리는 태그 (허용 목록에 넣을 수 없음)
+var dropped = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true, "object": true,
+	"embed": true, "applet": true, "noscript": true, "noembed": true, "noframes": true, "template": true,
+	"textarea": true, "select": true, "title": true, "xmp": true, "plaintext": true, "svg": true,
+	"math": true, "head": true, "base": true, "meta": true, "link": true, "form": true,
+}
+
+// 닫는 태그가 없는 태그
+var voids = map[string]bool{
+	"br": true, "hr": true, "img": true, "col": true, "wbr": true,
+	"base": true, "meta": true, "link": true,
+}
+
+// 주소를 담는 속성과 허용하는 scheme (scheme 이 없는 상대 주소는 허용)
+var urlSchemes = map[string][]string{
+	"href": {"http", "https", "mailto", "tel", "ftp"},
+	"src":  {"http", "https"},
+	"cite": {"http", "https"},
+}
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Policy is an allowlist of tags and their attributes
+type Policy struct {
+	elements map[string]map[string]bool
+}
+
+// NewPolicy reads an allowlist in the DefaultAllowlist format (entries
+// separated by spaces or commas)
+func NewPolicy(spec string) (*Policy, error) {
+	p := &Policy{elements: map[string]map[string]bool{}}
+	var global []string
+	for _, entry := range strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool { return r == ',' || r == ' ' || r == '\n' || r == '\t' }) {
+		tag, attrs, _ := strings.Cut(entry, ":")
+		if tag != "*" && (!namePattern.MatchString(tag) || dropped[tag]) {
+			return nil, fmt.Errorf("sanitize: invalid allowlist entry %q", entry)
+		}
+		var names []string
+		if attrs != "" {
+			names = strings.Split(attrs, "|")
+		}
+		for _, a := range names {
+			// 이벤트 처리기, 직접 붙이는 rel 은 받지 않음
+			if !namePattern.MatchString(a) || strings.HasPrefix(a, "on") || a == "rel" || a == "srcdoc" || a == "formaction" {
+				return nil, fmt.Errorf("sanitize: invalid attribute in %q", entry)
+			}
+		}
+		if tag == "*" {
+			global = append(global, names...)
+			continue
+		}
+		if p.elements[tag] == nil {
+			p.elements[tag] = map[string]bool{}
+		}
+		for _, a := range names {
+			p.elements[tag][a] = true
+		}
+	}
+	for _, attrs := range p.elements {
+		for _, a := range global {
+			attrs[a] = true
+		}
+	}
+	return p, nil
+}
+
+// Default returns the policy of DefaultAllowlist
+func Default() *Policy {
+	p, err := NewPolicy(DefaultAllowlist)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Content converts wr_content to safe HTML like conv_content
+func (p *Policy) Content(content string, format Format) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	switch format {
+	case HTML:
+		return p.sanitize(content, false)
+	case HTMLBreaks:
+		return p.sanitize(content, true)
+	default:
+		return TextHTML(content)
+	}
+}
+
+// Sanitize removes every tag, attribute and URL the policy does not allow
+func (p *Policy) Sanitize(s string) string {
+	return p.sanitize(s, false)
+}
+
+// sanitize rebuilds s from its tokens, closing every open tag at the end.
+// breaks adds <br> for newlines outside <pre> (html2).
+func (p *Policy) sanitize(s string, breaks bool) string {
+	var b strings.Builder
+	var open []string
+	skip, skipTag := 0, ""
+	pre := 0
+
+	z := nethtml.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		name := tok.Data
+
+		if skip > 0 {
+			// 버리는 태그 안: 같은 태그가 닫힐 때까지 건너뜀
+			switch {
+			case tt == nethtml.StartTagToken && name == skipTag:
+				skip++
+			case tt == nethtml.EndTagToken && name == skipTag:
+				skip--
+			}
+			continue
+		}
+
+		switch tt {
+		case nethtml.TextToken:
+			text := html.EscapeString(tok.Data)
+			if breaks && pre == 0 {
+				text = strings.ReplaceAll(text, "\n", "<br>\n")
+			}
+			b.WriteString(text)
+
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if dropped[name] {
+				if tt == nethtml.StartTagToken && !voids[name] {
+					skip, skipTag = 1, name
+				}
+				continue
+			}
+			attrs, ok := p.elements[name]
+			if !ok {
+				continue
+			}
+			b.WriteString(p.startTag(name, tok.Attr, attrs))
+			switch {
+			case voids[name]:
+			case tt == nethtml.SelfClosingTagToken:
+				b.WriteString("</" + name + ">")
+			default:
+				open = append(open, name)
+				if name == "pre" {
+					pre++
+				}
+			}
+
+		case nethtml.EndTagToken:
+			// 열린 태그만 닫고, 그 사이에 열린 태그도 함께 닫음
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+					if open[j] == "pre" {
+						pre--
+					}
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+func (p *Policy) startTag(name string, attrs []nethtml.Attribute, allowed map[string]bool) string {
+	var b strings.Builder
+	b.WriteString("<" + name)
+	seen := map[string]bool{}
+	target := false
+	for _, a := range attrs {
+		if a.Namespace != "" || !allowed[a.Key] || seen[a.Key] {
+			continue
+		}
+		value, ok := attrValue(a.Key, a.Val)
+		if !ok {
+			continue
+		}
+		seen[a.Key] = true
+		target = target || a.Key == "target"
+		b.WriteString(" " + a.Key + `="` + html.EscapeString(value) + `"`)
+	}
+	// 새 창으로 여는 링크가 원래 창을 조작하지 못하도록
+	if name == "a" && target {
+		b.WriteString(` rel="noopener noreferrer"`)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+func attrValue(key, value string) (string, bool) {
+	if schemes, ok := urlSchemes[key]; ok {
+		return safeURL(value, schemes)
+	}
+	if key == "style" {
+		value = cleanStyle(value)
+		return value, value != ""
+	}
+	return value, true
+}
+
+// safeURL accepts relative URLs and URLs with one of schemes. Browsers
+// ignore whitespace and control characters in the scheme (java\tscript:).
+func safeURL(value string, schemes []string) (string, bool) {
+	value = strings.TrimSpace(value)
+	compact := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, value)
+	i := strings.IndexAny(compact, ":/?#")
+	if i < 0 || compact[i] != ':' {
+		return value, true
+	}
+	scheme := strings.ToLower(compact[:i])
+	for _, s := range schemes {
+		if scheme == s {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// style 에서 남기는 속성 (margin, padding, border 는 -top 등 포함)
+var styleProperties = map[string]bool{
+	"color": true, "background-color": true, "font-size": true, "font-family": true, "font-weight": true,
+	"font-style": true, "text-align": true, "text-decoration": true, "text-indent": true, "line-height": true,
+	"letter-spacing": true, "vertical-align": true, "width": true, "height": true, "max-width": true,
+	"float": true, "display": true, "white-space": true, "list-style-type": true, "border-collapse": true,
+}
+
+var stylePrefixes = []string{"margin", "padding", "border"}
+
+// cleanStyle keeps the declarations of styleProperties whose value cannot
+// load anything or run script (url(), expression(), escapes)
+func cleanStyle(style string) string {
+	var kept []string
+	for _, decl := range strings.Split(style, ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		prop = strings.ToLower(strings.TrimSpace(prop))
+		value = strings.TrimSpace(value)
+		if !ok || value == "" || !styleAllowed(prop) {
+			continue
+		}
+		lower := strings.ToLower(value)
+		if strings.ContainsAny(lower, `\<>"`) || strings.Contains(lower, "/*") {
+			continue
+		}
+		if strings.Contains(lower, "url") || strings.Contains(lower, "expression") ||
+			strings.Contains(lower, "javascript") || strings.Contains(lower, "@") {
+			continue
+		}
+		kept = append(kept, prop+": "+value)
+	}
+	return strings.Join(kept, "; ")
+}
+
+func styleAllowed(prop string) bool {
+	if styleProperties[prop] {
+		return true
+	}
+	for _, p := range stylePrefixes {
+		if prop == p || strings.HasPrefix(prop, p+"-") {
+			return namePattern.MatchString(prop)
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package sanitize
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	p := Default()
+	tests := []struct {
+		name, in, want string
+	}{
+		{"허용 태그", `<p class="x">안녕 <b>굵게</b></p>`, `<p class="x">안녕 <b>굵게</b></p>`},
+		{"script 는 내용까지", `a<script>alert(1)</script>b`, `ab`},
+		{"중첩된 svg", `<svg><svg><a href="/x">x</a></svg><script>1</script></svg>c`, `c`},
+		{"모르는 태그는 내용만", `<marquee>흐름</marquee>`, `흐름`},
+		{"이벤트 속성", `<img src="/data/a.png" onerror="alert(1)">`, `<img src="/data/a.png">`},
+		{"javascript 주소", `<a href="java&#09;script:alert(1)">x</a>`, `<a>x</a>`},
+		{"대문자 scheme", `<a href=" JAVASCRIPT:alert(1)">x</a>`, `<a>x</a>`},
+		{"data 이미지", `<img src="data:image/svg+xml;base64,AAAA">`, `<img>`},
+		{"새 창 링크", `<a href="https://example.com/?a=1&amp;b=2" target="_blank">x</a>`, `<a href="https://example.com/?a=1&amp;b=2" target="_blank" rel="noopener noreferrer">x</a>`},
+		{"rel 은 버림", `<a href="/x" rel="opener">x</a>`, `<a href="/x">x</a>`},
+		{"style 걸러냄", `<span style="color: red; background: url(x); width:expression(1); margin-top:4px">x</span>`, `<span style="color: red; margin-top: 4px">x</span>`},
+		{"안 닫은 태그", `<div><b>굵게`, `<div><b>굵게</b></div>`},
+		{"열지 않은 닫는 태그", `x</div></b>`, `x`},
+		{"엇갈린 태그", `<b><i>x</b>y</i>`, `<b><i>x</i></b>y`},
+		{"텍스트 이스케이프", `&lt;script&gt; "따옴표"`, `&lt;script&gt; &#34;따옴표&#34;`},
+		{"속성 이스케이프", `<img alt='"><script>' src=x>`, `<img alt="&#34;&gt;&lt;script&gt;" src="x">`},
+		{"주석", `a<!-- <script>x</script> -->b`, `ab`},
+	}
+	for _, tt := range tests {
+		if got := p.Sanitize(tt.in); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestContent(t *testing.T) {
+	p := Default()
+	tests := []struct {
+		name   string
+		in     string
+		format Format
+		want   string
+	}{
+		{"html2 자동 줄바꿈", "<p>a\nb</p>\n<pre>c\nd</pre>", HTMLBreaks, "<p>a<br>\nb</p><br>\n<pre>c\nd</pre>"},
+		{"html1 은 그대로", "<p>a\nb</p>", HTML, "<p>a\nb</p>"},
+		{"텍스트", "<b>굵게</b> &amp; &x\r\n  들여쓰기", Text, "&lt;b&gt;굵게&lt;/b&gt; &amp; &amp;x<br>\n&nbsp; 들여쓰기"},
+		{"자동 링크", "보세요: https://example.com/a?b=1&c=2. 또는 www.example.org)", Text,
+			`보세요: <a href="https://example.com/a?b=1&amp;c=2" target="_blank" rel="nofollow noopener noreferrer">https://example.com/a?b=1&amp;c=2</a>. 또는 ` +
+				`<a href="http://www.example.org" target="_blank" rel="nofollow noopener noreferrer">www.example.org</a>)`},
+		{"따옴표 주소", `"javascript:alert(1)" http://x.test/"onmouseover=1`, Text,
+			`&#34;javascript:alert(1)&#34; <a href="http://x.test/" target="_blank" rel="nofollow noopener noreferrer">http://x.test/</a>&#34;onmouseover=1`},
+	}
+	for _, tt := range tests {
+		if got := p.Content(tt.in, tt.format); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	p, err := NewPolicy("*:class, p b:title a:href")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Sanitize(`<p class="c" title="t"><b title="t">x</b><i>y</i><a href="/z" class="c">z</a></p>`); got != `<p class="c"><b title="t">x</b>y<a href="/z" class="c">z</a></p>` {
+		t.Errorf("got %q", got)
+	}
+	for _, bad := range []string{"script", "a:onclick", "a:rel", "iframe:src", "p:x=y"} {
+		if _, err := NewPolicy(bad); err == nil {
+			t.Errorf("NewPolicy(%q) accepted", bad)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		in     string
+		format Format
+		want   string
+	}{
+		{"<p>첫 줄\n이어짐</p><p>둘째&nbsp;줄<br>셋째</p><script>x</script>", HTML, "첫 줄 이어짐\n\n둘째\u00a0줄\n셋째"},
+		{"a\nb<br>c", HTMLBreaks, "a\nb\nc"},
+		{"<table><tr><td>a</td><td>b</td></tr></table>", HTML, "a b"},
+		{"  <b>태그 그대로</b>\r\n", Text, "<b>태그 그대로</b>"},
+	}
+	for _, tt := range tests {
+		if got := PlainText(tt.in, tt.format); got != tt.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
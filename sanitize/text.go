@@ -0,0 +1,154 @@
+package sanitize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// 그누보드 url_auto_link 가 링크로 바꾸는 주소
+var linkPattern = regexp.MustCompile(`(?i)\b(?:(?:https?|ftp)://|www\.)[^\s<>"'` + "`" + `]+`)
+
+// &amp; &nbsp; &#39; 처럼 이미 쓴 문자 참조 (html_symbol 처럼 그대로 둠)
+var entityPattern = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]{1,31}|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+
+// TextHTML converts a plain text post to HTML like conv_content with html 0:
+// escaped, auto-linked, leading and double spaces kept, newlines as <br>
+func TextHTML(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var b strings.Builder
+	last := 0
+	for _, m := range linkPattern.FindAllStringIndex(text, -1) {
+		// 문장 끝의 마침표, 닫는 괄호는 주소에서 뺌
+		end := m[0] + len(strings.TrimRight(text[m[0]:m[1]], ".,;:!?)]}"))
+		b.WriteString(textSegment(text[last:m[0]]))
+		b.WriteString(link(text[m[0]:end]))
+		last = end
+	}
+	b.WriteString(textSegment(text[last:]))
+	return b.String()
+}
+
+func link(addr string) string {
+	href := addr
+	if strings.HasPrefix(strings.ToLower(addr), "www.") {
+		href = "http://" + addr
+	}
+	return `<a href="` + html.EscapeString(href) + `" target="_blank" rel="nofollow noopener noreferrer">` +
+		html.EscapeString(addr) + "</a>"
+}
+
+func textSegment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; ch {
+		case '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				b.WriteString(m)
+				i += len(m) - 1
+			} else {
+				b.WriteString("&amp;")
+			}
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&#34;")
+		case '\'':
+			b.WriteString("&#39;")
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	out := b.String()
+	out = strings.ReplaceAll(out, "  ", "&nbsp; ")
+	out = strings.ReplaceAll(out, "\n ", "\n&nbsp;")
+	if strings.HasPrefix(out, " ") {
+		out = "&nbsp;" + out[1:]
+	}
+	return strings.ReplaceAll(out, "\n", "<br>\n")
+}
+
+// 앞뒤로 줄을 바꾸는 태그
+var blocks = map[string]bool{
+	"p": true, "div": true, "li": true, "tr": true, "table": true, "ul": true, "ol": true, "dl": true,
+	"dt": true, "dd": true, "blockquote": true, "pre": true, "hr": true, "figure": true, "figcaption": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+var (
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+	lineSpaces  = regexp.MustCompile(`[ \t]*\n[ \t]*`)
+	innerSpaces = regexp.MustCompile(`[ \t\r\f\v]+`)
+)
+
+// PlainText returns the text of wr_content without markup, with line breaks
+// where the HTML breaks lines
+func PlainText(content string, format Format) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if format == Text {
+		return strings.TrimSpace(content)
+	}
+
+	var b strings.Builder
+	skip, skipTag := 0, ""
+	pre := 0
+	z := nethtml.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		name := tok.Data
+		if skip > 0 {
+			switch {
+			case tt == nethtml.StartTagToken && name == skipTag:
+				skip++
+			case tt == nethtml.EndTagToken && name == skipTag:
+				skip--
+			}
+			continue
+		}
+		switch tt {
+		case nethtml.TextToken:
+			text := tok.Data
+			if pre == 0 {
+				// HTML 의 줄바꿈은 공백 (html2 는 그대로 줄바꿈)
+				if format == HTML {
+					text = strings.ReplaceAll(text, "\n", " ")
+				}
+				text = innerSpaces.ReplaceAllString(text, " ")
+			}
+			b.WriteString(text)
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			switch {
+			case dropped[name]:
+				if tt == nethtml.StartTagToken && !voids[name] {
+					skip, skipTag = 1, name
+				}
+			case name == "br":
+				b.WriteString("\n")
+			case name == "td" || name == "th":
+				b.WriteString(" ")
+			case blocks[name]:
+				b.WriteString("\n")
+				if name == "pre" && tt == nethtml.StartTagToken {
+					pre++
+				}
+			}
+		case nethtml.EndTagToken:
+			if blocks[name] {
+				b.WriteString("\n")
+				if name == "pre" && pre > 0 {
+					pre--
+				}
+			}
+		}
+	}
+	text := lineSpaces.ReplaceAllString(b.String(), "\n")
+	return strings.TrimSpace(blankLines.ReplaceAllString(text, "\n\n"))
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	tagPattern   = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacePattern = regexp.MustCompile(`\s+`)
+)
+
+// PlainText strips HTML tags and collapses whitespace
+func PlainText(s string) string {
+	s = tagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(spacePattern.ReplaceAllString(s, " "))
+}
+
+// Highlight HTML-escapes text and wraps every occurrence of words in <mark>
+func Highlight(text string, words []string) string {
+	ranges := matchRanges(text, words)
+	var b strings.Builder
+	last := 0
+	for _, r := range ranges {
+		b.WriteString(html.EscapeString(text[last:r[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[r[0]:r[1]]))
+		b.WriteString("</mark>")
+		last = r[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// Snippet returns about width characters of the plain text of content
+// around the first match, highlighted
+func Snippet(content string, words []string, width int) string {
+	text := PlainText(content)
+	start := 0
+	if ranges := matchRanges(text, words); len(ranges) > 0 {
+		// 첫 일치 위치 앞쪽으로 width/4 글자 여유
+		before := []rune(text[:ranges[0][0]])
+		if n := len(before) - width/4; n > 0 {
+			start = len(string(before[:n]))
+		}
+	}
+
+	end := len(text)
+	if rest := text[start:]; utf8.RuneCountInString(rest) > width {
+		end = start + len(string([]rune(rest)[:width]))
+	}
+
+	s := Highlight(text[start:end], words)
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(text) {
+		s += "…"
+	}
+	return s
+}
+
+// matchRanges returns the non-overlapping byte ranges of words in text (case-insensitive)
+func matchRanges(text string, words []string) [][2]int {
+	var quoted []string
+	for _, w := range words {
+		if w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	re := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	var ranges [][2]int
+	for _, m := range re.FindAllStringIndex(text, -1) {
+		ranges = append(ranges, [2]int{m[0], m[1]})
+	}
+	return ranges
+}
@@ -0,0 +1,125 @@
+// Package search parses Gnuboard's sfl/stx/sop search parameters and
+// highlights matches in results.
+package search
+
+import (
+	"errors"
+	"strings"
+)
+
+// 그누보드와 동일하게 검색어는 앞의 두 단어만 사용
+const maxWords = 2
+
+// DefaultField is the sfl used when none is given
+const DefaultField = "wr_subject"
+
+// ErrInvalidField is returned for an sfl outside the allowlist
+var ErrInvalidField = errors.New("search: invalid sfl")
+
+// Match modes of a field
+const (
+	MatchContains = iota // INSTR(LOWER(col), LOWER(word))
+	MatchExact           // col = word        (mb_id,1 / wr_name,1)
+	MatchPrefix          // col LIKE 'word%'  (mb_id,0 / wr_name,0)
+)
+
+// Field is a column searched by a query
+type Field struct {
+	Column string
+	Match  int
+}
+
+// 허용하는 sfl 값 (그누보드 검색 select 와 동일)
+var fields = map[string][]Field{
+	"wr_subject":             {{Column: "wr_subject"}},
+	"wr_content":             {{Column: "wr_content"}},
+	"wr_subject||wr_content": {{Column: "wr_subject"}, {Column: "wr_content"}},
+	"mb_id,1":                {{Column: "mb_id", Match: MatchExact}},
+	"mb_id,0":                {{Column: "mb_id", Match: MatchPrefix}},
+	"wr_name,1":              {{Column: "wr_name", Match: MatchExact}},
+	"wr_name,0":              {{Column: "wr_name", Match: MatchPrefix}},
+}
+
+// FieldOptions lists the sfl values with their labels, in display order
+var FieldOptions = []struct{ Value, Label string }{
+	{"wr_subject", "제목"},
+	{"wr_content", "내용"},
+	{"wr_subject||wr_content", "제목+내용"},
+	{"mb_id,1", "회원아이디"},
+	{"mb_id,0", "회원아이디(코)"},
+	{"wr_name,1", "글쓴이"},
+	{"wr_name,0", "글쓴이(코)"},
+}
+
+// Query is a parsed search
+type Query struct {
+	SFL    string
+	Fields []Field
+	Words  []string
+	// And 이면 모든 단어, 아니면 하나라도 일치
+	And bool
+}
+
+// Parse validates sfl/stx/sop. sop is "and" (default) or "or".
+func Parse(sfl, stx, sop string) (Query, error) {
+	if sfl == "" {
+		sfl = DefaultField
+	}
+	f, ok := fields[sfl]
+	if !ok {
+		return Query{}, ErrInvalidField
+	}
+
+	words := strings.Fields(stx)
+	if len(words) > maxWords {
+		words = words[:maxWords]
+	}
+	return Query{SFL: sfl, Fields: f, Words: words, And: !strings.EqualFold(sop, "or")}, nil
+}
+
+// Empty reports whether there is nothing to search for
+func (q Query) Empty() bool {
+	return len(q.Words) == 0
+}
+
+// Columns returns the searched column names
+func (q Query) Columns() []string {
+	var cols []string
+	for _, f := range q.Fields {
+		cols = append(cols, f.Column)
+	}
+	return cols
+}
+
+// Match evaluates the query in Go. value returns a column's value.
+func (q Query) Match(value func(column string) string) bool {
+	if q.Empty() {
+		return true
+	}
+	for _, w := range q.Words {
+		matched := false
+		for _, f := range q.Fields {
+			if matchField(f, value(f.Column), w) {
+				matched = true
+				break
+			}
+		}
+		if q.And && !matched {
+			return false
+		}
+		if !q.And && matched {
+			return true
+		}
+	}
+	return q.And
+}
+
+func matchField(f Field, value, word string) bool {
+	switch f.Match {
+	case MatchExact:
+		return value == word
+	case MatchPrefix:
+		return strings.HasPrefix(value, word)
+	}
+	return strings.Contains(strings.ToLower(value), strings.ToLower(word))
+}
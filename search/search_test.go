@@ -0,0 +1,57 @@
+package search
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	q, err := Parse("", "  하나 둘 셋 ", "or")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.SFL != DefaultField || q.And || len(q.Words) != 2 {
+		t.Errorf("q = %+v", q)
+	}
+	if _, err := Parse("wr_password", "x", ""); err != ErrInvalidField {
+		t.Errorf("err = %v, want ErrInvalidField", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	row := map[string]string{"wr_subject": "Go 언어 소개", "wr_content": "fiber 서버", "mb_id": "admin"}
+	value := func(col string) string { return row[col] }
+
+	tests := []struct {
+		sfl, stx, sop string
+		want          bool
+	}{
+		{"wr_subject", "go", "", true},
+		{"wr_subject", "go fiber", "and", false},
+		{"wr_subject||wr_content", "go fiber", "and", true},
+		{"wr_subject", "go fiber", "or", true},
+		{"mb_id,1", "adm", "", false},
+		{"mb_id,0", "adm", "", true},
+		{"mb_id,1", "admin", "", true},
+	}
+	for _, tt := range tests {
+		q, _ := Parse(tt.sfl, tt.stx, tt.sop)
+		if got := q.Match(value); got != tt.want {
+			t.Errorf("%s %q %s = %v, want %v", tt.sfl, tt.stx, tt.sop, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := Highlight("<b>Go</b> go GO", []string{"go"})
+	want := "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; <mark>go</mark> <mark>GO</mark>"
+	if got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	content := "<p>가나다라마바사아자차카타파하 가나다라마바사아자차카타파하 검색어 뒤쪽 내용</p>"
+	got := Snippet(content, []string{"검색어"}, 20)
+	want := "…카타파하 <mark>검색어</mark> 뒤쪽 내용"
+	if got != want {
+		t.Errorf("Snippet = %q, want %q", got, want)
+	}
+}
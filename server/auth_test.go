@@ -0,0 +1,95 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func TestLoginHashFormats(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	// 픽스처의 회원마다 다른 해시 형식
+	logins := map[string]string{
+		"admin":   "admin1234", // PBKDF2
+		"user1":   "user1pass", // bcrypt
+		"user2":   "user2pass", // PASSWORD()
+		"olduser": "oldpass",   // OLD_PASSWORD()
+	}
+	for id, password := range logins {
+		var body routes.Envelope[routes.MemberInfo]
+		status := h.JSON(http.MethodPost, "/api/auth/login", routes.LoginRequest{ID: id, Password: password}, &body)
+		if status != 200 || body.Data.ID != id {
+			t.Errorf("%s: status = %d, data = %+v", id, status, body.Data)
+		}
+	}
+}
+
+func TestLoginErrors(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	tests := []struct {
+		req    routes.LoginRequest
+		status int
+		code   string
+	}{
+		{routes.LoginRequest{ID: "admin", Password: "wrong"}, 401, routes.ErrCodeInvalidCredentials},
+		{routes.LoginRequest{ID: "nobody", Password: "admin1234"}, 401, routes.ErrCodeInvalidCredentials},
+		{routes.LoginRequest{ID: "leaver", Password: "leaver1234"}, 403, routes.ErrCodeMemberLeft},
+		{routes.LoginRequest{ID: "blocked", Password: "blocked1234"}, 403, routes.ErrCodeMemberIntercepted},
+		{routes.LoginRequest{ID: "admin"}, 400, routes.ErrCodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		var body routes.ErrorEnvelope
+		if status := h.JSON(http.MethodPost, "/api/auth/login", tt.req, &body); status != tt.status || body.Error.Code != tt.code {
+			t.Errorf("%s: %d %q, want %d %q", tt.req.ID, status, body.Error.Code, tt.status, tt.code)
+		}
+	}
+
+	// 탈퇴 회원은 비밀번호가 틀리면 일반 로그인 실패와 같음
+	var body routes.ErrorEnvelope
+	h.JSON(http.MethodPost, "/api/auth/login", routes.LoginRequest{ID: "leaver", Password: "x"}, &body)
+	if body.Error.Code != routes.ErrCodeInvalidCredentials {
+		t.Errorf("leaver with wrong password: %q", body.Error.Code)
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	client := h.NewClient()
+
+	var errBody routes.ErrorEnvelope
+	if status := client.JSON(http.MethodGet, "/api/me", nil, &errBody); status != 401 || errBody.Error.Code != routes.ErrCodeUnauthorized {
+		t.Fatalf("before login: %d %q", status, errBody.Error.Code)
+	}
+
+	// 폼 로그인
+	form := url.Values{"mb_id": {"user1"}, "mb_password": {"user1pass"}}
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if resp := client.Do(req); resp.StatusCode != 200 {
+		t.Fatalf("login status = %d", resp.StatusCode)
+	}
+
+	var me routes.Envelope[routes.MemberInfo]
+	if status := client.JSON(http.MethodGet, "/api/me", nil, &me); status != 200 || me.Data.ID != "user1" || me.Data.Level != 2 {
+		t.Fatalf("me: %d %+v", status, me.Data)
+	}
+
+	// 다른 클라이언트는 로그인 상태가 아님
+	if status := h.JSON(http.MethodGet, "/api/me", nil, nil); status != 401 {
+		t.Errorf("anonymous me: %d", status)
+	}
+
+	if status := client.JSON(http.MethodPost, "/api/auth/logout", nil, nil); status != 204 {
+		t.Errorf("logout status = %d", status)
+	}
+	if status := client.JSON(http.MethodGet, "/api/me", nil, nil); status != 401 {
+		t.Errorf("after logout: %d", status)
+	}
+}
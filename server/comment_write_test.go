@@ -0,0 +1,188 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func createComment(t *testing.T, cl *servertest.Client, path string, req routes.CommentRequest) routes.CommentItem {
+	t.Helper()
+	var body routes.Envelope[routes.CommentItem]
+	if status := cl.JSON(http.MethodPost, path, req, &body); status != 201 {
+		t.Fatalf("POST %s: %d", path, status)
+	}
+	return body.Data
+}
+
+// free/1 의 댓글: 11 (1, ""), 12 (1, "A"), 14 (1, "AA"), 13 (2, "")
+func TestCreateComment(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+	ctx := context.Background()
+
+	created := createComment(t, user1, "/api/free/1/comments", routes.CommentRequest{Content: "세 번째 댓글"})
+	cm, err := h.Store.GetComment(ctx, "free", created.ID)
+	if err != nil || cm.Comment != 3 || cm.CommentReply != "" || cm.Name != "길동" {
+		t.Fatalf("stored = %+v, %v", cm, err)
+	}
+	post, _ := h.Store.GetPost(ctx, "free", 1)
+	if post.CommentCount != 5 || post.Last != cm.Datetime.Format("2006-01-02 15:04:05") {
+		t.Errorf("post wr_comment %d, wr_last %q", post.CommentCount, post.Last)
+	}
+	points := h.Store.Points("user1")
+	if len(points) != 1 || points[0].Point != 1 || points[0].RelAction != "댓글" || points[0].RelID != strconv.Itoa(created.ID) {
+		t.Errorf("points = %+v", points)
+	}
+
+	// 답글은 같은 wr_comment 에서 다음 글자
+	tests := []struct {
+		replyTo int
+		want    string
+	}{
+		{11, "B"},
+		{12, "AB"},
+		{13, "A"},
+	}
+	for _, tt := range tests {
+		reply := createComment(t, user1, "/api/free/1/comments", routes.CommentRequest{Content: "답글", ReplyTo: tt.replyTo})
+		cm, _ := h.Store.GetComment(ctx, "free", reply.ID)
+		if cm.CommentReply != tt.want || reply.ParentCommentID == nil || *reply.ParentCommentID != tt.replyTo {
+			t.Errorf("reply to %d: %q %+v, want %q", tt.replyTo, cm.CommentReply, reply, tt.want)
+		}
+	}
+
+	// 답글은 5단계까지
+	parent := 14
+	for depth := 3; depth <= 5; depth++ {
+		parent = createComment(t, user1, "/api/free/1/comments", routes.CommentRequest{Content: "깊은 답글", ReplyTo: parent}).ID
+	}
+	var errBody routes.ErrorEnvelope
+	status := user1.JSON(http.MethodPost, "/api/free/1/comments", routes.CommentRequest{Content: "6단계", ReplyTo: parent}, &errBody)
+	if status != 409 || errBody.Error.Code != routes.ErrCodeReplyLimit {
+		t.Errorf("depth 6: %d %+v", status, errBody)
+	}
+
+	// 다른 글의 댓글에는 답글 불가
+	if status := user1.JSON(http.MethodPost, "/api/free/2/comments", routes.CommentRequest{Content: "c", ReplyTo: 11}, nil); status != 404 {
+		t.Errorf("other post's comment: %d", status)
+	}
+}
+
+func TestCreateCommentDenied(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	guest := h.NewClient()
+
+	var errBody routes.ErrorEnvelope
+	status := guest.JSON(http.MethodPost, "/api/free/1/comments", routes.CommentRequest{Content: " "}, &errBody)
+	if status != 422 || errBody.Error.Code != routes.ErrCodeValidation {
+		t.Fatalf("invalid: %d %+v", status, errBody)
+	}
+	for _, field := range []string{"content", "name", "password"} {
+		if errBody.Error.Fields[field] == "" {
+			t.Errorf("fields[%s] missing: %+v", field, errBody.Error.Fields)
+		}
+	}
+
+	tests := []struct {
+		who  *servertest.Client
+		path string
+		want int
+		code string
+	}{
+		{h.Login("user1", "user1pass"), "/api/member/1/comments", 403, routes.ErrCodeForbidden}, // bo_comment_level 3
+		{h.Login("user2", "user2pass"), "/api/qna/1/comments", 403, routes.ErrCodeSecretPost},
+		{guest, "/api/free/999/comments", 404, routes.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		errBody = routes.ErrorEnvelope{}
+		req := routes.CommentRequest{Content: "댓글", Name: "손님", Password: "pw"}
+		if status := tt.who.JSON(http.MethodPost, tt.path, req, &errBody); status != tt.want || errBody.Error.Code != tt.code {
+			t.Errorf("POST %s: %d %q, want %d %q", tt.path, status, errBody.Error.Code, tt.want, tt.code)
+		}
+	}
+}
+
+func TestUpdateComment(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+	req := routes.CommentRequest{Content: "고친 댓글", Secret: true}
+
+	var errBody routes.ErrorEnvelope
+	tests := []struct {
+		who  *servertest.Client
+		path string
+		want int
+		code string
+	}{
+		{h.NewClient(), "/api/free/1/comments/13", 401, routes.ErrCodeUnauthorized},
+		{h.Login("user2", "user2pass"), "/api/free/1/comments/13", 403, routes.ErrCodeForbidden},
+		{user1, "/api/free/1/comments/12", 409, routes.ErrCodeHasReplies},
+		{user1, "/api/free/2/comments/13", 404, routes.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		errBody = routes.ErrorEnvelope{}
+		if status := tt.who.JSON(http.MethodPut, tt.path, req, &errBody); status != tt.want || errBody.Error.Code != tt.code {
+			t.Errorf("PUT %s: %d %q, want %d %q", tt.path, status, errBody.Error.Code, tt.want, tt.code)
+		}
+	}
+
+	var body routes.Envelope[routes.CommentItem]
+	if status := user1.JSON(http.MethodPut, "/api/free/1/comments/13", req, &body); status != 200 {
+		t.Fatalf("owner: %d", status)
+	}
+	if body.Data.Source != "고친 댓글" || !body.Data.IsSecret {
+		t.Errorf("updated = %+v", body.Data)
+	}
+	if status := h.Login("admin", "admin1234").JSON(http.MethodPut, "/api/free/1/comments/12", req, nil); status != 200 {
+		t.Errorf("admin: %d", status)
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	ctx := context.Background()
+	user1 := h.Login("user1", "user1pass")
+
+	var errBody routes.ErrorEnvelope
+	if status := user1.JSON(http.MethodDelete, "/api/free/1/comments/12", nil, &errBody); status != 409 || errBody.Error.Code != routes.ErrCodeHasReplies {
+		t.Errorf("has replies: %d %+v", status, errBody)
+	}
+	if status := h.Login("user2", "user2pass").JSON(http.MethodDelete, "/api/free/1/comments/14", nil, nil); status != 204 {
+		t.Errorf("user2: %d", status)
+	}
+	if status := user1.JSON(http.MethodDelete, "/api/free/1/comments/12", nil, nil); status != 204 {
+		t.Errorf("after reply removed: %d", status)
+	}
+	post, _ := h.Store.GetPost(ctx, "free", 1)
+	if post.CommentCount != 2 || post.Last != "2025-01-01 11:00:00" {
+		t.Errorf("post wr_comment %d, wr_last %q", post.CommentCount, post.Last)
+	}
+
+	// 손님 댓글은 비밀번호로 삭제, 포인트 없음
+	guest := h.NewClient()
+	cm := createComment(t, guest, "/api/free/1/comments", routes.CommentRequest{Content: "손님 댓글", Name: "나그네", Password: "pw1234"})
+	path := "/api/free/1/comments/" + strconv.Itoa(cm.ID)
+	if status := guest.JSON(http.MethodDelete, path, routes.DeleteRequest{Password: "wrong"}, &errBody); status != 403 || errBody.Error.Code != routes.ErrCodeInvalidPassword {
+		t.Errorf("wrong password: %d %+v", status, errBody)
+	}
+	if status := guest.JSON(http.MethodDelete, path, routes.DeleteRequest{Password: "pw1234"}, nil); status != 204 {
+		t.Errorf("guest delete: %d", status)
+	}
+	if n, _ := h.Store.CountComments(ctx, "free", 1); n != 2 {
+		t.Errorf("comments = %d, want 2", n)
+	}
+
+	// 댓글 포인트는 삭제하면 회수
+	cm = createComment(t, user1, "/api/free/1/comments", routes.CommentRequest{Content: "c"})
+	if status := user1.JSON(http.MethodDelete, "/api/free/1/comments/"+strconv.Itoa(cm.ID), nil, nil); status != 204 {
+		t.Errorf("owner delete: %d", status)
+	}
+	if points := h.Store.Points("user1"); len(points) != 0 {
+		t.Errorf("points = %+v", points)
+	}
+}
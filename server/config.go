@@ -0,0 +1,369 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"fibergo/board"
+	"fibergo/hits"
+	"fibergo/jwt"
+	"fibergo/phpsession"
+	"fibergo/repository"
+	"fibergo/routes"
+	"fibergo/sanitize"
+	"fibergo/thumbnail"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Config holds everything needed to build the application
+type Config struct {
+	DB     *sql.DB
+	Boards *board.Registry
+
+	// 비어있으면 DB 로 MySQL 저장소 생성
+	Posts    repository.PostRepository
+	Comments repository.CommentRepository
+	Members  repository.MemberRepository
+	Writer   repository.PostWriter
+	// 댓글 쓰기
+	CommentWriter repository.CommentWriter
+	// 추천/비추천 (g5_board_good)
+	Votes repository.VoteRepository
+	// 첨부파일 (g5_board_file) 과 그누보드 data 디렉터리 (기본값 ./data)
+	Files   repository.FileRepository
+	DataDir string
+	// 첨부파일 올리기
+	FileWriter repository.FileWriter
+	// 글읽기, 다운로드 포인트와 /api/me/points (g5_point)
+	Points repository.PointRepository
+	// 요청 본문 최대 크기 (기본 4MB). 첨부파일은 bo_upload_size 로 제한
+	BodyLimit int
+	// 본문 HTML 허용 목록 (기본 sanitize.DefaultAllowlist)
+	HTMLPolicy *sanitize.Policy
+	// 갤러리 게시판 크기 (bo_gallery_width x bo_gallery_height) 외에 허용하는 썸네일 크기
+	ThumbnailSizes []thumbnail.Size
+
+	// 설정하면 /api/latest 가 g5_board_new 를 사용 (없으면 게시판별 조회 후 병합)
+	BoardNew repository.BoardNewRepository
+	// /api/latest, /api/search 의 게시판별 동시 조회 제한 시간 (기본 3s)
+	FanOutTimeout time.Duration
+
+	// 조회수: 같은 방문자 (회원, 손님은 IP) 는 HitWindow (기본 24h) 안에 글마다 한 번만 세고,
+	// HitFlushInterval 마다 모아서 반영 (0 이면 요청마다 바로 반영). Hits 가 비어있으면 프로세스 메모리에 보관
+	Hits             hits.Store
+	HitWindow        time.Duration
+	HitFlushInterval time.Duration
+	// 프로세스 메모리에 보관할 때 최대 방문 기록 수 (넘으면 가장 오래된 것부터 잊음)
+	HitMemoryLimit int
+
+	// 목록, 게시판 글 수, 글, 댓글 목록 캐시의 최대 항목 수 (0 이면 사용 안 함) 와 유지 시간 (기본 10s).
+	// 이 서버에서 쓰면 그 게시판 항목을 바로 버리고, PHP 쪽에서 바뀐 것은 유지 시간이 지나면 반영
+	CacheSize int
+	CacheTTL  time.Duration
+
+	// 템플릿, 정적 파일 경로 (기본값 ./templates, ./static)
+	TemplatesDir string
+	StaticDir    string
+
+	// 개발 환경에서 템플릿 자동 리로드
+	TemplateReload bool
+
+	// limit, per_page 상한 (기본 100)
+	MaxPageSize int
+
+	// 검색 한 구간의 wr_num 범위 (그누보드 cf_search_part, 기본 10000)
+	SearchPart int
+
+	// 프록시 뒤에서 클라이언트 IP 를 읽을 헤더 (예: X-Forwarded-For). 손님 조회수, wr_ip, bf_ip, ss_mb_key 가
+	// 모두 이 IP 를 씀. EnableTrustedProxyCheck 이면 TrustedProxies (IP 또는 CIDR) 에서 온 요청만 헤더를 믿음
+	ProxyHeader             string
+	EnableTrustedProxyCheck bool
+	TrustedProxies          []string
+
+	// 로그인 세션 유지 시간 (기본 24h), HTTPS 전용 쿠키 여부
+	SessionLifetime time.Duration
+	SecureCookie    bool
+
+	// 설정하면 PHP 그누보드의 로그인 세션 (PHPSESSID) 도 인식
+	PHPSession *routes.PHPSession
+
+	// 최고관리자 mb_id (그누보드 cf_admin). 모든 게시판의 비밀글을 볼 수 있음
+	SuperAdmin string
+
+	// JWT 서명 키 (없으면 /api/auth/token 사용 안 함), 폐기 목록, 유효 시간
+	TokenKeys       *jwt.KeySet
+	Denylist        repository.TokenDenylist
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// 폐기 목록에서 만료된 항목을 지우는 주기 (0 이면 지우지 않음)
+	TokenPurgeInterval time.Duration
+}
+
+// ConfigFromEnv returns the configuration shared by both entrypoints
+func ConfigFromEnv(db *sql.DB, boards *board.Registry) Config {
+	cfg := Config{
+		DB:          db,
+		Boards:      boards,
+		MaxPageSize: envInt("MAX_PAGE_SIZE", 100),
+		BodyLimit:   envInt("BODY_LIMIT", 0),
+		SearchPart:  envInt("SEARCH_PART", 10000),
+		CacheSize:   envInt("CACHE_SIZE", 1000),
+		DataDir:     os.Getenv("G5_DATA_PATH"),
+	}
+	if spec := os.Getenv("HTML_ALLOWED_TAGS"); spec != "" {
+		if p, err := sanitize.NewPolicy(spec); err != nil {
+			log.Printf("HTML_ALLOWED_TAGS 무시: %v", err)
+		} else {
+			cfg.HTMLPolicy = p
+		}
+	}
+	if sizes, err := thumbnail.ParseSizes(os.Getenv("THUMBNAIL_SIZES")); err != nil {
+		log.Printf("THUMBNAIL_SIZES 무시: %v", err)
+	} else {
+		cfg.ThumbnailSizes = sizes
+	}
+	if os.Getenv("LATEST_SOURCE") == "board_new" {
+		cfg.BoardNew = repository.NewMySQL(db)
+	}
+	if d, err := time.ParseDuration(os.Getenv("FANOUT_TIMEOUT")); err == nil {
+		cfg.FanOutTimeout = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("CACHE_TTL")); err == nil {
+		cfg.CacheTTL = d
+	}
+	cfg.Hits = hitStoreFromEnv()
+	if d, err := time.ParseDuration(os.Getenv("HIT_WINDOW")); err == nil {
+		cfg.HitWindow = d
+	}
+	cfg.HitFlushInterval = 10 * time.Second
+	if d, err := time.ParseDuration(os.Getenv("HIT_FLUSH_INTERVAL")); err == nil {
+		cfg.HitFlushInterval = d
+	}
+	cfg.HitMemoryLimit = envInt("HIT_MEMORY_LIMIT", hits.DefaultMemoryLimit)
+	cfg.ProxyHeader = os.Getenv("PROXY_HEADER")
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		for _, p := range strings.Split(proxies, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				cfg.TrustedProxies = append(cfg.TrustedProxies, p)
+			}
+		}
+		cfg.EnableTrustedProxyCheck = true
+	}
+	if d, err := time.ParseDuration(os.Getenv("SESSION_LIFETIME")); err == nil {
+		cfg.SessionLifetime = d
+	}
+	cfg.SecureCookie = os.Getenv("SESSION_SECURE") == "true"
+	cfg.PHPSession = phpSessionFromEnv(db)
+	cfg.SuperAdmin = superAdminFromEnv(db)
+	if spec := os.Getenv("JWT_KEYS"); spec != "" {
+		keys, err := jwt.ParseKeySet(spec, os.Getenv("JWT_SIGNING_KID"))
+		if err != nil {
+			log.Printf("JWT 설정 무시: %v", err)
+		} else {
+			cfg.TokenKeys = keys
+		}
+	}
+	if d, err := time.ParseDuration(os.Getenv("JWT_ACCESS_TTL")); err == nil {
+		cfg.AccessTokenTTL = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("JWT_REFRESH_TTL")); err == nil {
+		cfg.RefreshTokenTTL = d
+	}
+	cfg.TokenPurgeInterval = time.Hour
+	if d, err := time.ParseDuration(os.Getenv("JWT_PURGE_INTERVAL")); err == nil {
+		cfg.TokenPurgeInterval = d
+	}
+	return cfg
+}
+
+// hitStoreFromEnv returns the Redis store of HIT_STORE (redis://...), or
+// nil for the in-process store
+func hitStoreFromEnv() hits.Store {
+	rawURL := os.Getenv("HIT_STORE")
+	if rawURL == "" {
+		return nil
+	}
+	store, err := hits.NewRedisStore(rawURL)
+	if err != nil {
+		log.Printf("HIT_STORE 무시: %v", err)
+		return nil
+	}
+	return store
+}
+
+// phpSessionFromEnv returns the PHP session settings, or nil if no
+// PHP_SESSION_PATH, PHP_SESSION_REDIS or PHP_SESSION_QUERY is set
+func phpSessionFromEnv(db *sql.DB) *routes.PHPSession {
+	var store phpsession.Store
+	switch {
+	case os.Getenv("PHP_SESSION_PATH") != "":
+		// 그누보드 common.php 의 session.gc_maxlifetime 기본값 10800
+		store = phpsession.FileStore{
+			Dir:         os.Getenv("PHP_SESSION_PATH"),
+			MaxLifetime: time.Duration(envInt("PHP_SESSION_LIFETIME", 10800)) * time.Second,
+		}
+	case os.Getenv("PHP_SESSION_REDIS") != "":
+		redis, err := phpsession.NewRedisStore(os.Getenv("PHP_SESSION_REDIS"))
+		if err != nil {
+			log.Printf("PHP 세션 설정 무시: %v", err)
+			return nil
+		}
+		store = redis
+	case os.Getenv("PHP_SESSION_QUERY") != "":
+		store = phpsession.SQLStore{DB: db, Query: os.Getenv("PHP_SESSION_QUERY")}
+	default:
+		return nil
+	}
+	return &routes.PHPSession{
+		Reader:   phpsession.Reader{Store: store, Handler: os.Getenv("PHP_SESSION_SERIALIZER")},
+		Cookie:   os.Getenv("PHP_SESSION_NAME"),
+		CheckKey: os.Getenv("PHP_SESSION_CHECK_KEY") != "false",
+	}
+}
+
+// superAdminFromEnv returns SUPER_ADMIN, or cf_admin from g5_config
+func superAdminFromEnv(db *sql.DB) string {
+	if id := os.Getenv("SUPER_ADMIN"); id != "" || db == nil {
+		return id
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	id, err := repository.NewMySQL(db).SuperAdmin(ctx)
+	if err != nil {
+		log.Printf("cf_admin 조회 실패: %v", err)
+	}
+	return id
+}
+
+func envInt(name string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return n
+	}
+	return def
+}
+
+func (cfg *Config) setDefaults() {
+	if cfg.Posts == nil || cfg.Comments == nil || cfg.Members == nil || cfg.Writer == nil || cfg.CommentWriter == nil || cfg.Votes == nil || cfg.Files == nil || cfg.FileWriter == nil || cfg.Points == nil || cfg.Denylist == nil {
+		mysql := repository.NewMySQL(cfg.DB)
+		if cfg.Posts == nil {
+			cfg.Posts = mysql
+		}
+		if cfg.Comments == nil {
+			cfg.Comments = mysql
+		}
+		if cfg.Members == nil {
+			cfg.Members = mysql
+		}
+		if cfg.Writer == nil {
+			cfg.Writer = mysql
+		}
+		if cfg.CommentWriter == nil {
+			cfg.CommentWriter = mysql
+		}
+		if cfg.Votes == nil {
+			cfg.Votes = mysql
+		}
+		if cfg.Files == nil {
+			cfg.Files = mysql
+		}
+		if cfg.FileWriter == nil {
+			cfg.FileWriter = mysql
+		}
+		if cfg.Points == nil {
+			cfg.Points = mysql
+		}
+		if cfg.Denylist == nil {
+			cfg.Denylist = mysql
+		}
+	}
+	if cfg.TemplatesDir == "" {
+		cfg.TemplatesDir = "./templates"
+	}
+	if cfg.DataDir == "" {
+		cfg.DataDir = "./data"
+	}
+	if cfg.BodyLimit <= 0 {
+		cfg.BodyLimit = fiber.DefaultBodyLimit
+	}
+	if cfg.MaxPageSize <= 0 {
+		cfg.MaxPageSize = 100
+	}
+	if cfg.FanOutTimeout <= 0 {
+		cfg.FanOutTimeout = 3 * time.Second
+	}
+	if cfg.Hits == nil {
+		cfg.Hits = hits.NewMemoryStore(cfg.HitMemoryLimit)
+	}
+	if cfg.HitWindow <= 0 {
+		cfg.HitWindow = 24 * time.Hour
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = 10 * time.Second
+	}
+	if cfg.SessionLifetime <= 0 {
+		cfg.SessionLifetime = 24 * time.Hour
+	}
+	if cfg.SearchPart <= 0 {
+		cfg.SearchPart = 10000
+	}
+	if cfg.StaticDir == "" {
+		cfg.StaticDir = "./static"
+	}
+}
+
+// DSNFromEnv returns DATABASE_URL if set, otherwise builds a DSN from DB_* variables
+func DSNFromEnv() string {
+	if dsn := os.Getenv("DATABASE_URL"); dsn != "" {
+		return dsn
+	}
+	return os.Getenv("DB_USER") + ":" + os.Getenv("DB_PASSWORD") +
+		"@tcp(" + os.Getenv("DB_HOST") + ":" + os.Getenv("DB_PORT") + ")/" + os.Getenv("DB_NAME") +
+		"?charset=utf8mb4&parseTime=True"
+}
+
+// OpenDB opens the MySQL connection pool and checks that it is reachable
+func OpenDB(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// DB 커넥션 풀 설정
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(25)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// LoadBoards loads the board registry from g5_board, restricted by ALLOWED_BOARDS
+// and refreshed every BOARD_REFRESH_INTERVAL (default 5m).
+func LoadBoards(db *sql.DB) (*board.Registry, error) {
+	registry := board.NewRegistry(board.NewMySQLSource(db), board.ParseAllowed(os.Getenv("ALLOWED_BOARDS")))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := registry.Load(ctx); err != nil {
+		return nil, err
+	}
+
+	refresh := 5 * time.Minute
+	if v := os.Getenv("BOARD_REFRESH_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			refresh = d
+		}
+	}
+	registry.Start(refresh)
+	return registry, nil
+}
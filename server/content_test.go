@@ -0,0 +1,143 @@
+package server_test
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func TestContentSanitized(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	const payload = `<p onclick="steal()">안녕<script>alert(1)</script></p><a href="javascript:alert(1)">링크</a><img src=x onerror=alert(2)>`
+	htmlPost := createPost(t, user1, "free", routes.PostRequest{Subject: "HTML", Content: payload, Format: "html1"})
+	textPost := createPost(t, user1, "free", routes.PostRequest{Subject: "텍스트", Content: "<b>굵게</b>\nhttps://example.com"})
+
+	tests := []struct {
+		post               routes.PostDetail
+		format, html, text string
+	}{
+		{htmlPost, "html1", `<p>안녕</p><a>링크</a><img src="x">`, "안녕\n링크"},
+		{textPost, "text",
+			`&lt;b&gt;굵게&lt;/b&gt;<br>` + "\n" + `<a href="https://example.com" target="_blank" rel="nofollow noopener noreferrer">https://example.com</a>`,
+			"<b>굵게</b>\nhttps://example.com"},
+	}
+	for _, tt := range tests {
+		var body routes.Envelope[routes.PostDetail]
+		if status := h.GetJSON("/api/v1/boards/free/posts/"+strconv.Itoa(tt.post.ID), &body); status != 200 {
+			t.Fatalf("detail %d: %d", tt.post.ID, status)
+		}
+		got := body.Data
+		if got.Source != "" || got.Format != tt.format || got.ContentHTML != tt.html || got.ContentText != tt.text {
+			t.Errorf("post %d = source %q, format %q, html %q, text %q", tt.post.ID, got.Source, got.Format, got.ContentHTML, got.ContentText)
+		}
+	}
+
+	// 글쓰기 응답에도 같은 필드
+	if htmlPost.ContentHTML != tests[0].html {
+		t.Errorf("create response content_html = %q", htmlPost.ContentHTML)
+	}
+
+	comment := createComment(t, user1, "/api/free/"+strconv.Itoa(htmlPost.ID)+"/comments", routes.CommentRequest{Content: "<script>x()</script>\n둘째 줄"})
+	if comment.ContentHTML != "&lt;script&gt;x()&lt;/script&gt;<br>\n둘째 줄" {
+		t.Errorf("comment content_html = %q", comment.ContentHTML)
+	}
+
+	status, page := h.GetHTML("/free/" + strconv.Itoa(htmlPost.ID))
+	if status != 200 {
+		t.Fatalf("SSR: %d", status)
+	}
+	// 본문 부분만 (레이아웃에는 원래 script 가 있음)
+	_, view, _ := strings.Cut(page, `<div class="post-content">`)
+	view, _, _ = strings.Cut(view, "</div>")
+	for _, bad := range []string{"<script>", "onclick", "onerror", "javascript:"} {
+		if strings.Contains(view, bad) {
+			t.Errorf("SSR view contains %q", bad)
+		}
+	}
+	for _, want := range []string{`<p>안녕</p>`, "&lt;script&gt;x()&lt;/script&gt;<br>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("SSR view missing %q", want)
+		}
+	}
+}
+
+// 레거시 상세와 댓글 목록의 내용도 정리한 HTML (board.js, view.html 이 innerHTML 로 넣음)
+func TestLegacyContentSanitized(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	post := createPost(t, user1, "free", routes.PostRequest{
+		Subject: "HTML", Format: "html1",
+		Content: `<p>본문<script>alert(1)</script></p><img src=x onerror=alert(2)>`,
+	})
+	path := "/api/free/" + strconv.Itoa(post.ID)
+	createComment(t, user1, path+"/comments", routes.CommentRequest{Content: `<img src=x onerror=alert(3)><script>x()</script>`})
+
+	var detail routes.LegacyPost
+	if status := h.GetJSON(path, &detail); status != 200 {
+		t.Fatalf("legacy detail: %d", status)
+	}
+	var comments routes.LegacyCommentList
+	if status := h.GetJSON(path+"/comments", &comments); status != 200 || len(comments.Comments) != 1 {
+		t.Fatalf("legacy comments: %d %+v", status, comments)
+	}
+	for name, got := range map[string]string{"post": detail.Content, "comment": comments.Comments[0].Content} {
+		// 태그로 남은 것만 위험 (이스케이프한 글자는 괜찮음)
+		for _, bad := range []string{"<script", "<img src=x", `" onerror`} {
+			if strings.Contains(got, bad) {
+				t.Errorf("%s 내용 contains %q: %q", name, bad, got)
+			}
+		}
+	}
+	if detail.Content != `<p>본문</p><img src="x">` {
+		t.Errorf("post 내용 = %q", detail.Content)
+	}
+	if want := "&lt;img src=x onerror=alert(3)&gt;&lt;script&gt;x()&lt;/script&gt;"; comments.Comments[0].Content != want {
+		t.Errorf("comment 내용 = %q, want %q", comments.Comments[0].Content, want)
+	}
+}
+
+// 원문 (source) 은 글쓴이와 관리자에게만, 다른 독자의 응답에는 원문이 없음
+func TestContentSource(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	const payload = `<img src=x onerror=alert(1)>`
+	post := createPost(t, user1, "free", routes.PostRequest{Subject: "원문", Content: payload, Format: "html1"})
+	path := "/api/v1/boards/free/posts/" + strconv.Itoa(post.ID)
+	createComment(t, user1, "/api/free/"+strconv.Itoa(post.ID)+"/comments", routes.CommentRequest{Content: payload})
+
+	tests := []struct {
+		who    string
+		client *servertest.Client
+		want   string
+	}{
+		{"guest", h.NewClient(), ""},
+		{"user2", h.Login("user2", "user2pass"), ""},
+		{"author", user1, payload},
+		{"admin", h.Login("admin", "admin1234"), payload},
+	}
+	for _, tt := range tests {
+		var detail routes.Envelope[routes.PostDetail]
+		if status := tt.client.JSON(http.MethodGet, path, nil, &detail); status != 200 || detail.Data.Source != tt.want {
+			t.Errorf("%s post: %d source %q", tt.who, status, detail.Data.Source)
+		}
+		var comments routes.Envelope[[]routes.CommentItem]
+		if status := tt.client.JSON(http.MethodGet, path+"/comments", nil, &comments); status != 200 || len(comments.Data) != 1 || comments.Data[0].Source != tt.want {
+			t.Errorf("%s comments: %d %+v", tt.who, status, comments.Data)
+		}
+	}
+
+	resp := h.Get(path, "application/json")
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); strings.Contains(string(body), "onerror") {
+		t.Errorf("guest response has the raw payload: %s", body)
+	}
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"net/url"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+type feedRef struct {
+	Board string
+	ID    int
+}
+
+func feedRefs(t *testing.T, h *servertest.Harness, path string) (routes.Envelope[[]routes.FeedItem], []feedRef) {
+	t.Helper()
+	var body routes.Envelope[[]routes.FeedItem]
+	if status := h.GetJSON(path, &body); status != 200 {
+		t.Fatalf("%s: status = %d", path, status)
+	}
+	var refs []feedRef
+	for _, item := range body.Data {
+		refs = append(refs, feedRef{item.BoardID, item.ID})
+	}
+	return body, refs
+}
+
+func equalRefs(a, b []feedRef) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLatestMergesBoards(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	body, got := feedRefs(t, h, "/api/latest")
+	want := []feedRef{{"free", 5}, {"free", 4}, {"free", 3}, {"free", 2}, {"free", 1}, {"notice", 1}, {"qna", 3}, {"qna", 2}, {"qna", 1}}
+	if !equalRefs(got, want) {
+		t.Errorf("latest = %v, want %v", got, want)
+	}
+	if body.Meta.HasMore || body.Data[5].BoardName != "공지사항" {
+		t.Errorf("meta = %+v, last = %+v", body.Meta, body.Data[5])
+	}
+
+	body, got = feedRefs(t, h, "/api/latest?per_page=2&page=2")
+	if !equalRefs(got, []feedRef{{"free", 3}, {"free", 2}}) || !body.Meta.HasMore {
+		t.Errorf("page 2 = %v, has_more = %v", got, body.Meta.HasMore)
+	}
+
+	_, got = feedRefs(t, h, "/api/latest?boards=notice")
+	if !equalRefs(got, []feedRef{{"notice", 1}}) {
+		t.Errorf("boards=notice = %v", got)
+	}
+}
+
+func TestLatestFromBoardNew(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	routes.InitFeed(h.Store, 0)
+	t.Cleanup(func() { routes.InitFeed(nil, 0) })
+
+	body, got := feedRefs(t, h, "/api/latest?per_page=5")
+	want := []feedRef{{"free", 5}, {"free", 4}, {"free", 3}, {"free", 2}, {"free", 1}}
+	if !equalRefs(got, want) || !body.Meta.HasMore {
+		t.Errorf("latest = %v (has_more %v), want %v", got, body.Meta.HasMore, want)
+	}
+}
+
+func TestCrossSearch(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	body, got := feedRefs(t, h, "/api/search?stx="+url.QueryEscape("첫"))
+	if !equalRefs(got, []feedRef{{"free", 4}, {"free", 1}}) {
+		t.Errorf("search = %v", got)
+	}
+	if body.Data[1].SubjectHTML != "<mark>첫</mark> 번째 글" || body.Meta.Search == nil {
+		t.Errorf("subject_html = %q, meta = %+v", body.Data[1].SubjectHTML, body.Meta)
+	}
+}
+
+func TestFeedErrors(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	tests := []struct {
+		path, code string
+	}{
+		{"/api/latest?page=100&per_page=100", routes.ErrCodeInvalidPage},
+		{"/api/search", routes.ErrCodeInvalidSearch},
+		{"/api/search?sfl=nope&stx=a", routes.ErrCodeInvalidSearch},
+	}
+	for _, tt := range tests {
+		var body routes.ErrorEnvelope
+		if status := h.GetJSON(tt.path, &body); status != 400 || body.Error.Code != tt.code {
+			t.Errorf("%s: status = %d, code = %q", tt.path, status, body.Error.Code)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package server_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+const reportContent = "0123456789abcdefghijklmnop"
+
+// free/2 의 첨부파일을 data/file/free 에 만듦 (photo.jpg 는 없는 파일)
+func writeAttachment(t *testing.T, h *servertest.Harness) {
+	t.Helper()
+	dir := filepath.Join(h.DataDir, "file", "free")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "8f14e45f_report.pdf"), []byte(reportContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func download(t *testing.T, cl *servertest.Client, path, rangeHeader string) (*http.Response, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	resp := cl.Do(req)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(body)
+}
+
+func TestPostAttachments(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.Envelope[routes.PostDetail]
+	h.GetJSON("/api/v1/boards/free/posts/2", &body)
+	files := body.Data.Files
+	if len(files) != 2 {
+		t.Fatalf("files = %+v", files)
+	}
+	want := routes.Attachment{No: 1, Name: "photo.jpg", Description: "풍경", Size: 4, Width: 640, Height: 480, IsImage: true, URL: "/api/free/2/files/1"}
+	if files[0].Name != "보고서 2025.pdf" || files[0].Downloads != 3 || files[1] != want {
+		t.Errorf("files = %+v", files)
+	}
+
+	h.GetJSON("/api/v1/boards/free/posts/1", &body)
+	if body.Data.Files == nil || len(body.Data.Files) != 0 {
+		t.Errorf("no files = %#v", body.Data.Files)
+	}
+
+	status, html := h.GetHTML("/free/2")
+	if status != 200 || !strings.Contains(html, `href="/api/free/2/files/0"`) {
+		t.Errorf("SSR: %d", status)
+	}
+}
+
+func TestFileDownload(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	writeAttachment(t, h)
+	user1 := h.Login("user1", "user1pass")
+
+	resp, body := download(t, user1, "/api/free/2/files/0", "")
+	if resp.StatusCode != 200 || body != reportContent {
+		t.Fatalf("download: %d %q", resp.StatusCode, body)
+	}
+	wantDisposition := `attachment; filename="___ 2025.pdf"; filename*=UTF-8''%EB%B3%B4%EA%B3%A0%EC%84%9C%202025.pdf`
+	if got := resp.Header.Get("Content-Disposition"); got != wantDisposition {
+		t.Errorf("Content-Disposition = %s", got)
+	}
+	if resp.Header.Get("Content-Type") != "application/octet-stream" || resp.Header.Get("Accept-Ranges") != "bytes" {
+		t.Errorf("headers = %v", resp.Header)
+	}
+
+	// 이어받기
+	tests := []struct {
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"bytes=10-", 206, reportContent[10:], "bytes 10-25/26"},
+		{"bytes=0-3", 206, "0123", "bytes 0-3/26"},
+		{"bytes=-4", 206, "mnop", "bytes 22-25/26"},
+		{"bytes=20-100", 206, reportContent[20:], "bytes 20-25/26"},
+		{"bytes=0-1,4-5", 200, reportContent, ""},
+		{"bytes=30-", 416, "", "bytes */26"},
+	}
+	for _, tt := range tests {
+		resp, body := download(t, user1, "/api/free/2/files/0", tt.rangeHeader)
+		if resp.StatusCode != tt.status || resp.Header.Get("Content-Range") != tt.contentRange {
+			t.Errorf("%s: %d %q", tt.rangeHeader, resp.StatusCode, resp.Header.Get("Content-Range"))
+		}
+		if tt.status != 416 && body != tt.body {
+			t.Errorf("%s: body %q", tt.rangeHeader, body)
+		}
+	}
+
+	// 세션마다 한 번만 센다
+	user2 := h.Login("user2", "user2pass")
+	download(t, user2, "/api/free/2/files/0", "")
+	download(t, user2, "/api/free/2/files/0", "bytes=10-")
+	var detail routes.Envelope[routes.PostDetail]
+	h.GetJSON("/api/v1/boards/free/posts/2", &detail)
+	if detail.Data.Files[0].Downloads != 5 {
+		t.Errorf("downloads = %d, want 5", detail.Data.Files[0].Downloads)
+	}
+}
+
+func TestFileDownloadDenied(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	writeAttachment(t, h)
+	user1 := h.Login("user1", "user1pass")
+
+	tests := []struct {
+		who  *servertest.Client
+		path string
+		want int
+		code string
+	}{
+		{h.NewClient(), "/api/free/2/files/0", 401, routes.ErrCodeUnauthorized}, // bo_download_level 2
+		{user1, "/api/free/2/files/1", 404, routes.ErrCodeNotFound},             // 파일 없음
+		{user1, "/api/free/2/files/5", 404, routes.ErrCodeNotFound},
+		{user1, "/api/free/1/files/0", 404, routes.ErrCodeNotFound},
+		{user1, "/api/free/2/files/-1", 400, routes.ErrCodeInvalidID},
+	}
+	for _, tt := range tests {
+		var errBody routes.ErrorEnvelope
+		if status := tt.who.JSON(http.MethodGet, tt.path, nil, &errBody); status != tt.want || errBody.Error.Code != tt.code {
+			t.Errorf("GET %s: %d %q, want %d %q", tt.path, status, errBody.Error.Code, tt.want, tt.code)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"os"
+	"time"
+
+	"fibergo/repository/cache"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// handleIndex reports server, database and cache status. postCache is nil
+// if CACHE_SIZE is 0.
+func handleIndex(cfg Config, postCache *cache.Store) fiber.Handler {
+	startTime := time.Now()
+
+	return func(c *fiber.Ctx) error {
+		// DB 상태 확인
+		dbStatus := "연결 성공"
+		if cfg.DB == nil {
+			dbStatus = "사용 안 함"
+		} else if err := cfg.DB.Ping(); err != nil {
+			dbStatus = "연결 실패: " + err.Error()
+		}
+
+		boardSubjects := map[string]string{}
+		for _, b := range cfg.Boards.List() {
+			boardSubjects[b.Table] = b.Subject
+		}
+
+		// 서버 정보 수집
+		serverInfo := map[string]interface{}{
+			"status":    "정상 작동 중",
+			"version":   Version,
+			"startTime": startTime.Format("2006-01-02 15:04:05"),
+			"database": map[string]string{
+				"status": dbStatus,
+				"host":   os.Getenv("DB_HOST"),
+				"name":   os.Getenv("DB_NAME"),
+			},
+			"boards": boardSubjects,
+			"endpoints": map[string]string{
+				"boards":   "/api/:type",
+				"post":     "/api/:type/:id",
+				"comments": "/api/:type/:id/comments",
+				"v1":       "/api/v1/boards",
+				"latest":   "/api/latest",
+				"search":   "/api/search",
+				"login":    "/api/auth/login",
+				"me":       "/api/me",
+				"openapi":  "/api/openapi.json",
+				"docs":     "/static/docs/index.html",
+			},
+		}
+
+		// 캐시 적중률 확인용
+		if postCache != nil {
+			serverInfo["cache"] = postCache.Stats()
+		}
+
+		return c.JSON(fiber.Map{
+			"message": "Board API Server",
+			"server":  serverInfo,
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import "github.com/gofiber/fiber/v2"
+
+// corsMiddleware sets CORS and security headers. 다른 출처에서는 쿠키 없이
+// Authorization: Bearer 토큰으로 호출하므로 Allow-Credentials 는 보내지 않음
+func corsMiddleware(c *fiber.Ctx) error {
+	c.Set("Access-Control-Allow-Origin", "*")
+	c.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	c.Set("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
+
+	// 보안 헤더 추가
+	c.Set("X-Content-Type-Options", "nosniff")
+	c.Set("X-Frame-Options", "DENY")
+	c.Set("X-XSS-Protection", "1; mode=block")
+
+	if c.Method() == "OPTIONS" {
+		return c.SendStatus(204)
+	}
+	return c.Next()
+}
@@ -0,0 +1,280 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"fibergo/openapi"
+	"fibergo/routes"
+	"fibergo/server"
+	"fibergo/server/servertest"
+)
+
+// specExample is an example request of TestResponsesMatchSpec
+type specExample struct {
+	path string
+	as   string      // 로그인할 회원 (specLogins), 비어 있으면 손님
+	body interface{} // JSON 본문
+	// build makes a request that needs earlier state (토큰, 첨부파일); path 와 body 는 무시
+	build func(t *testing.T, h *servertest.Harness) *http.Request
+}
+
+// 예시에서 로그인하는 픽스처 회원의 비밀번호
+var specLogins = map[string]string{"user1": "user1pass", "user2": "user2pass", "admin": "admin1234", "olduser": "oldpass"}
+
+// get lists GET examples sent by a guest
+func get(paths ...string) []specExample {
+	list := make([]specExample, 0, len(paths))
+	for _, p := range paths {
+		list = append(list, specExample{path: p})
+	}
+	return list
+}
+
+// 라우트별 예시 요청. 새 라우트를 추가하면 여기에도 추가해야 테스트가 통과하고,
+// 라우트마다 적어도 하나는 2xx 응답이어야 함 (성공 응답의 모양도 문서와 비교)
+var specExamples = map[string][]specExample{
+	"GET /v1/boards":                 get("/api/v1/boards"),
+	"GET /v1/boards/:type":           get("/api/v1/boards/free", "/api/v1/boards/nope"),
+	"GET /v1/boards/:type/posts":     get("/api/v1/boards/free/posts", "/api/v1/boards/notice/posts?page=9", "/api/v1/boards/nope/posts", "/api/v1/boards/free/posts?per_page=2&cursor=&count=false", "/api/v1/boards/free/posts?cursor=x", "/api/v1/boards/member/posts"),
+	"GET /v1/boards/:type/search":    get("/api/v1/boards/free/search?stx=%EC%B2%AB", "/api/v1/boards/free/search", "/api/v1/boards/nope/search?stx=a", "/api/v1/boards/member/search?stx=a"),
+	"GET /v1/boards/:type/posts/:id": get("/api/v1/boards/free/posts/1", "/api/v1/boards/free/posts/999", "/api/v1/boards/free/posts/x", "/api/v1/boards/member/posts/1", "/api/v1/boards/qna/posts/1"),
+	"POST /v1/boards/:type/posts/:id/password": {
+		{path: "/api/v1/boards/qna/posts/3/password"},
+		{path: "/api/v1/boards/qna/posts/3/password", body: routes.UnlockRequest{Password: "wrong"}},
+		{path: "/api/v1/boards/qna/posts/1/password", body: routes.UnlockRequest{Password: "guest1234"}},
+		{path: "/api/v1/boards/qna/posts/3/password", body: routes.UnlockRequest{Password: "guest1234"}},
+	},
+	"GET /v1/boards/:type/posts/:id/comments": append(get("/api/v1/boards/free/posts/1/comments", "/api/v1/boards/free/posts/2/comments", "/api/v1/boards/free/posts/1/comments?per_page=2&page=2", "/api/v1/boards/member/posts/1/comments"),
+		specExample{path: "/api/v1/boards/notice/posts/1/comments", as: "user2"}),
+	"GET /latest": get("/api/latest", "/api/latest?boards=free&per_page=2&page=2", "/api/latest?page=100&per_page=100"),
+	"GET /search": get("/api/search?stx=%EC%B2%AB", "/api/search", "/api/search?sfl=nope&stx=a"),
+	"POST /auth/login": {
+		{path: "/api/auth/login"},
+		{path: "/api/auth/login", body: routes.LoginRequest{ID: "user1", Password: "wrong"}},
+		{path: "/api/auth/login", body: routes.LoginRequest{ID: "user1", Password: "user1pass"}},
+	},
+	"POST /auth/token": {
+		{path: "/api/auth/token"},
+		{path: "/api/auth/token", body: routes.LoginRequest{ID: "user1", Password: "user1pass"}},
+	},
+	"POST /auth/refresh": {
+		{path: "/api/auth/refresh"},
+		{path: "/api/auth/refresh", body: routes.RefreshRequest{RefreshToken: "x"}},
+		{build: func(t *testing.T, h *servertest.Harness) *http.Request {
+			pair := issue(t, h, "user1", "user1pass")
+			return jsonRequest(t, http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: pair.RefreshToken})
+		}},
+	},
+	"POST /auth/logout": {
+		{path: "/api/auth/logout"},
+		{build: func(t *testing.T, h *servertest.Harness) *http.Request {
+			pair := issue(t, h, "user1", "user1pass")
+			req := jsonRequest(t, http.MethodPost, "/api/auth/logout", routes.RefreshRequest{RefreshToken: pair.RefreshToken})
+			req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+			return req
+		}},
+	},
+	"GET /me":        {{path: "/api/me"}, {path: "/api/me", as: "user1"}},
+	"GET /me/points": {{path: "/api/me/points"}, {path: "/api/me/points", as: "user1"}, {path: "/api/me/points?page=2&per_page=5", as: "user1"}},
+	"POST /:type": {
+		{path: "/api/free"},
+		{path: "/api/nope", as: "user1"},
+		{path: "/api/notice", as: "user1", body: routes.PostRequest{Subject: "공지", Content: "내용"}},
+		{path: "/api/free", as: "user1", body: routes.PostRequest{Subject: "", Content: ""}},
+		{path: "/api/free", as: "user1", body: routes.PostRequest{Subject: "제목", Content: "<p>내용</p>", Format: "html1"}},
+		{path: "/api/free", body: routes.PostRequest{Subject: "손님 글", Content: "내용", Name: "손님", Password: "guest1234"}},
+	},
+	"PUT /:type/:id": {
+		{path: "/api/free/1"},
+		{path: "/api/free/3", as: "user2", body: routes.PostRequest{Subject: "남의 글", Content: "내용"}},
+		{path: "/api/free/3", as: "user1", body: routes.PostRequest{Subject: "고친 제목", Content: "고친 본문"}},
+	},
+	"DELETE /:type/:id": {
+		{path: "/api/free/999", as: "user1"},
+		{path: "/api/free/1"},
+		{path: "/api/free/5"},
+		{path: "/api/nope/1"},
+		{path: "/api/free/3", as: "user1"},
+	},
+	"POST /:type/:id/good":   {{path: "/api/free/1/good"}, {path: "/api/notice/1/good", as: "user2"}, {path: "/api/free/x/good"}, {path: "/api/free/1/good", as: "user2"}, {path: "/api/free/1/good", as: "olduser"}},
+	"POST /:type/:id/nogood": {{path: "/api/free/1/nogood"}, {path: "/api/qna/1/nogood", as: "user2"}, {path: "/api/free/1/nogood", as: "user2"}, {path: "/api/free/1/nogood", as: "olduser"}},
+	"GET /:type/:id/files/:no": {
+		{path: "/api/free/2/files/0"},
+		{path: "/api/free/2/files/9", as: "user1"},
+		{path: "/api/free/x/files/0"},
+		{as: "user1", build: func(t *testing.T, h *servertest.Harness) *http.Request {
+			writeAttachment(t, h)
+			return httptest.NewRequest(http.MethodGet, "/api/free/2/files/0", nil)
+		}},
+	},
+	"POST /:type/:id/files": {
+		{path: "/api/free/2/files"},
+		{path: "/api/notice/1/files", as: "user1"},
+		{path: "/api/free/x/files"},
+		{path: "/api/free/999/files", as: "user1"},
+		{as: "user2", build: func(t *testing.T, h *servertest.Harness) *http.Request {
+			return uploadRequest(t, "/api/free/2/files", formPart{"bf_file[]", "memo.txt", "메모"})
+		}},
+	},
+	"GET /:type/:id/thumbnail": {
+		{path: "/api/free/1/thumbnail"},
+		{path: "/api/gallery/1/thumbnail"},
+		{path: "/api/gallery/x/thumbnail"},
+		{path: "/api/gallery/1/thumbnail?size=1x1"},
+		{build: func(t *testing.T, h *servertest.Harness) *http.Request {
+			user1 := h.Login("user1", "user1pass")
+			post := createPost(t, user1, "gallery", routes.PostRequest{Subject: "사진", Content: "본문"})
+			path := "/api/gallery/" + strconv.Itoa(post.ID)
+			if status, raw := upload(t, user1, path+"/files", formPart{"bf_file[]", "photo.png", pngImage(t, 40, 30)}); status != 201 {
+				t.Fatalf("upload: %d %s", status, raw)
+			}
+			return httptest.NewRequest(http.MethodGet, path+"/thumbnail", nil)
+		}},
+	},
+	"POST /:type/:id/comments": {
+		{path: "/api/free/1/comments"},
+		{path: "/api/free/x/comments"},
+		{path: "/api/free/1/comments", as: "user1", body: routes.CommentRequest{}},
+		{path: "/api/free/1/comments", as: "user1", body: routes.CommentRequest{Content: "댓글"}},
+	},
+	"PUT /:type/:id/comments/:comment_id": {
+		{path: "/api/free/1/comments/11"},
+		{path: "/api/free/1/comments/11", as: "user1", body: routes.CommentRequest{Content: "남의 댓글"}},
+		{path: "/api/free/1/comments/13", as: "user1", body: routes.CommentRequest{Content: "고친 댓글"}},
+	},
+	"DELETE /:type/:id/comments/:comment_id": {
+		{path: "/api/free/1/comments/99", as: "user1"},
+		{path: "/api/free/1/comments/11"},
+		{path: "/api/free/1/comments/x"},
+		{path: "/api/free/1/comments/13", as: "user1"},
+	},
+	"GET /:type":              get("/api/free", "/api/free?page=9", "/api/nope", "/api/member"),
+	"GET /:type/:id":          get("/api/free/1", "/api/free/999", "/api/nope/1", "/api/member/1"),
+	"GET /:type/:id/comments": get("/api/free/1/comments", "/api/free/2/comments", "/api/nope/1/comments", "/api/member/1/comments"),
+}
+
+// jsonRequest builds a JSON request; body nil sends no body
+func jsonRequest(t *testing.T, method, path string, body interface{}) *http.Request {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+// specPath turns /:type/:id into the document's /{type}/{id}
+func specPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// TestResponsesMatchSpec fails when a handler's response shape diverges from
+// the OpenAPI document. 라우트마다 새 하네스를 써서 쓰기 예시가 서로 영향을 주지 않음
+func TestResponsesMatchSpec(t *testing.T) {
+	spec := server.Spec()
+
+	for _, r := range routes.APIRoutes() {
+		key := r.Method + " " + r.Path
+		examples, ok := specExamples[key]
+		if !ok {
+			t.Errorf("%s: no example request in specExamples", key)
+			continue
+		}
+
+		path := specPath(r.Path)
+		item := spec.Paths[path][strings.ToLower(r.Method)]
+		if item == nil {
+			t.Errorf("%s: missing from spec (%s)", key, path)
+			continue
+		}
+
+		h := newTokenHarness(t, time.Minute)
+		succeeded := false
+		for _, ex := range examples {
+			client := h.NewClient()
+			if ex.as != "" {
+				client = h.Login(ex.as, specLogins[ex.as])
+			}
+			var req *http.Request
+			if ex.build != nil {
+				req = ex.build(t, h)
+			} else {
+				req = jsonRequest(t, r.Method, ex.path, ex.body)
+			}
+			req.Header.Set("Accept", "application/json")
+			url := req.URL.String()
+			resp := client.Do(req)
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				succeeded = true
+			}
+
+			documented, ok := item.Responses[strconv.Itoa(resp.StatusCode)]
+			if !ok {
+				t.Errorf("%s %s: status %d is not documented", r.Method, url, resp.StatusCode)
+				resp.Body.Close()
+				continue
+			}
+
+			if documented.Content == nil {
+				resp.Body.Close()
+				continue
+			}
+			var body interface{}
+			err := json.NewDecoder(resp.Body).Decode(&body)
+			resp.Body.Close()
+			if err != nil {
+				t.Errorf("%s %s: %v", r.Method, url, err)
+				continue
+			}
+			if err := spec.Validate(documented.Content["application/json"].Schema, body); err != nil {
+				t.Errorf("%s %s (%d): %v", r.Method, url, resp.StatusCode, err)
+			}
+		}
+		if !succeeded {
+			t.Errorf("%s: no example got a 2xx response", key)
+		}
+	}
+}
+
+func TestSpecServed(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var doc openapi.Document
+	if status := h.GetJSON("/api/openapi.json", &doc); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	// 같은 경로에 여러 메서드가 있을 수 있음
+	paths := map[string]bool{}
+	for key := range specExamples {
+		_, path, _ := strings.Cut(key, " ")
+		paths[path] = true
+	}
+	if doc.OpenAPI != "3.1.0" || len(doc.Paths) != len(paths) {
+		t.Errorf("openapi = %q, %d paths", doc.OpenAPI, len(doc.Paths))
+	}
+	if _, ok := doc.Components.Schemas["PostSummary"]; !ok {
+		t.Error("PostSummary schema missing")
+	}
+}
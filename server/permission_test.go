@@ -0,0 +1,105 @@
+package server_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+// member 게시판: bo_list_level 2, bo_read_level 3
+func TestBoardLevels(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user := h.Login("user1", "user1pass") // 레벨 2
+	admin := h.Login("admin", "admin1234")
+
+	tests := []struct {
+		path   string
+		guest  int
+		user   int
+		admin  int
+		member string // 회원이 받는 에러 코드
+	}{
+		{"/api/v1/boards/member/posts", 401, 200, 200, ""},
+		{"/api/v1/boards/member/search?stx=%ED%9A%8C%EC%9B%90", 401, 200, 200, ""},
+		{"/api/v1/boards/member/posts/1", 401, 403, 200, routes.ErrCodeForbidden},
+		{"/api/v1/boards/member/posts/1/comments", 401, 403, 200, routes.ErrCodeForbidden},
+	}
+	for _, tt := range tests {
+		var guest routes.ErrorEnvelope
+		if status := h.GetJSON(tt.path, &guest); status != tt.guest || guest.Error.Code != routes.ErrCodeUnauthorized {
+			t.Errorf("guest %s: %d %q", tt.path, status, guest.Error.Code)
+		}
+		var body routes.ErrorEnvelope
+		if status := user.JSON(http.MethodGet, tt.path, nil, &body); status != tt.user || body.Error.Code != tt.member {
+			t.Errorf("user1 %s: %d %q", tt.path, status, body.Error.Code)
+		}
+		if status := admin.JSON(http.MethodGet, tt.path, nil, nil); status != tt.admin {
+			t.Errorf("admin %s: %d", tt.path, status)
+		}
+	}
+}
+
+func TestLegacyBoardLevels(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user := h.Login("user1", "user1pass")
+
+	for path, want := range map[string][2]int{
+		"/api/member":            {401, 200},
+		"/api/member/1":          {401, 403},
+		"/api/member/1/comments": {401, 403},
+	} {
+		var guest routes.LegacyError
+		if status := h.GetJSON(path, &guest); status != want[0] || guest.Error == "" {
+			t.Errorf("guest %s: %d %+v", path, status, guest)
+		}
+		if status := user.JSON(http.MethodGet, path, nil, nil); status != want[1] {
+			t.Errorf("user1 %s: %d, want %d", path, status, want[1])
+		}
+	}
+}
+
+func TestBoardLevelsSSR(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	status, body := h.GetHTML("/member")
+	if status != 401 || !strings.Contains(body, "로그인") {
+		t.Errorf("guest list: %d", status)
+	}
+	if status, _ := h.GetHTML("/member/1"); status != 401 {
+		t.Errorf("guest view: %d", status)
+	}
+}
+
+func TestBoardPermissionsInfo(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var guest routes.Envelope[routes.BoardInfo]
+	h.GetJSON("/api/v1/boards/member", &guest)
+	if guest.Data.Permissions != (routes.BoardPermissions{}) || guest.Data.CommentLevel != 3 {
+		t.Errorf("guest: %+v", guest.Data)
+	}
+
+	var user routes.Envelope[routes.BoardInfo]
+	h.Login("user1", "user1pass").JSON(http.MethodGet, "/api/v1/boards/member", nil, &user)
+	if user.Data.Permissions != (routes.BoardPermissions{List: true}) {
+		t.Errorf("user1: %+v", user.Data.Permissions)
+	}
+}
+
+func TestFeedSkipsForbiddenBoards(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	_, refs := feedRefs(t, h, "/api/latest?boards=member")
+	if len(refs) != 0 {
+		t.Errorf("guest sees member board: %v", refs)
+	}
+
+	var body routes.Envelope[[]routes.FeedItem]
+	h.Login("user1", "user1pass").JSON(http.MethodGet, "/api/latest?boards=member", nil, &body)
+	if len(body.Data) != 1 || body.Data[0].BoardID != "member" {
+		t.Errorf("user1 latest: %+v", body.Data)
+	}
+}
@@ -0,0 +1,97 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fibergo/phpsession"
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+// writePHPSession saves a session file as PHP's files handler would
+func writePHPSession(t *testing.T, dir, id, data string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "sess_"+id), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func phpMe(h *servertest.Harness, sessionID, userAgent string) (int, routes.MemberInfo) {
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.AddCookie(&http.Cookie{Name: "PHPSESSID", Value: sessionID})
+	var body routes.Envelope[routes.MemberInfo]
+	resp := h.Do(req)
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body.Data
+}
+
+func TestPHPSession(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	dir := t.TempDir()
+	routes.InitPHPSession(&routes.PHPSession{
+		Reader:   phpsession.Reader{Store: phpsession.FileStore{Dir: dir}},
+		CheckKey: true,
+	})
+
+	// app.Test 의 클라이언트 IP 는 0.0.0.0
+	key := phpsession.MemberKey("2024-01-01 00:00:00", "0.0.0.0", "test-agent")
+	writePHPSession(t, dir, "good", `ss_mb_id|s:5:"user2";ss_mb_key|s:32:"`+key+`";`)
+	writePHPSession(t, dir, "guest", `ss_lang|s:2:"ko";`)
+	writePHPSession(t, dir, "left", `ss_mb_id|s:6:"leaver";ss_mb_key|s:32:"`+key+`";`)
+
+	if status, m := phpMe(h, "good", "test-agent"); status != 200 || m.ID != "user2" {
+		t.Errorf("good session: %d %+v", status, m)
+	}
+	// 다른 브라우저에서 쿠키를 재사용하면 ss_mb_key 가 맞지 않음
+	if status, _ := phpMe(h, "good", "other-agent"); status != 401 {
+		t.Errorf("stolen session: %d", status)
+	}
+	for _, id := range []string{"guest", "left", "missing", "../../etc/passwd"} {
+		if status, _ := phpMe(h, id, "test-agent"); status != 401 {
+			t.Errorf("%s: %d", id, status)
+		}
+	}
+
+	routes.InitPHPSession(&routes.PHPSession{Reader: phpsession.Reader{Store: phpsession.FileStore{Dir: dir}}})
+	if status, _ := phpMe(h, "good", "other-agent"); status != 200 {
+		t.Errorf("without key check: %d", status)
+	}
+}
+
+func TestPHPSessionBehindProxy(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	dir := t.TempDir()
+	routes.InitPHPSession(&routes.PHPSession{
+		Reader:   phpsession.Reader{Store: phpsession.FileStore{Dir: dir}},
+		CheckKey: true,
+	})
+
+	// PHP 는 프록시가 넘긴 클라이언트 IP 로 ss_mb_key 를 만듦
+	key := phpsession.MemberKey("2024-01-01 00:00:00", "203.0.113.7", "test-agent")
+	writePHPSession(t, dir, "proxied", `ss_mb_id|s:5:"user2";ss_mb_key|s:32:"`+key+`";`)
+
+	me := func(forwarded string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", "test-agent")
+		req.Header.Set("X-Forwarded-For", forwarded)
+		req.AddCookie(&http.Cookie{Name: "PHPSESSID", Value: "proxied"})
+		resp := h.Do(req)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if status := me("203.0.113.7, 10.0.0.1"); status != 200 {
+		t.Errorf("forwarded client IP: %d", status)
+	}
+	if status := me("198.51.100.1"); status != 401 {
+		t.Errorf("other client IP: %d", status)
+	}
+}
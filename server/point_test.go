@@ -0,0 +1,183 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"fibergo/repository/memory"
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func TestReadPoint(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass") // 3 포인트
+	user2 := h.Login("user2", "user2pass") // 0 포인트
+
+	// 공지 게시판 bo_read_point -2 는 글마다 한 번만
+	for _, path := range []string{"/api/v1/boards/notice/posts/1", "/api/v1/boards/notice/posts/1", "/api/notice/1"} {
+		if status := user1.JSON(http.MethodGet, path, nil, nil); status != 200 {
+			t.Fatalf("%s: %d", path, status)
+		}
+	}
+	points := h.Store.Points("user1")
+	if len(points) != 1 || points[0].Point != -2 || points[0].MemberPoint != 1 || points[0].RelTable != "notice" || points[0].RelID != "1" || points[0].RelAction != "읽기" {
+		t.Fatalf("user1 points = %+v", points)
+	}
+
+	var errBody routes.ErrorEnvelope
+	if status := user2.JSON(http.MethodGet, "/api/v1/boards/notice/posts/1", nil, &errBody); status != 403 || errBody.Error.Code != routes.ErrCodeInsufficientPoints {
+		t.Errorf("user2 v1 = %d %+v", status, errBody)
+	}
+	var legacy map[string]string
+	if status := user2.JSON(http.MethodGet, "/api/notice/1", nil, &legacy); status != 403 || legacy["error"] == "" {
+		t.Errorf("user2 legacy = %d %v", status, legacy)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/notice/1", nil)
+	req.Header.Set("Accept", "text/html")
+	if resp := user2.Do(req); resp.StatusCode != 403 {
+		t.Errorf("user2 SSR = %d", resp.StatusCode)
+	}
+	if points := h.Store.Points("user2"); len(points) != 0 {
+		t.Errorf("user2 points = %+v", points)
+	}
+
+	// 손님과 관리자는 차감하지 않음
+	if status := h.GetJSON("/api/v1/boards/notice/posts/1", &struct{}{}); status != 200 {
+		t.Errorf("guest = %d", status)
+	}
+	if status := h.Login("admin", "admin1234").JSON(http.MethodGet, "/api/v1/boards/notice/posts/1", nil, nil); status != 200 {
+		t.Errorf("admin = %d", status)
+	}
+	if points := h.Store.Points("admin"); len(points) != 0 {
+		t.Errorf("admin points = %+v", points)
+	}
+}
+
+func TestDownloadPoint(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	writeAttachment(t, h)
+	user1 := h.Login("user1", "user1pass")
+
+	// 자유게시판 bo_download_point -1, 이어받기는 다시 차감하지 않음
+	for _, rangeHeader := range []string{"", "bytes=10-"} {
+		if resp, _ := download(t, user1, "/api/free/2/files/0", rangeHeader); resp.StatusCode != 200 && resp.StatusCode != 206 {
+			t.Fatalf("download %q: %d", rangeHeader, resp.StatusCode)
+		}
+	}
+	// 새 세션이어도 이미 차감한 글은 다시 차감하지 않음
+	download(t, h.Login("user1", "user1pass"), "/api/free/2/files/0", "")
+	points := h.Store.Points("user1")
+	if len(points) != 1 || points[0].Point != -1 || points[0].MemberPoint != 2 || points[0].RelAction != "다운로드" {
+		t.Fatalf("user1 points = %+v", points)
+	}
+
+	// 글쓴이는 차감하지 않음 (free/2 는 user2 의 글)
+	if resp, _ := download(t, h.Login("user2", "user2pass"), "/api/free/2/files/0", ""); resp.StatusCode != 200 {
+		t.Errorf("author download = %d", resp.StatusCode)
+	}
+
+	// 포인트가 모자라면 403 이고 다운로드 수도 그대로
+	olduser := h.Login("olduser", "oldpass") // 0 포인트
+	var errBody routes.ErrorEnvelope
+	if status := olduser.JSON(http.MethodGet, "/api/free/2/files/0", nil, &errBody); status != 403 || errBody.Error.Code != routes.ErrCodeInsufficientPoints {
+		t.Errorf("olduser = %d %+v", status, errBody)
+	}
+	var detail routes.Envelope[routes.PostDetail]
+	h.GetJSON("/api/v1/boards/free/posts/2", &detail)
+	if n := detail.Data.Files[0].Downloads; n != 6 {
+		t.Errorf("downloads = %d, want 6", n)
+	}
+}
+
+func TestMyPoints(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	writeAttachment(t, h)
+	user1 := h.Login("user1", "user1pass")
+
+	user1.JSON(http.MethodGet, "/api/v1/boards/notice/posts/1", nil, nil)
+	download(t, user1, "/api/free/2/files/0", "")
+	post := createPost(t, user1, "free", routes.PostRequest{Subject: "포인트", Content: "내용"})
+
+	var body routes.Envelope[[]routes.PointItem]
+	if status := user1.JSON(http.MethodGet, "/api/me/points", nil, &body); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	got := body.Data
+	if len(got) != 3 || body.Meta == nil || body.Meta.Total == nil || *body.Meta.Total != 3 || body.Meta.PerPage != 15 {
+		t.Fatalf("points = %+v, meta = %+v", got, body.Meta)
+	}
+	// 최신순이고 잔액은 그 시점의 mb_point
+	if got[0].Point != 5 || got[0].RelAction != "쓰기" || got[0].Balance != 5 || got[0].RelID != strconv.Itoa(post.ID) {
+		t.Errorf("newest = %+v", got[0])
+	}
+	if got[2].Point != -2 || got[2].Balance != 1 || got[2].ExpiresOn != "9999-12-31" || got[2].CreatedAt.IsZero() {
+		t.Errorf("oldest = %+v", got[2])
+	}
+
+	if status := user1.JSON(http.MethodGet, "/api/me/points?page=2&per_page=2", nil, &body); status != 200 || len(body.Data) != 1 || body.Data[0].Point != -2 || *body.Meta.Total != 3 {
+		t.Errorf("page 2 = %d %+v", status, body.Data)
+	}
+
+	var errBody routes.ErrorEnvelope
+	if status := h.JSON(http.MethodGet, "/api/me/points", nil, &errBody); status != 401 || errBody.Error.Code != routes.ErrCodeUnauthorized {
+		t.Errorf("guest = %d %+v", status, errBody)
+	}
+}
+
+func TestPointsDisabled(t *testing.T) {
+	store, err := memory.LoadFixture(servertest.DefaultFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cf_use_point 를 끄면 읽기, 다운로드, 글쓰기, 댓글 모두 포인트를 다루지 않음
+	store.SetConfig(memory.FixtureConfig{UsePoint: false})
+	h := servertest.NewWithStore(t, store)
+	writeAttachment(t, h)
+
+	user2 := h.Login("user2", "user2pass") // 0 포인트
+	if status := user2.JSON(http.MethodGet, "/api/v1/boards/notice/posts/1", nil, nil); status != 200 {
+		t.Errorf("read = %d", status)
+	}
+	olduser := h.Login("olduser", "oldpass") // 0 포인트
+	if resp, _ := download(t, olduser, "/api/free/2/files/0", ""); resp.StatusCode != 200 {
+		t.Errorf("download = %d", resp.StatusCode)
+	}
+	post := createPost(t, user2, "free", routes.PostRequest{Subject: "포인트 없음", Content: "내용"})
+	createComment(t, user2, "/api/free/"+strconv.Itoa(post.ID)+"/comments", routes.CommentRequest{Content: "댓글"})
+
+	for _, id := range []string{"user2", "olduser"} {
+		if points := h.Store.Points(id); len(points) != 0 {
+			t.Errorf("%s points = %+v", id, points)
+		}
+	}
+}
+
+func TestPointTerm(t *testing.T) {
+	store, err := memory.LoadFixture(servertest.DefaultFixture())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetConfig(memory.FixtureConfig{UsePoint: true, PointTerm: 30})
+	h := servertest.NewWithStore(t, store)
+	user1 := h.Login("user1", "user1pass")
+
+	user1.JSON(http.MethodGet, "/api/v1/boards/notice/posts/1", nil, nil)
+	createPost(t, user1, "free", routes.PostRequest{Subject: "유효기간", Content: "내용"})
+
+	// 적립은 오늘을 포함해 30일, 차감은 오늘 만료 (insert_point)
+	today := time.Now()
+	points := h.Store.Points("user1")
+	if len(points) != 2 {
+		t.Fatalf("points = %+v", points)
+	}
+	if read := points[0]; read.Expired != 1 || read.ExpireDate != today.Format("2006-01-02") {
+		t.Errorf("read = %+v", read)
+	}
+	if write := points[1]; write.Expired != 0 || write.ExpireDate != today.AddDate(0, 0, 29).Format("2006-01-02") {
+		t.Errorf("write = %+v", write)
+	}
+}
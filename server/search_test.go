@@ -0,0 +1,103 @@
+package server_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func searchIDs(t *testing.T, h *servertest.Harness, query string) (routes.Envelope[[]routes.SearchResult], []int) {
+	t.Helper()
+	var body routes.Envelope[[]routes.SearchResult]
+	if status := h.GetJSON("/api/v1/boards/free/search?per_page=10&"+query, &body); status != 200 {
+		t.Fatalf("search %s: status = %d", query, status)
+	}
+	var ids []int
+	for _, r := range body.Data {
+		ids = append(ids, r.ID)
+	}
+	return body, ids
+}
+
+func TestSearchFields(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"stx=" + url.QueryEscape("번째"), []int{5, 3, 2, 1, 4}},
+		{"stx=" + url.QueryEscape("첫"), []int{1, 4}},
+		{"sfl=wr_content&stx=" + url.QueryEscape("날씨"), []int{3}},
+		{"sfl=" + url.QueryEscape("wr_subject||wr_content") + "&stx=" + url.QueryEscape("최신"), []int{5}},
+		{"sfl=" + url.QueryEscape("mb_id,1") + "&stx=user", nil},
+		{"sfl=" + url.QueryEscape("mb_id,0") + "&stx=user1", []int{3, 1}},
+		{"sfl=" + url.QueryEscape("wr_name,1") + "&stx=" + url.QueryEscape("손님"), []int{5}},
+		{"stx=" + url.QueryEscape("첫 두") + "&sop=and", nil},
+		{"stx=" + url.QueryEscape("첫 두") + "&sop=or", []int{2, 1, 4}},
+	}
+	for _, tt := range tests {
+		if _, got := searchIDs(t, h, tt.query); !equalInts(got, tt.want) {
+			t.Errorf("%s: ids = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchHighlight(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	body, _ := searchIDs(t, h, "sfl="+url.QueryEscape("wr_subject||wr_content")+"&stx="+url.QueryEscape("날씨"))
+	if len(body.Data) != 1 || body.Data[0].SnippetHTML != "오늘 <mark>날씨</mark>" {
+		t.Errorf("data = %+v", body.Data)
+	}
+}
+
+func TestSearchSegments(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	routes.SetSearchPart(2)
+	defer routes.SetSearchPart(10000)
+
+	// wr_num -4 ~ -1 을 2 씩 나눠서 검색
+	stx := "stx=" + url.QueryEscape("번째")
+	body, ids := searchIDs(t, h, stx)
+	if !equalInts(ids, []int{5, 3}) || body.Meta.Search.Spt != -4 || body.Meta.Search.PrevSpt != nil {
+		t.Fatalf("first segment: ids = %v, meta = %+v", ids, body.Meta.Search)
+	}
+	next := body.Meta.Search.NextSpt
+	if next == nil || *next != -2 {
+		t.Fatalf("next_spt = %v", next)
+	}
+
+	body, ids = searchIDs(t, h, stx+"&spt=-2")
+	if !equalInts(ids, []int{2, 1, 4}) || body.Meta.Search.NextSpt != nil || *body.Meta.Search.PrevSpt != -4 {
+		t.Errorf("second segment: ids = %v, meta = %+v", ids, body.Meta.Search)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	for _, q := range []string{"sfl=wr_password&stx=x", "stx="} {
+		var body routes.ErrorEnvelope
+		if status := h.GetJSON("/api/v1/boards/free/search?"+q, &body); status != 400 || body.Error.Code != routes.ErrCodeInvalidSearch {
+			t.Errorf("%s: %d %+v", q, status, body)
+		}
+	}
+}
+
+func TestSearchSSR(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	status, body := h.GetHTML("/free?sfl=wr_subject&stx=" + url.QueryEscape("두 번째"))
+	if status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	for _, want := range []string{"<mark>두</mark> <mark>번째</mark> 글", "검색결과 1개", `value="두 번째"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %q", want)
+		}
+	}
+}
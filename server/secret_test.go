@@ -0,0 +1,185 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+// qna 게시판: 1 user1 의 비밀글, 2 관리자의 비밀 답변, 3 손님 비밀글 (guest1234)
+func TestSecretPostAccess(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	clients := map[string]*servertest.Client{
+		"guest": h.NewClient(),
+		"user1": h.Login("user1", "user1pass"),
+		"user2": h.Login("user2", "user2pass"),
+		"admin": h.Login("admin", "admin1234"),
+	}
+
+	tests := []struct {
+		who  string
+		id   string
+		want int
+	}{
+		{"guest", "1", 403},
+		{"user2", "1", 403},
+		{"user1", "1", 200},
+		{"admin", "1", 200},
+		{"user1", "2", 200}, // 원글 작성자는 답변도 볼 수 있음
+		{"user2", "2", 403},
+		{"user1", "3", 403},
+		{"admin", "3", 200},
+	}
+	for _, tt := range tests {
+		var body routes.Envelope[routes.PostDetail]
+		status := clients[tt.who].JSON(http.MethodGet, "/api/v1/boards/qna/posts/"+tt.id, nil, &body)
+		if status != tt.want {
+			t.Errorf("%s qna/%s: %d, want %d", tt.who, tt.id, status, tt.want)
+		}
+		if status == 200 && (body.Data.ContentText == "" || !body.Data.IsSecret) {
+			t.Errorf("%s qna/%s: %+v", tt.who, tt.id, body.Data)
+		}
+	}
+
+	var denied routes.ErrorEnvelope
+	clients["guest"].JSON(http.MethodGet, "/api/v1/boards/qna/posts/1", nil, &denied)
+	if denied.Error.Code != routes.ErrCodeSecretPost {
+		t.Errorf("code = %q", denied.Error.Code)
+	}
+	var legacy routes.LegacyError
+	if status := h.GetJSON("/api/qna/1", &legacy); status != 403 || legacy.Error == "" {
+		t.Errorf("legacy: %d %+v", status, legacy)
+	}
+}
+
+func TestSecretPostNotCounted(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	h.JSON(http.MethodGet, "/api/v1/boards/qna/posts/1", nil, nil)
+
+	// 거부된 조회는 세지 않음 (응답의 views 는 증가 전 값)
+	user1 := h.Login("user1", "user1pass")
+	user1.JSON(http.MethodGet, "/api/v1/boards/qna/posts/1", nil, nil)
+	var body routes.Envelope[routes.PostDetail]
+	user1.JSON(http.MethodGet, "/api/v1/boards/qna/posts/1", nil, &body)
+	if body.Data.Views != 1 {
+		t.Errorf("views = %d, want 1", body.Data.Views)
+	}
+}
+
+func TestSecretPassword(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	client := h.NewClient()
+
+	var errBody routes.ErrorEnvelope
+	status := client.JSON(http.MethodPost, "/api/v1/boards/qna/posts/3/password", routes.UnlockRequest{Password: "wrong"}, &errBody)
+	if status != 403 || errBody.Error.Code != routes.ErrCodeInvalidPassword {
+		t.Errorf("wrong password: %d %q", status, errBody.Error.Code)
+	}
+	// 회원의 비밀글은 비밀번호로 열 수 없음
+	status = client.JSON(http.MethodPost, "/api/v1/boards/qna/posts/1/password", routes.UnlockRequest{Password: "user1pass"}, &errBody)
+	if status != 403 || errBody.Error.Code != routes.ErrCodeSecretPost {
+		t.Errorf("member post: %d %q", status, errBody.Error.Code)
+	}
+
+	var body routes.Envelope[routes.PostDetail]
+	status = client.JSON(http.MethodPost, "/api/v1/boards/qna/posts/3/password", routes.UnlockRequest{Password: "guest1234"}, &body)
+	if status != 200 || body.Data.ContentText != "손님의 질문" {
+		t.Fatalf("unlock: %d %+v", status, body.Data)
+	}
+	// 세션에 기록되어 이후 조회도 가능
+	if status := client.JSON(http.MethodGet, "/api/v1/boards/qna/posts/3", nil, nil); status != 200 {
+		t.Errorf("after unlock: %d", status)
+	}
+	if status := h.JSON(http.MethodGet, "/api/v1/boards/qna/posts/3", nil, nil); status != 403 {
+		t.Errorf("other client: %d", status)
+	}
+}
+
+func TestSecretPasswordSSR(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	client := h.NewClient()
+
+	if status, body := h.GetHTML("/qna"); status != 200 || !strings.Contains(body, "🔒") {
+		t.Errorf("list: %d", status)
+	}
+	status, body := h.GetHTML("/qna/3")
+	if status != 403 || !strings.Contains(body, `name="wr_password"`) || strings.Contains(body, "손님의 질문") {
+		t.Fatalf("form: %d", status)
+	}
+	if status, _ := h.GetHTML("/qna/1"); status != 403 {
+		t.Errorf("member secret: %d", status)
+	}
+
+	post := func(password string) *http.Response {
+		form := url.Values{"wr_password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/qna/3/password", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return client.Do(req)
+	}
+	if resp := post("wrong"); resp.StatusCode != 403 {
+		t.Errorf("wrong password: %d", resp.StatusCode)
+	}
+	resp := post("guest1234")
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/qna/3" {
+		t.Fatalf("unlock: %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/qna/3", nil)
+	req.Header.Set("Accept", "text/html")
+	if resp := client.Do(req); resp.StatusCode != 200 {
+		t.Errorf("after unlock: %d", resp.StatusCode)
+	}
+}
+
+func TestSecretComments(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	contents := func(client *servertest.Client) []string {
+		var body routes.Envelope[[]routes.CommentItem]
+		if status := client.JSON(http.MethodGet, "/api/v1/boards/notice/posts/1/comments", nil, &body); status != 200 {
+			t.Fatalf("status = %d", status)
+		}
+		var list []string
+		for _, cm := range body.Data {
+			list = append(list, cm.ContentHTML)
+		}
+		return list
+	}
+
+	// notice 1 (관리자 글) 의 21 은 user2 의 비밀 댓글
+	if got := contents(h.Login("user2", "user2pass")); got[0] != "비밀 댓글" {
+		t.Errorf("user2: %v", got)
+	}
+	if got := contents(h.Login("admin", "admin1234")); got[0] != "비밀 댓글" {
+		t.Errorf("admin: %v", got)
+	}
+	for _, client := range []*servertest.Client{h.NewClient(), h.Login("user1", "user1pass")} {
+		if got := contents(client); got[0] != "비밀글 입니다." || got[1] != "공개 댓글" {
+			t.Errorf("others: %v", got)
+		}
+	}
+	// 글을 볼 수 없으면 댓글도 403
+	if status := h.JSON(http.MethodGet, "/api/v1/boards/qna/posts/1/comments", nil, nil); status != 403 {
+		t.Errorf("guest: %d", status)
+	}
+}
+
+func TestSecretSearchSnippet(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.Envelope[[]routes.SearchResult]
+	h.GetJSON("/api/v1/boards/qna/search?sfl=wr_content&stx="+url.QueryEscape("질문"), &body)
+	if len(body.Data) != 2 {
+		t.Fatalf("%d results", len(body.Data))
+	}
+	for _, r := range body.Data {
+		if r.SnippetHTML != "비밀글 입니다." || !r.IsSecret {
+			t.Errorf("%d: %q", r.ID, r.SnippetHTML)
+		}
+	}
+}
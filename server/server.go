@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"fibergo/hits"
+	"fibergo/openapi"
+	"fibergo/repository"
+	"fibergo/repository/cache"
+	"fibergo/routes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/gofiber/template/html/v2"
+)
+
+// Version is the API version reported by / and the OpenAPI document
+const Version = "1.0.0"
+
+// Spec builds the OpenAPI document of every /api route
+func Spec() *openapi.Document {
+	return openapi.Build(openapi.Info{
+		Title:       "Board API",
+		Version:     Version,
+		Description: "그누보드 게시판 API. /api/v1 은 영문 키, 나머지는 레거시 (한글 키) 입니다.",
+	}, "/api", routes.APIDocs())
+}
+
+// New builds the complete Fiber application. It is shared by the
+// long-running server (main.go) and the Vercel entrypoint (api/index.go).
+func New(cfg Config) *fiber.App {
+	cfg.setDefaults()
+
+	// 목록, 글, 댓글 캐시. 쓰기도 캐시를 거쳐야 그 게시판 항목을 버림
+	var postCache *cache.Store
+	if cfg.CacheSize > 0 {
+		postCache = cache.New(cfg.Posts, cfg.Comments, cfg.CacheSize, cfg.CacheTTL)
+		cfg.Posts, cfg.Comments = postCache, postCache
+		cfg.Writer = postCache.Writer(cfg.Writer)
+		cfg.CommentWriter = postCache.CommentWriter(cfg.CommentWriter)
+		cfg.Votes = postCache.Votes(cfg.Votes)
+		cfg.FileWriter = postCache.FileWriter(cfg.FileWriter)
+	}
+
+	// 핸들러에 저장소, 게시판 목록 전달
+	routes.InitRepositories(cfg.Posts, cfg.Comments)
+	routes.InitWriter(cfg.Writer, cfg.CommentWriter)
+	routes.InitVotes(cfg.Votes)
+	routes.InitFiles(cfg.Files, cfg.DataDir)
+	routes.InitUploads(cfg.FileWriter)
+	routes.InitPoints(cfg.Points)
+	routes.InitThumbnails(cfg.ThumbnailSizes)
+	routes.InitSanitizer(cfg.HTMLPolicy)
+	routes.InitBoards(cfg.Boards)
+	routes.SetMaxPageSize(cfg.MaxPageSize)
+	routes.SetSearchPart(cfg.SearchPart)
+	routes.InitFeed(cfg.BoardNew, cfg.FanOutTimeout)
+	routes.SetSuperAdmin(cfg.SuperAdmin)
+	routes.InitAuth(cfg.Members, session.New(session.Config{
+		KeyLookup:      "cookie:" + routes.SessionCookie,
+		Expiration:     cfg.SessionLifetime,
+		CookieHTTPOnly: true,
+		CookieSecure:   cfg.SecureCookie,
+		CookieSameSite: "Lax",
+		CookiePath:     "/",
+	}))
+	routes.InitPHPSession(cfg.PHPSession)
+	routes.InitTokens(cfg.TokenKeys, cfg.Denylist, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+
+	// 템플릿 엔진 설정
+	engine := html.New(cfg.TemplatesDir, ".html")
+	engine.Reload(cfg.TemplateReload)
+	engine.Debug(cfg.TemplateReload)
+
+	app := fiber.New(fiber.Config{
+		Views:        engine,
+		ViewsLayout:  "layouts/main", // 기본 레이아웃 설정
+		Prefork:      false,
+		ErrorHandler: errorHandler(cfg),
+		// 첨부파일은 메모리에 올리지 않고 디스크로 (나머지 요청은 LimitBody 가 BodyLimit 적용)
+		BodyLimit:                    cfg.BodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+		// 클라이언트 IP (c.IP()) 를 프록시 헤더에서. 헤더의 첫 번째 올바른 IP 를 씀
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableIPValidation:      cfg.ProxyHeader != "",
+		EnableTrustedProxyCheck: cfg.EnableTrustedProxyCheck,
+		TrustedProxies:          cfg.TrustedProxies,
+	})
+
+	// 조회수는 모아서 반영하고, 종료할 때 남은 것을 반영
+	counter := hits.NewCounter(cfg.Hits, cfg.Posts, cfg.HitWindow, cfg.HitFlushInterval)
+	routes.InitHits(counter)
+	app.Hooks().OnShutdown(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := counter.Stop(ctx)
+		if store, ok := cfg.Hits.(*hits.MemoryStore); ok {
+			store.Close()
+		}
+		return err
+	})
+
+	// 폐기 목록의 만료된 토큰은 요청과 따로 정리
+	if cfg.TokenKeys != nil {
+		stop := purgeTokens(cfg.Denylist, cfg.TokenPurgeInterval)
+		app.Hooks().OnShutdown(func() error {
+			stop()
+			return nil
+		})
+	}
+
+	app.Use(routes.LimitBody(cfg.BodyLimit))
+
+	// 압축
+	app.Use(compress.New())
+
+	// CORS, 보안 헤더
+	app.Use(corsMiddleware)
+
+	// 정적 파일 제공
+	app.Static("/static", cfg.StaticDir, fiber.Static{
+		Compress: true,
+		MaxAge:   int((24 * time.Hour).Seconds()),
+	})
+
+	// 로그인 세션의 회원 (정적 파일 이후)
+	app.Use(routes.LoadMember)
+
+	// 루트 경로: 서버 상태
+	app.Get("/", handleIndex(cfg, postCache))
+
+	// API 라우트
+	apiGroup := app.Group("/api")
+
+	// Authorization: Bearer 토큰 (세션보다 우선)
+	apiGroup.Use(routes.LoadToken)
+
+	// 쿠키로 로그인한 회원의 쓰기 요청은 이 사이트에서 온 것만 (CSRF)
+	apiGroup.Use(routes.RejectCrossSite)
+
+	// OpenAPI 문서 (라우트 표에서 생성)
+	spec := Spec()
+	apiGroup.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return c.JSON(spec)
+	})
+
+	routes.RegisterAPI(apiGroup)
+
+	// 웹 페이지 라우트
+	app.Get("/:type", routes.HandleBoardSSR)
+	app.Get("/:type/:id", routes.HandleBoardSSR)
+	app.Post("/:type/:id/password", routes.HandleSecretPasswordSSR)
+
+	// 404 에러 핸들러
+	app.Use(func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	return app
+}
+
+// errorHandler answers JSON or HTML depending on the Accept header
+func errorHandler(cfg Config) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		code := fiber.StatusInternalServerError
+		if e, ok := err.(*fiber.Error); ok {
+			code = e.Code
+		}
+
+		if c.Accepts("html", "json") == "json" {
+			message := err.Error()
+			if code == fiber.StatusNotFound {
+				message = "요청하신 페이지를 찾을 수 없습니다"
+			}
+			return c.Status(code).JSON(fiber.Map{
+				"error": message,
+			})
+		}
+
+		if code == fiber.StatusNotFound {
+			return c.Status(code).SendFile(filepath.Join(cfg.TemplatesDir, "404.html"))
+		}
+		return c.Status(code).Render("error", fiber.Map{
+			"Title": "오류가 발생했습니다",
+			"Code":  code,
+			"Error": err.Error(),
+		})
+	}
+}
+
+// purgeTokens deletes expired denylist entries every interval until stop
+// is called. 실패는 기록만 하고 다음 주기에 다시 시도
+func purgeTokens(denylist repository.TokenDenylist, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if err := denylist.PurgeTokens(ctx, time.Now()); err != nil {
+					log.Printf("만료된 토큰 정리 실패: %v", err)
+				}
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,264 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fibergo/repository/cache"
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+type legacyList struct {
+	Board string                   `json:"게시판"`
+	Page  int                      `json:"현재페이지"`
+	Total int                      `json:"전체개수"`
+	Posts []map[string]interface{} `json:"게시글"`
+}
+
+func postIDs(posts []map[string]interface{}) []int {
+	var ids []int
+	for _, p := range posts {
+		ids = append(ids, int(p["id"].(float64)))
+	}
+	return ids
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBoardListPagination(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	tests := []struct {
+		path string
+		want []int
+	}{
+		// bo_page_rows = 3, 최신글 (wr_num 작은 순) 부터, 답변글은 원글 바로 뒤
+		{"/api/free", []int{5, 3, 2}},
+		{"/api/free?page=2", []int{1, 4}},
+		{"/api/free?page=3", nil},
+		{"/api/free?limit=2&page=2", []int{2, 1}},
+	}
+	for _, tt := range tests {
+		var body legacyList
+		if status := h.GetJSON(tt.path, &body); status != 200 {
+			t.Fatalf("GET %s: status = %d", tt.path, status)
+		}
+		if body.Total != 5 {
+			t.Errorf("GET %s: 전체개수 = %d, want 5", tt.path, body.Total)
+		}
+		if got := postIDs(body.Posts); !equalInts(got, tt.want) {
+			t.Errorf("GET %s: ids = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPostDetail(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var post map[string]interface{}
+	if status := h.GetJSON("/api/free/1", &post); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	if post["제목"] != "첫 번째 글" || post["이름"] != "홍길동" {
+		t.Errorf("post = %v", post)
+	}
+	if post["날짜"] != "2025-01-01 09:00:00" {
+		t.Errorf("날짜 = %v", post["날짜"])
+	}
+
+	// 조회수 증가 확인
+	h.GetJSON("/api/free/1", &post)
+	if post["조회"] != float64(11) {
+		t.Errorf("조회 = %v, want 11", post["조회"])
+	}
+}
+
+func TestHitsDeduplicated(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+	user2 := h.Login("user2", "user2pass")
+
+	// 회원은 회원마다, 손님은 IP 마다 한 번 (페이지와 API 를 합쳐서)
+	user1.JSON(http.MethodGet, "/api/v1/boards/free/posts/1", nil, nil)
+	user1.JSON(http.MethodGet, "/api/free/1", nil, nil)
+	user2.JSON(http.MethodGet, "/api/v1/boards/free/posts/1", nil, nil)
+	h.GetHTML("/free/1")
+	h.GetJSON("/api/free/1", &struct{}{})
+
+	var body routes.Envelope[routes.PostDetail]
+	h.GetJSON("/api/v1/boards/free/posts/1", &body)
+	if body.Data.Views != 13 {
+		t.Errorf("views = %d, want 13", body.Data.Views)
+	}
+}
+
+func TestHitsGuestIP(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	// 프록시 뒤의 손님은 X-Forwarded-For 의 클라이언트 IP 마다 한 번
+	var body routes.Envelope[routes.PostDetail]
+	for _, ip := range []string{"203.0.113.1", "198.51.100.7, 10.0.0.1", "203.0.113.1", "198.51.100.7"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/boards/free/posts/1", nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("X-Forwarded-For", ip)
+		resp := h.Do(req)
+		body = routes.Envelope[routes.PostDetail]{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if body.Data.Views != 12 {
+		t.Errorf("views = %d, want 12", body.Data.Views)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	// 같은 목록을 두 번 읽으면 두 번째는 캐시에서
+	h.GetJSON("/api/v1/boards/free/posts", &struct{}{})
+	h.GetJSON("/api/v1/boards/free/posts", &struct{}{})
+
+	var index struct {
+		Server struct {
+			Cache *cache.Stats `json:"cache"`
+		} `json:"server"`
+	}
+	if status := h.GetJSON("/", &index); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	stats := index.Server.Cache
+	if stats == nil || stats.Hits == 0 || stats.Misses == 0 || stats.Entries == 0 {
+		t.Errorf("cache stats = %+v", stats)
+	}
+}
+
+func TestPostNotFound(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body map[string]interface{}
+	if status := h.GetJSON("/api/free/999", &body); status != 404 {
+		t.Errorf("API status = %d, want 404", status)
+	}
+	if status, _ := h.GetHTML("/free/999"); status != 404 {
+		t.Errorf("SSR status = %d, want 404", status)
+	}
+	if status := h.GetJSON("/api/free/abc", &body); status != 400 {
+		t.Errorf("status for non-numeric id = %d, want 400", status)
+	}
+}
+
+func TestInvalidBoard(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	for _, path := range []string{"/api/nope", "/api/nope/1", "/api/nope/1/comments", "/api/free;drop/1"} {
+		var body map[string]interface{}
+		if status := h.GetJSON(path, &body); status != 400 {
+			t.Errorf("GET %s: status = %d, want 400", path, status)
+		}
+		if body["error"] != "유효하지 않은 게시판입니다" {
+			t.Errorf("GET %s: error = %v", path, body["error"])
+		}
+	}
+}
+
+func TestCommentOrdering(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body struct {
+		Count    int                      `json:"count"`
+		Comments []map[string]interface{} `json:"comments"`
+	}
+	if status := h.GetJSON("/api/free/1/comments", &body); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	// wr_comment, wr_comment_reply 순서
+	if got, want := postIDs(body.Comments), []int{11, 12, 14, 13}; !equalInts(got, want) {
+		t.Errorf("comment ids = %v, want %v", got, want)
+	}
+	if body.Count != 4 {
+		t.Errorf("count = %d, want 4", body.Count)
+	}
+}
+
+func TestCommentThread(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.LegacyCommentList
+	if status := h.GetJSON("/api/free/1/comments?limit=2&page=2", &body); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	// 14 (AA) 의 부모 12 (A) 는 이전 페이지에 있음
+	want := []routes.LegacyComment{{ID: 14, Depth: 2, ReplyTo: 12}, {ID: 13, Depth: 0, ReplyTo: 0}}
+	if body.Count != 4 || len(body.Comments) != len(want) {
+		t.Fatalf("body = %+v", body)
+	}
+	for i, w := range want {
+		got := body.Comments[i]
+		if got.ID != w.ID || got.Depth != w.Depth || got.ReplyTo != w.ReplyTo {
+			t.Errorf("comments[%d] = %+v, want id %d depth %d parent %d", i, got, w.ID, w.Depth, w.ReplyTo)
+		}
+	}
+
+	var empty map[string]interface{}
+	h.GetJSON("/api/free/2/comments", &empty)
+	if list, ok := empty["comments"].([]interface{}); !ok || len(list) != 0 {
+		t.Errorf("comments = %#v, want []", empty["comments"])
+	}
+}
+
+func TestBoardSSR(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	status, body := h.GetHTML("/free")
+	if status != 200 {
+		t.Fatalf("list status = %d", status)
+	}
+	for _, want := range []string{"<title>자유게시판</title>", `href="/free/5"`, "네 번째 글", "전체 5개"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("list page does not contain %q", want)
+		}
+	}
+
+	status, body = h.GetHTML("/free/2")
+	if status != 200 {
+		t.Fatalf("view status = %d", status)
+	}
+	for _, want := range []string{"두 번째 글", "반갑습니다", "작성자: 김철수"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("view page does not contain %q", want)
+		}
+	}
+
+	// 대댓글 들여쓰기
+	_, body = h.GetHTML("/free/1")
+	for _, want := range []string{"댓글 <span id=\"comment-count\">(4)</span>", `id="c_12" style="--depth: 1"`, `id="c_14" style="--depth: 2"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("view page does not contain %q", want)
+		}
+	}
+}
+
+func TestUnknownRoute(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	status, body := h.GetHTML("/free/1/unknown")
+	if status != 404 || !strings.Contains(body, "404") {
+		t.Errorf("status = %d", status)
+	}
+}
@@ -0,0 +1,213 @@
+// Package servertest runs the full application against an in-memory
+// store so handlers can be tested without a Gnuboard database.
+package servertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"fibergo/board"
+	"fibergo/repository/memory"
+	"fibergo/server"
+	"fibergo/thumbnail"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Harness is an application built on a memory store
+type Harness struct {
+	t     testing.TB
+	App   *fiber.App
+	Store *memory.Store
+	// 첨부파일을 두는 그누보드 data 디렉터리 (테스트마다 임시 디렉터리)
+	DataDir string
+}
+
+// 저장소 루트 (templates, static 위치)
+func rootDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+// DefaultFixture is the path of the bundled fixture file
+func DefaultFixture() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "testdata", "fixtures.json")
+}
+
+// New loads the fixture and builds the application
+func New(t testing.TB, fixturePath string) *Harness {
+	t.Helper()
+
+	store, err := memory.LoadFixture(fixturePath)
+	if err != nil {
+		t.Fatalf("픽스처 로드 실패: %v", err)
+	}
+	return NewWithStore(t, store)
+}
+
+// NewWithStore builds the application on an existing store
+func NewWithStore(t testing.TB, store *memory.Store) *Harness {
+	t.Helper()
+
+	registry := board.NewRegistry(store, nil)
+	if err := registry.Load(context.Background()); err != nil {
+		t.Fatalf("게시판 목록 로드 실패: %v", err)
+	}
+
+	dataDir := t.TempDir()
+	app := server.New(server.Config{
+		Boards:        registry,
+		Posts:         store,
+		Comments:      store,
+		Members:       store,
+		Writer:        store,
+		CommentWriter: store,
+		Votes:         store,
+		Files:         store,
+		FileWriter:    store,
+		Points:        store,
+		DataDir:       dataDir,
+		// 갤러리 게시판 기본 크기 외에 허용하는 크기
+		ThumbnailSizes: []thumbnail.Size{{Width: 100, Height: 100}},
+		Denylist:       store,
+		// 캐시를 켜 두어 쓰기 후 무효화도 함께 확인
+		CacheSize:  100,
+		CacheTTL:   time.Minute,
+		SuperAdmin: "admin", // 픽스처의 최고관리자
+		// app.Test 의 접속 주소 0.0.0.0 을 프록시로 보고 X-Forwarded-For 로 클라이언트 IP 를 줌
+		ProxyHeader:             "X-Forwarded-For",
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          []string{"0.0.0.0"},
+		TemplatesDir:            filepath.Join(rootDir(), "templates"),
+		StaticDir:               filepath.Join(rootDir(), "static"),
+	})
+	return &Harness{t: t, App: app, Store: store, DataDir: dataDir}
+}
+
+// Do sends the request through app.Test
+func (h *Harness) Do(req *http.Request) *http.Response {
+	h.t.Helper()
+	resp, err := h.App.Test(req, -1)
+	if err != nil {
+		h.t.Fatalf("%s %s: %v", req.Method, req.URL, err)
+	}
+	return resp
+}
+
+// Get sends a GET request with the given Accept header
+func (h *Harness) Get(path, accept string) *http.Response {
+	h.t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	return h.Do(req)
+}
+
+// GetJSON requests path and decodes the JSON body into v, returning the status
+func (h *Harness) GetJSON(path string, v interface{}) int {
+	h.t.Helper()
+	resp := h.Get(path, fiber.MIMEApplicationJSON)
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		h.t.Fatalf("GET %s: JSON 디코딩 실패: %v", path, err)
+	}
+	return resp.StatusCode
+}
+
+// GetHTML requests path as a browser would and returns status and body
+func (h *Harness) GetHTML(path string) (int, string) {
+	h.t.Helper()
+	resp := h.Get(path, "text/html")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("GET %s: %v", path, err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+// JSON sends body (if not nil) as JSON and decodes the response into v
+// (if not nil), returning the status
+func (h *Harness) JSON(method, path string, body, v interface{}) int {
+	h.t.Helper()
+	return doJSON(h.t, h.Do, method, path, body, v)
+}
+
+// Client keeps cookies between requests like a browser
+type Client struct {
+	h   *Harness
+	jar *cookiejar.Jar
+}
+
+// NewClient returns a client with an empty cookie jar
+func (h *Harness) NewClient() *Client {
+	jar, _ := cookiejar.New(nil)
+	return &Client{h: h, jar: jar}
+}
+
+// Login returns a client logged in as id through /api/auth/login
+func (h *Harness) Login(id, password string) *Client {
+	h.t.Helper()
+	cl := h.NewClient()
+	body := map[string]string{"mb_id": id, "mb_password": password}
+	if status := cl.JSON(http.MethodPost, "/api/auth/login", body, nil); status != http.StatusOK {
+		h.t.Fatalf("%s 로그인 실패: %d", id, status)
+	}
+	return cl
+}
+
+// Do sends the request with the stored cookies and keeps the ones it sets
+func (cl *Client) Do(req *http.Request) *http.Response {
+	cl.h.t.Helper()
+	// httptest.NewRequest 의 URL 에는 호스트가 없음
+	u := &url.URL{Scheme: "http", Host: req.Host, Path: req.URL.Path}
+	for _, c := range cl.jar.Cookies(u) {
+		req.AddCookie(c)
+	}
+	resp := cl.h.Do(req)
+	cl.jar.SetCookies(u, resp.Cookies())
+	return resp
+}
+
+// JSON is Harness.JSON with the client's cookies
+func (cl *Client) JSON(method, path string, body, v interface{}) int {
+	cl.h.t.Helper()
+	return doJSON(cl.h.t, cl.Do, method, path, body, v)
+}
+
+func doJSON(t testing.TB, do func(*http.Request) *http.Response, method, path string, body, v interface{}) int {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Accept", fiber.MIMEApplicationJSON)
+	if body != nil {
+		req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+	}
+	resp := do(req)
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: JSON 디코딩 실패: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
@@ -0,0 +1,149 @@
+package server_test
+
+import (
+	"image"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func getThumbnail(t *testing.T, cl *servertest.Client, path string) (*http.Response, []byte) {
+	t.Helper()
+	resp := cl.Do(httptest.NewRequest(http.MethodGet, path, nil))
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func TestGalleryThumbnails(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	// 첨부파일 이미지
+	attached := createPost(t, user1, "gallery", routes.PostRequest{Subject: "첨부 사진", Content: "본문"})
+	if status, raw := upload(t, user1, "/api/gallery/"+strconv.Itoa(attached.ID)+"/files",
+		formPart{"bf_file[]", "memo.txt", "메모"},
+		formPart{"bf_file[]", "photo.png", pngImage(t, 400, 300)},
+	); status != 201 {
+		t.Fatalf("upload: %d %s", status, raw)
+	}
+	// 에디터로 올린 본문 이미지
+	editorDir := filepath.Join(h.DataDir, "editor", "2501")
+	if err := os.MkdirAll(editorDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(editorDir, "inline.png"), []byte(pngImage(t, 120, 120)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inline := createPost(t, user1, "gallery", routes.PostRequest{
+		Subject: "본문 사진",
+		Content: `<p><img src="http://elsewhere.example/a.png"><img alt="" src="/data/editor/2501/inline.png"></p>`,
+	})
+	// 에디터 이미지가 아닌 data 디렉터리의 파일로는 만들지 않음
+	if err := os.WriteFile(filepath.Join(h.DataDir, "secret.png"), []byte(pngImage(t, 10, 10)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	text := createPost(t, user1, "gallery", routes.PostRequest{
+		Subject: "글만",
+		Content: `<img src="/data/../secret.png"><img src="/data/secret.png"><img src="/data/editor/../secret.png">`,
+	})
+
+	var list routes.Envelope[[]routes.PostSummary]
+	if status := h.GetJSON("/api/v1/boards/gallery/posts", &list); status != 200 {
+		t.Fatalf("list: %d", status)
+	}
+	thumbs := map[int]string{}
+	for _, p := range list.Data {
+		thumbs[p.ID] = p.Thumbnail
+	}
+	for _, p := range []routes.PostDetail{attached, inline} {
+		prefix := "/api/gallery/" + strconv.Itoa(p.ID) + "/thumbnail?size=202x150&v="
+		if !strings.HasPrefix(thumbs[p.ID], prefix) {
+			t.Errorf("post %d thumbnail = %q", p.ID, thumbs[p.ID])
+		}
+	}
+	if thumbs[text.ID] != "" {
+		t.Errorf("text post thumbnail = %q", thumbs[text.ID])
+	}
+
+	// 다른 게시판 목록에는 없음
+	var free routes.Envelope[[]routes.PostSummary]
+	h.GetJSON("/api/v1/boards/free/posts", &free)
+	for _, p := range free.Data {
+		if p.Thumbnail != "" {
+			t.Errorf("free post %d thumbnail = %q", p.ID, p.Thumbnail)
+		}
+	}
+
+	guest := h.NewClient()
+	for _, p := range []routes.PostDetail{attached, inline} {
+		resp, body := getThumbnail(t, guest, thumbs[p.ID])
+		if resp.StatusCode != 200 {
+			t.Fatalf("thumbnail %d: %d %s", p.ID, resp.StatusCode, body)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "image/png" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if cc := resp.Header.Get("Cache-Control"); !strings.Contains(cc, "max-age=31536000") {
+			t.Errorf("Cache-Control = %q", cc)
+		}
+		cfg, _, err := image.DecodeConfig(strings.NewReader(string(body)))
+		if err != nil || cfg.Width != 202 || cfg.Height != 150 {
+			t.Errorf("thumbnail %d = %dx%d %v", p.ID, cfg.Width, cfg.Height, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(editorDir, "thumb-inline_202x150.png")); err != nil {
+		t.Errorf("cached thumbnail: %v", err)
+	}
+
+	base := "/api/gallery/" + strconv.Itoa(inline.ID) + "/thumbnail"
+	tests := []struct {
+		path string
+		want int
+	}{
+		{base + "?size=100x100", 200}, // THUMBNAIL_SIZES
+		{base + "?size=50x50", 400},
+		{base + "?size=big", 400},
+		{"/api/gallery/" + strconv.Itoa(text.ID) + "/thumbnail", 404},
+		{"/api/gallery/999/thumbnail", 404},
+		{"/api/free/1/thumbnail", 404},
+	}
+	for _, tt := range tests {
+		if resp, body := getThumbnail(t, guest, tt.path); resp.StatusCode != tt.want {
+			t.Errorf("%s: %d %s, want %d", tt.path, resp.StatusCode, body, tt.want)
+		}
+	}
+
+	// SSR 목록은 카드로 썸네일을 보여줌
+	resp, page := getThumbnail(t, guest, "/gallery")
+	if resp.StatusCode != 200 || !strings.Contains(string(page), `src="`+strings.ReplaceAll(thumbs[inline.ID], "&", "&amp;")+`"`) {
+		t.Errorf("SSR gallery: %d", resp.StatusCode)
+	}
+
+	// 글을 지우면 썸네일도 지움
+	if status := user1.JSON(http.MethodDelete, "/api/gallery/"+strconv.Itoa(inline.ID), nil, nil); status != 204 {
+		t.Fatalf("delete: %d", status)
+	}
+	for _, name := range []string{"thumb-inline_202x150.png", "thumb-inline_100x100.png"} {
+		if _, err := os.Stat(filepath.Join(editorDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s left after delete: %v", name, err)
+		}
+	}
+	if status := user1.JSON(http.MethodDelete, "/api/gallery/"+strconv.Itoa(attached.ID), nil, nil); status != 204 {
+		t.Fatalf("delete: %d", status)
+	}
+	if stored := storedFiles(t, h, "gallery"); len(stored) != 0 {
+		t.Errorf("after delete: %v", stored)
+	}
+}
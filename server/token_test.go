@@ -0,0 +1,224 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"fibergo/jwt"
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func newTokenHarness(t *testing.T, access time.Duration) *servertest.Harness {
+	t.Helper()
+	h := servertest.New(t, servertest.DefaultFixture())
+	key, err := jwt.NewHS256Key("test", bytes.Repeat([]byte("s"), 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := jwt.NewKeySet("test", key)
+	routes.InitTokens(keys, h.Store, access, time.Hour)
+	t.Cleanup(func() { routes.InitTokens(nil, nil, 15*time.Minute, 14*24*time.Hour) })
+	return h
+}
+
+// withBearer sends a JSON request with an Authorization header
+func withBearer(h *servertest.Harness, method, path, token string, body, v interface{}) int {
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp := h.Do(req)
+	defer resp.Body.Close()
+	if v != nil {
+		json.NewDecoder(resp.Body).Decode(v)
+	}
+	return resp.StatusCode
+}
+
+func issue(t *testing.T, h *servertest.Harness, id, password string) routes.TokenPair {
+	t.Helper()
+	var body routes.Envelope[routes.TokenPair]
+	if status := h.JSON(http.MethodPost, "/api/auth/token", routes.LoginRequest{ID: id, Password: password}, &body); status != 200 {
+		t.Fatalf("token status = %d", status)
+	}
+	return body.Data
+}
+
+func TestTokenIssueAndUse(t *testing.T) {
+	h := newTokenHarness(t, time.Minute)
+	pair := issue(t, h, "admin", "admin1234")
+	if pair.TokenType != "Bearer" || pair.ExpiresIn != 60 || pair.AccessToken == "" || pair.RefreshToken == "" {
+		t.Fatalf("pair = %+v", pair)
+	}
+
+	var me routes.Envelope[routes.MemberInfo]
+	if status := withBearer(h, http.MethodGet, "/api/me", pair.AccessToken, nil, &me); status != 200 || me.Data.ID != "admin" {
+		t.Errorf("me: %d %+v", status, me.Data)
+	}
+
+	// refresh 토큰은 API 호출에 쓸 수 없음, 잘못된 토큰은 어느 /api 경로에서도 거부
+	tests := []struct {
+		path, token, code string
+	}{
+		{"/api/me", pair.RefreshToken, routes.ErrCodeInvalidToken},
+		{"/api/v1/boards", "not.a.token", routes.ErrCodeInvalidToken},
+		{"/api/free", pair.AccessToken + "x", routes.ErrCodeInvalidToken},
+	}
+	for _, tt := range tests {
+		var body routes.ErrorEnvelope
+		if status := withBearer(h, http.MethodGet, tt.path, tt.token, nil, &body); status != 401 || body.Error.Code != tt.code {
+			t.Errorf("%s: %d %q", tt.path, status, body.Error.Code)
+		}
+	}
+
+	var errBody routes.ErrorEnvelope
+	h.JSON(http.MethodPost, "/api/auth/token", routes.LoginRequest{ID: "blocked", Password: "blocked1234"}, &errBody)
+	if errBody.Error.Code != routes.ErrCodeMemberIntercepted {
+		t.Errorf("blocked member: %q", errBody.Error.Code)
+	}
+}
+
+func TestTokenExpired(t *testing.T) {
+	h := newTokenHarness(t, time.Nanosecond)
+	pair := issue(t, h, "user1", "user1pass")
+
+	var body routes.ErrorEnvelope
+	if status := withBearer(h, http.MethodGet, "/api/me", pair.AccessToken, nil, &body); status != 401 || body.Error.Code != routes.ErrCodeTokenExpired {
+		t.Errorf("expired: %d %q", status, body.Error.Code)
+	}
+}
+
+func TestTokenRefreshRotation(t *testing.T) {
+	h := newTokenHarness(t, time.Minute)
+	pair := issue(t, h, "user1", "user1pass")
+
+	var next routes.Envelope[routes.TokenPair]
+	if status := h.JSON(http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: pair.RefreshToken}, &next); status != 200 {
+		t.Fatalf("refresh status = %d", status)
+	}
+	if next.Data.RefreshToken == pair.RefreshToken {
+		t.Error("refresh token not rotated")
+	}
+
+	// 사용한 refresh 토큰, access 토큰으로는 재발급 불가
+	for _, token := range []string{pair.RefreshToken, pair.AccessToken} {
+		var body routes.ErrorEnvelope
+		if status := h.JSON(http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: token}, &body); status != 401 || body.Error.Code != routes.ErrCodeInvalidToken {
+			t.Errorf("reuse: %d %q", status, body.Error.Code)
+		}
+	}
+}
+
+func TestTokenRefreshConcurrent(t *testing.T) {
+	h := newTokenHarness(t, time.Minute)
+	pair := issue(t, h, "user1", "user1pass")
+
+	// 같은 refresh 토큰을 동시에 써도 새 토큰은 한 번만 발급
+	statuses := make(chan int, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(statuses); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var body routes.ErrorEnvelope
+			status := h.JSON(http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: pair.RefreshToken}, &body)
+			if status != 200 && body.Error.Code != routes.ErrCodeInvalidToken {
+				t.Errorf("refresh: %d %q", status, body.Error.Code)
+			}
+			statuses <- status
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+	ok := 0
+	for status := range statuses {
+		if status == 200 {
+			ok++
+		}
+	}
+	if ok != 1 {
+		t.Errorf("refreshed %d times, want 1", ok)
+	}
+}
+
+func TestTokenRevocation(t *testing.T) {
+	h := newTokenHarness(t, time.Minute)
+	pair := issue(t, h, "user1", "user1pass")
+
+	if status := withBearer(h, http.MethodPost, "/api/auth/logout", pair.AccessToken, routes.RefreshRequest{RefreshToken: pair.RefreshToken}, nil); status != 204 {
+		t.Fatalf("logout status = %d", status)
+	}
+	if status := withBearer(h, http.MethodGet, "/api/me", pair.AccessToken, nil, nil); status != 401 {
+		t.Errorf("revoked access token: %d", status)
+	}
+	if status := h.JSON(http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: pair.RefreshToken}, nil); status != 401 {
+		t.Errorf("revoked refresh token: %d", status)
+	}
+}
+
+func TestTokenPurge(t *testing.T) {
+	h := newTokenHarness(t, time.Minute)
+	pair := issue(t, h, "user1", "user1pass")
+	if status := withBearer(h, http.MethodPost, "/api/auth/logout", pair.AccessToken, routes.RefreshRequest{RefreshToken: pair.RefreshToken}, nil); status != 204 {
+		t.Fatalf("logout status = %d", status)
+	}
+	ctx := context.Background()
+	h.Store.RevokeToken(ctx, "old", "user1", time.Now().Add(-time.Minute))
+
+	// 만료된 항목만 지우고, 아직 유효한 폐기 토큰은 계속 거부
+	if err := h.Store.PurgeTokens(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := h.Store.IsTokenRevoked(ctx, "old"); revoked {
+		t.Error("expired entry not purged")
+	}
+	if status := h.JSON(http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: pair.RefreshToken}, nil); status != 401 {
+		t.Errorf("revoked refresh token after purge: %d", status)
+	}
+}
+
+func TestTokenBoundToLevel(t *testing.T) {
+	h := newTokenHarness(t, time.Minute)
+	pair := issue(t, h, "user2", "user2pass")
+
+	// 발급 후 레벨이 바뀌면 access 토큰은 거부, refresh 로 새 레벨의 토큰 발급
+	m, _ := h.Store.GetMember(context.Background(), "user2")
+	promoted := *m
+	promoted.Level = 5
+	h.Store.AddMember(promoted)
+
+	if status := withBearer(h, http.MethodGet, "/api/me", pair.AccessToken, nil, nil); status != 401 {
+		t.Errorf("stale level: %d", status)
+	}
+	var next routes.Envelope[routes.TokenPair]
+	h.JSON(http.MethodPost, "/api/auth/refresh", routes.RefreshRequest{RefreshToken: pair.RefreshToken}, &next)
+	var me routes.Envelope[routes.MemberInfo]
+	if status := withBearer(h, http.MethodGet, "/api/me", next.Data.AccessToken, nil, &me); status != 200 || me.Data.Level != 5 {
+		t.Errorf("refreshed: %d %+v", status, me.Data)
+	}
+}
+
+func TestTokensDisabled(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.ErrorEnvelope
+	if status := h.JSON(http.MethodPost, "/api/auth/token", routes.LoginRequest{ID: "admin", Password: "admin1234"}, &body); status != 503 || body.Error.Code != routes.ErrCodeTokensDisabled {
+		t.Errorf("token: %d %q", status, body.Error.Code)
+	}
+	if status := withBearer(h, http.MethodGet, "/api/v1/boards", "x.y.z", nil, nil); status != 401 {
+		t.Errorf("bearer without keys: %d", status)
+	}
+}
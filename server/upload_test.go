@@ -0,0 +1,264 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+// 파일 이름이 없으면 일반 필드
+type formPart struct {
+	field, filename, content string
+}
+
+func upload(t *testing.T, cl *servertest.Client, path string, parts ...formPart) (int, []byte) {
+	t.Helper()
+	resp := cl.Do(uploadRequest(t, path, parts...))
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, body
+}
+
+// uploadRequest builds a multipart POST of the parts
+func uploadRequest(t *testing.T, path string, parts ...formPart) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		var err error
+		if p.filename == "" && p.field != "bf_file[]" {
+			err = w.WriteField(p.field, p.content)
+		} else {
+			var fw io.Writer
+			if fw, err = w.CreateFormFile(p.field, p.filename); err == nil {
+				_, err = io.WriteString(fw, p.content)
+			}
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	req := httptest.NewRequest(http.MethodPost, path, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func pngImage(t *testing.T, width, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func storedFiles(t *testing.T, h *servertest.Harness, table string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(h.DataDir, "file", table))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+var storedNamePattern = regexp.MustCompile(`^[0-9a-f]{32}_[A-Za-z0-9]{8}_[0-9a-f]{40}\.(png|txt)$`)
+
+func TestUploadFiles(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+	post := createPost(t, user1, "free", routes.PostRequest{Subject: "첨부", Content: "본문"})
+	path := "/api/free/" + strconv.Itoa(post.ID) + "/files"
+	photo := pngImage(t, 3, 2)
+
+	status, raw := upload(t, user1, path,
+		formPart{"bf_file[]", "사진.png", photo},
+		formPart{"bf_file[]", "", ""}, // 고르지 않은 칸
+		formPart{"bf_file[]", `C:\docs\memo.txt`, "메모"},
+		formPart{"bf_content[]", "", " 풍경 사진 "},
+		formPart{"bf_content[]", "", ""},
+		formPart{"bf_content[]", "", "메모 설명"},
+	)
+	if status != 201 {
+		t.Fatalf("upload: %d %s", status, raw)
+	}
+	var body routes.Envelope[[]routes.Attachment]
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatal(err)
+	}
+	want := []routes.Attachment{
+		{No: 0, Name: "사진.png", Description: "풍경 사진", Size: int64(len(photo)), Width: 3, Height: 2, IsImage: true, URL: path + "/0"},
+		{No: 1, Name: "memo.txt", Description: "메모 설명", Size: int64(len("메모")), URL: path + "/1"},
+	}
+	if len(body.Data) != 2 || body.Data[0] != want[0] || body.Data[1] != want[1] {
+		t.Fatalf("attachments = %+v", body.Data)
+	}
+
+	stored := storedFiles(t, h, "free")
+	if len(stored) != 2 {
+		t.Fatalf("stored = %v", stored)
+	}
+	for _, name := range stored {
+		if !storedNamePattern.MatchString(name) {
+			t.Errorf("stored name %q", name)
+		}
+	}
+	p, _ := h.Store.GetPost(context.Background(), "free", post.ID)
+	if p.File != 2 {
+		t.Errorf("wr_file = %d", p.File)
+	}
+	resp, downloaded := download(t, user1, path+"/0", "")
+	if resp.StatusCode != 200 || downloaded != photo {
+		t.Errorf("download: %d", resp.StatusCode)
+	}
+
+	// bo_upload_count 3: 한 개만 더 올릴 수 있음
+	var errBody routes.ErrorEnvelope
+	status, raw = upload(t, user1, path, formPart{"bf_file[]", "a.txt", "a"}, formPart{"bf_file[]", "b.txt", "b"})
+	json.Unmarshal(raw, &errBody)
+	if status != 409 || errBody.Error.Code != routes.ErrCodeTooManyFiles {
+		t.Errorf("too many: %d %s", status, raw)
+	}
+	if n := len(storedFiles(t, h, "free")); n != 2 {
+		t.Errorf("files left after rejected upload: %d", n)
+	}
+
+	// 글을 지우면 파일도 지움
+	if status := user1.JSON(http.MethodDelete, "/api/free/"+strconv.Itoa(post.ID), nil, nil); status != 204 {
+		t.Fatalf("delete: %d", status)
+	}
+	if stored := storedFiles(t, h, "free"); len(stored) != 0 {
+		t.Errorf("after delete: %v", stored)
+	}
+}
+
+func TestUploadGuestPost(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	guest := h.NewClient()
+	post := createPost(t, guest, "free", routes.PostRequest{Subject: "손님 글", Content: "본문", Name: "나그네", Password: "pw1234"})
+	path := "/api/free/" + strconv.Itoa(post.ID) + "/files"
+
+	// 비밀번호를 확인하기 전에는 파일을 디스크에 쓰지 않음
+	for name, parts := range map[string][]formPart{
+		"틀린 비밀번호":     {{"wr_password", "", "wrong"}, {"bf_file[]", "a.txt", "a"}},
+		"파일 뒤의 비밀번호":  {{"bf_file[]", "a.txt", "a"}, {"wr_password", "", "pw1234"}},
+		"비밀번호 없음":     {{"bf_file[]", "a.txt", "a"}},
+		"비밀번호만 있는 본문": {{"wr_password", "", "wrong"}},
+	} {
+		var errBody routes.ErrorEnvelope
+		status, raw := upload(t, guest, path, parts...)
+		json.Unmarshal(raw, &errBody)
+		if status != 403 || errBody.Error.Code != routes.ErrCodeInvalidPassword {
+			t.Errorf("%s: %d %s", name, status, raw)
+		}
+		if stored := storedFiles(t, h, "free"); len(stored) != 0 {
+			t.Errorf("%s: stored %v", name, stored)
+		}
+	}
+
+	if status, raw := upload(t, guest, path, formPart{"wr_password", "", "pw1234"}, formPart{"bf_file[]", "a.txt", "a"}); status != 201 {
+		t.Errorf("upload: %d %s", status, raw)
+	}
+	// 비밀번호는 헤더로도 보낼 수 있음
+	headerUpload := func(password string) int {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		fw, _ := w.CreateFormFile("bf_file[]", "b.txt")
+		io.WriteString(fw, "b")
+		w.Close()
+		req := httptest.NewRequest(http.MethodPost, path, &buf)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		req.Header.Set("X-Wr-Password", password)
+		resp := guest.Do(req)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if status := headerUpload("wrong"); status != 403 {
+		t.Errorf("wrong header password: %d", status)
+	}
+	if status := headerUpload("pw1234"); status != 201 {
+		t.Errorf("header password: %d", status)
+	}
+	if stored := storedFiles(t, h, "free"); len(stored) != 2 {
+		t.Errorf("stored = %v, want 2 files", stored)
+	}
+}
+
+func TestUploadRejected(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+	post := createPost(t, user1, "free", routes.PostRequest{Subject: "첨부", Content: "본문"})
+	path := "/api/free/" + strconv.Itoa(post.ID) + "/files"
+
+	tests := []struct {
+		name  string
+		who   *servertest.Client
+		path  string
+		parts []formPart
+		want  int
+		code  string
+	}{
+		{"이름만 바꾼 HTML", user1, path, []formPart{{"bf_file[]", "x.jpg", "<html><script>alert(1)</script></html>"}}, 415, routes.ErrCodeFileType},
+		{"PNG 가 아닌 .png", user1, path, []formPart{{"bf_file[]", "x.png", "plain text"}}, 415, routes.ErrCodeFileType},
+		{"허용하지 않는 확장자", user1, path, []formPart{{"bf_file[]", "run.php", "<?php echo 1;"}}, 415, routes.ErrCodeFileType},
+		{"bo_upload_size 초과", user1, path, []formPart{{"bf_file[]", "big.txt", strings.Repeat("a", 1025)}}, 413, routes.ErrCodeFileTooLarge},
+		{"파일 없음", user1, path, []formPart{{"bf_content[]", "", "설명"}}, 422, routes.ErrCodeValidation},
+		{"다른 회원의 글", h.Login("user2", "user2pass"), path, []formPart{{"bf_file[]", "a.txt", "a"}}, 403, routes.ErrCodeForbidden},
+		{"손님", h.NewClient(), path, []formPart{{"bf_file[]", "a.txt", "a"}}, 401, routes.ErrCodeUnauthorized},
+		{"업로드 안 하는 게시판", user1, "/api/notice/1/files", []formPart{{"bf_file[]", "a.txt", "a"}}, 403, routes.ErrCodeUploadDisabled},
+		{"없는 글", user1, "/api/free/999/files", []formPart{{"bf_file[]", "a.txt", "a"}}, 404, routes.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		var errBody routes.ErrorEnvelope
+		status, raw := upload(t, tt.who, tt.path, tt.parts...)
+		json.Unmarshal(raw, &errBody)
+		if status != tt.want || errBody.Error.Code != tt.code {
+			t.Errorf("%s: %d %s, want %d %s", tt.name, status, raw, tt.want, tt.code)
+		}
+	}
+	if stored := storedFiles(t, h, "free"); len(stored) != 0 {
+		t.Errorf("rejected files left on disk: %v", stored)
+	}
+
+	var errBody routes.ErrorEnvelope
+	if status := user1.JSON(http.MethodPost, path, map[string]string{"bf_file": "a"}, &errBody); status != 400 || errBody.Error.Code != routes.ErrCodeInvalidRequest {
+		t.Errorf("JSON body: %d %+v", status, errBody)
+	}
+}
+
+// 본문을 스트리밍해도 첨부파일이 아닌 요청은 BodyLimit 로 제한
+func TestBodyLimit(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/free", strings.NewReader(`{"subject": "`+strings.Repeat("a", 4<<20)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := user1.Do(req)
+	resp.Body.Close()
+	if resp.StatusCode != 413 {
+		t.Errorf("large body: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,143 @@
+package server_test
+
+import (
+	"testing"
+	"time"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func TestV1Posts(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.Envelope[[]routes.PostSummary]
+	if status := h.GetJSON("/api/v1/boards/free/posts?page=2&per_page=2", &body); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	if body.Meta == nil || body.Meta.Page != 2 || body.Meta.PerPage != 2 || body.Meta.Total == nil || *body.Meta.Total != 5 {
+		t.Fatalf("meta = %+v", body.Meta)
+	}
+	var ids []int
+	for _, p := range body.Data {
+		ids = append(ids, p.ID)
+	}
+	if !equalInts(ids, []int{2, 1}) {
+		t.Errorf("ids = %v, want [2 1]", ids)
+	}
+	if body.Data[1].Author.Name != "홍길동" || body.Data[1].BoardID != "free" {
+		t.Errorf("post = %+v", body.Data[1])
+	}
+}
+
+func TestV1PostDetail(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.Envelope[routes.PostDetail]
+	if status := h.GetJSON("/api/v1/boards/free/posts/1", &body); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	want := time.Date(2025, 1, 1, 9, 0, 0, 0, time.Local)
+	if !body.Data.CreatedAt.Equal(want) {
+		t.Errorf("created_at = %v, want %v", body.Data.CreatedAt, want)
+	}
+	if body.Data.ContentText != "안녕하세요" || body.Data.Source != "" || body.Data.Links == nil {
+		t.Errorf("post = %+v", body.Data)
+	}
+}
+
+func TestV1Errors(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/api/v1/boards/nope/posts", 400, routes.ErrCodeInvalidBoard},
+		{"/api/v1/boards/free/posts/abc", 400, routes.ErrCodeInvalidID},
+		{"/api/v1/boards/free/posts/999", 404, routes.ErrCodeNotFound},
+		{"/api/v1/unknown", 404, routes.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		var body routes.ErrorEnvelope
+		if status := h.GetJSON(tt.path, &body); status != tt.status || body.Error.Code != tt.code {
+			t.Errorf("GET %s: %d %q, want %d %q", tt.path, status, body.Error.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestV1EmptyComments(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body map[string]interface{}
+	h.GetJSON("/api/v1/boards/free/posts/2/comments", &body)
+	if data, ok := body["data"].([]interface{}); !ok || len(data) != 0 {
+		t.Errorf("data = %#v, want []", body["data"])
+	}
+}
+
+func TestV1CommentThread(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var body routes.Envelope[[]routes.CommentItem]
+	h.GetJSON("/api/v1/boards/free/posts/1/comments?per_page=3", &body)
+	if len(body.Data) != 3 || *body.Meta.Total != 4 {
+		t.Fatalf("body = %+v", body)
+	}
+	if c := body.Data[0]; c.Depth != 0 || c.ParentCommentID != nil {
+		t.Errorf("root = %+v", c)
+	}
+	if c := body.Data[2]; c.ID != 14 || c.Depth != 2 || c.ParentCommentID == nil || *c.ParentCommentID != 12 {
+		t.Errorf("reply = %+v", c)
+	}
+}
+
+func TestV1CursorPaging(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+
+	var ids []int
+	path := "/api/v1/boards/free/posts?per_page=2&count=false&cursor="
+	for pages := 0; pages < 5; pages++ {
+		var body routes.Envelope[[]routes.PostSummary]
+		if status := h.GetJSON(path, &body); status != 200 {
+			t.Fatalf("GET %s: status = %d", path, status)
+		}
+		if body.Meta.Total != nil || body.Meta.Page != 0 {
+			t.Errorf("GET %s: meta = %+v, want no total/page", path, body.Meta)
+		}
+		for _, p := range body.Data {
+			ids = append(ids, p.ID)
+		}
+		if body.Meta.NextCursor == "" {
+			break
+		}
+		path = "/api/v1/boards/free/posts?per_page=2&count=false&cursor=" + body.Meta.NextCursor
+	}
+	if !equalInts(ids, []int{5, 3, 2, 1, 4}) {
+		t.Errorf("ids = %v", ids)
+	}
+
+	var bad routes.ErrorEnvelope
+	if status := h.GetJSON("/api/v1/boards/free/posts?cursor=%%%", &bad); status != 400 || bad.Error.Code != routes.ErrCodeInvalidCursor {
+		t.Errorf("bad cursor: %d %+v", status, bad)
+	}
+}
+
+func TestPageSizeClamped(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	routes.SetMaxPageSize(2)
+	defer routes.SetMaxPageSize(100)
+
+	var body routes.Envelope[[]routes.PostSummary]
+	h.GetJSON("/api/v1/boards/free/posts?per_page=100000", &body)
+	if body.Meta.PerPage != 2 || len(body.Data) != 2 {
+		t.Errorf("per_page = %d, %d posts", body.Meta.PerPage, len(body.Data))
+	}
+
+	var legacy legacyList
+	h.GetJSON("/api/free?limit=100000", &legacy)
+	if len(legacy.Posts) != 2 {
+		t.Errorf("legacy limit not clamped: %d posts", len(legacy.Posts))
+	}
+}
@@ -0,0 +1,76 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+// free/1 은 user1 의 글 (wr_good 1, user2 가 이미 추천)
+func TestVote(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	olduser := h.Login("olduser", "oldpass")
+
+	var body routes.Envelope[routes.VoteResult]
+	if status := olduser.JSON(http.MethodPost, "/api/free/1/good", nil, &body); status != 200 {
+		t.Fatalf("good: %d", status)
+	}
+	if body.Data != (routes.VoteResult{Recommends: 2, Unrecommends: 0, MyVote: "good"}) {
+		t.Errorf("result = %+v", body.Data)
+	}
+
+	// 추천과 비추천을 합쳐 한 번만
+	var errBody routes.ErrorEnvelope
+	for _, path := range []string{"/api/free/1/good", "/api/free/1/nogood"} {
+		errBody = routes.ErrorEnvelope{}
+		if status := olduser.JSON(http.MethodPost, path, nil, &errBody); status != 409 || errBody.Error.Code != routes.ErrCodeAlreadyVoted {
+			t.Errorf("%s again: %d %+v", path, status, errBody)
+		}
+	}
+
+	var detail routes.Envelope[routes.PostDetail]
+	olduser.JSON(http.MethodGet, "/api/v1/boards/free/posts/1", nil, &detail)
+	if detail.Data.Recommends != 2 || detail.Data.MyVote != "good" {
+		t.Errorf("detail = %+v", detail.Data)
+	}
+	h.Login("user2", "user2pass").JSON(http.MethodGet, "/api/v1/boards/free/posts/1", nil, &detail)
+	if detail.Data.MyVote != "good" {
+		t.Errorf("user2 my_vote = %q", detail.Data.MyVote)
+	}
+	detail = routes.Envelope[routes.PostDetail]{}
+	h.JSON(http.MethodGet, "/api/v1/boards/free/posts/1", nil, &detail)
+	if detail.Data.MyVote != "" {
+		t.Errorf("guest my_vote = %q", detail.Data.MyVote)
+	}
+
+	if status := olduser.JSON(http.MethodPost, "/api/free/2/nogood", nil, &body); status != 200 || body.Data.Unrecommends != 1 {
+		t.Errorf("nogood: %d %+v", status, body.Data)
+	}
+}
+
+func TestVoteDenied(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	tests := []struct {
+		who  *servertest.Client
+		path string
+		want int
+		code string
+	}{
+		{h.NewClient(), "/api/free/2/good", 401, routes.ErrCodeUnauthorized},
+		{user1, "/api/free/1/good", 403, routes.ErrCodeOwnPost},
+		{user1, "/api/notice/1/good", 403, routes.ErrCodeVoteDisabled},
+		{user1, "/api/qna/2/nogood", 403, routes.ErrCodeVoteDisabled}, // qna 는 추천만 사용
+		{h.Login("user2", "user2pass"), "/api/qna/1/good", 403, routes.ErrCodeSecretPost},
+		{user1, "/api/free/999/good", 404, routes.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		var errBody routes.ErrorEnvelope
+		if status := tt.who.JSON(http.MethodPost, tt.path, nil, &errBody); status != tt.want || errBody.Error.Code != tt.code {
+			t.Errorf("POST %s: %d %q, want %d %q", tt.path, status, errBody.Error.Code, tt.want, tt.code)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fibergo/routes"
+	"fibergo/server/servertest"
+)
+
+func createPost(t *testing.T, cl *servertest.Client, table string, req routes.PostRequest) routes.PostDetail {
+	t.Helper()
+	var body routes.Envelope[routes.PostDetail]
+	if status := cl.JSON(http.MethodPost, "/api/"+table, req, &body); status != 201 {
+		t.Fatalf("POST /api/%s: %d", table, status)
+	}
+	return body.Data
+}
+
+func TestCreatePost(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	created := createPost(t, user1, "free", routes.PostRequest{Subject: " 새 글 ", Content: "본문"})
+	if created.ID != 15 || created.Subject != "새 글" || created.Author.Name != "길동" {
+		t.Errorf("created = %+v", created)
+	}
+	p, err := h.Store.GetPost(context.Background(), "free", created.ID)
+	if err != nil || p.Num != -5 || p.Reply != "" || p.Parent != p.ID || p.MemberID != "user1" {
+		t.Fatalf("stored = %+v, %v", p, err)
+	}
+
+	// bo_write_point 는 한 번만 지급
+	points := h.Store.Points("user1")
+	if len(points) != 1 || points[0].Point != 5 || points[0].RelAction != "쓰기" || points[0].RelID != strconv.Itoa(p.ID) {
+		t.Errorf("points = %+v", points)
+	}
+
+	// 목록 맨 앞에 옴
+	var list routes.Envelope[[]routes.PostSummary]
+	h.GetJSON("/api/v1/boards/free/posts", &list)
+	if len(list.Data) == 0 || list.Data[0].ID != created.ID {
+		t.Errorf("list = %+v", list.Data)
+	}
+}
+
+func TestCrossSiteWrite(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+	before, _ := h.Store.CountPosts(context.Background(), "free")
+
+	// 다른 사이트의 폼이 로그인 쿠키를 달고 보낸 글쓰기
+	post := func(header, value string) int {
+		form := url.Values{"wr_subject": {"가짜 글"}, "wr_content": {"본문"}}
+		req := httptest.NewRequest(http.MethodPost, "/api/free", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Accept", "application/json")
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		resp := user1.Do(req)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for _, tc := range []struct{ header, value string }{
+		{"Origin", "https://evil.example"},
+		{"Origin", "null"},
+		{"Sec-Fetch-Site", "cross-site"},
+	} {
+		if status := post(tc.header, tc.value); status != 403 {
+			t.Errorf("%s: %s = %d, want 403", tc.header, tc.value, status)
+		}
+	}
+	if n, _ := h.Store.CountPosts(context.Background(), "free"); n != before {
+		t.Errorf("posts = %d, want %d", n, before)
+	}
+
+	// 같은 사이트에서 보낸 폼, 브라우저가 아닌 클라이언트는 그대로 처리
+	for _, tc := range []struct{ header, value string }{
+		{"Origin", "http://example.com"},
+		{"Sec-Fetch-Site", "same-origin"},
+		{"", ""},
+	} {
+		if status := post(tc.header, tc.value); status != 201 {
+			t.Errorf("%s: %s = %d, want 201", tc.header, tc.value, status)
+		}
+	}
+}
+
+func TestCreateGuestPost(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	guest := h.NewClient()
+
+	var errBody routes.ErrorEnvelope
+	status := guest.JSON(http.MethodPost, "/api/free", routes.PostRequest{Content: "본문", Format: "markdown"}, &errBody)
+	if status != 422 || errBody.Error.Code != routes.ErrCodeValidation {
+		t.Fatalf("invalid: %d %+v", status, errBody)
+	}
+	for _, field := range []string{"subject", "format", "name", "password"} {
+		if errBody.Error.Fields[field] == "" {
+			t.Errorf("fields[%s] missing: %+v", field, errBody.Error.Fields)
+		}
+	}
+
+	created := createPost(t, guest, "free", routes.PostRequest{
+		Subject: "손님 글", Content: "본문", Name: "나그네", Password: "pw1234", Secret: true,
+	})
+	p, _ := h.Store.GetPost(context.Background(), "free", created.ID)
+	if p.Name != "나그네" || p.Password == "" || p.Password == "pw1234" {
+		t.Errorf("stored = %+v", p)
+	}
+	// free 게시판은 bo_use_secret 0 이라 비밀글 옵션 무시
+	if p.HasOption("secret") {
+		t.Errorf("option = %q", p.Option)
+	}
+
+	// 글쓰기 권한 없는 게시판
+	status = guest.JSON(http.MethodPost, "/api/gallery", routes.PostRequest{Subject: "s", Content: "c", Name: "n", Password: "p"}, &errBody)
+	if status != 401 || errBody.Error.Code != routes.ErrCodeUnauthorized {
+		t.Errorf("gallery: %d %+v", status, errBody)
+	}
+}
+
+func TestCreateReply(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	// free/1 에는 이미 A 답변이 있음
+	first := createPost(t, user1, "free", routes.PostRequest{Subject: "Re", Content: "답변", ReplyTo: 1})
+	nested := createPost(t, user1, "free", routes.PostRequest{Subject: "Re Re", Content: "답변의 답변", ReplyTo: 4})
+	for id, want := range map[int]string{first.ID: "B", nested.ID: "AA"} {
+		p, _ := h.Store.GetPost(context.Background(), "free", id)
+		if p.Num != -1 || p.Reply != want {
+			t.Errorf("free/%d: num %d reply %q, want -1 %q", id, p.Num, p.Reply, want)
+		}
+	}
+
+	// 비밀글의 답변은 비밀글
+	reply := createPost(t, user1, "qna", routes.PostRequest{Subject: "추가 질문", Content: "본문", ReplyTo: 1})
+	if !reply.IsSecret {
+		t.Errorf("qna reply = %+v", reply)
+	}
+
+	// 읽을 수 없는 비밀글에는 답변 불가
+	user2 := h.Login("user2", "user2pass")
+	var errBody routes.ErrorEnvelope
+	status := user2.JSON(http.MethodPost, "/api/qna", routes.PostRequest{Subject: "s", Content: "c", ReplyTo: 1}, &errBody)
+	if status != 403 || errBody.Error.Code != routes.ErrCodeSecretPost {
+		t.Errorf("secret parent: %d %+v", status, errBody)
+	}
+	if status := user2.JSON(http.MethodPost, "/api/free", routes.PostRequest{Subject: "s", Content: "c", ReplyTo: 999}, nil); status != 404 {
+		t.Errorf("missing parent: %d", status)
+	}
+}
+
+func TestUpdatePost(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	req := routes.PostRequest{Subject: "고친 제목", Content: "고친 본문"}
+
+	var errBody routes.ErrorEnvelope
+	tests := []struct {
+		who  *servertest.Client
+		path string
+		req  routes.PostRequest
+		want int
+		code string
+	}{
+		{h.NewClient(), "/api/free/3", req, 401, routes.ErrCodeUnauthorized},
+		{h.Login("user2", "user2pass"), "/api/free/3", req, 403, routes.ErrCodeForbidden},
+		{h.NewClient(), "/api/free/5", req, 403, routes.ErrCodeInvalidPassword},
+		{h.Login("user1", "user1pass"), "/api/free/3", routes.PostRequest{Subject: "제목"}, 422, routes.ErrCodeValidation},
+		{h.NewClient(), "/api/free/999", req, 404, routes.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		errBody = routes.ErrorEnvelope{}
+		if status := tt.who.JSON(http.MethodPut, tt.path, tt.req, &errBody); status != tt.want || errBody.Error.Code != tt.code {
+			t.Errorf("PUT %s: %d %q, want %d %q", tt.path, status, errBody.Error.Code, tt.want, tt.code)
+		}
+	}
+
+	var body routes.Envelope[routes.PostDetail]
+	if status := h.Login("user1", "user1pass").JSON(http.MethodPut, "/api/free/3", req, &body); status != 200 || body.Data.Subject != "고친 제목" {
+		t.Fatalf("owner: %d %+v", status, body.Data)
+	}
+	p, _ := h.Store.GetPost(context.Background(), "free", 3)
+	if p.Subject != "고친 제목" || p.Content != "고친 본문" || p.Num != -3 {
+		t.Errorf("stored = %+v", p)
+	}
+
+	// 다른 사람 댓글이 bo_count_modify 개 이상이면 수정 불가
+	status := h.Login("user1", "user1pass").JSON(http.MethodPut, "/api/free/1", req, &errBody)
+	if status != 409 || errBody.Error.Code != routes.ErrCodeHasComments {
+		t.Errorf("commented: %d %+v", status, errBody)
+	}
+	if status := h.Login("admin", "admin1234").JSON(http.MethodPut, "/api/free/1", req, nil); status != 200 {
+		t.Errorf("admin: %d", status)
+	}
+}
+
+// 비밀글에 단 답변은 고쳐도 비밀글로 남고, 원글이 비밀글이 아니면 풀 수 있음
+func TestUpdateReplySecret(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	answer := createPost(t, user1, "qna", routes.PostRequest{Subject: "추가 질문", Content: "본문", ReplyTo: 1})
+	open := createPost(t, user1, "qna", routes.PostRequest{Subject: "공개 질문", Content: "본문"})
+	openAnswer := createPost(t, user1, "qna", routes.PostRequest{Subject: "답변", Content: "본문", ReplyTo: open.ID, Secret: true})
+
+	for _, tt := range []struct {
+		id   int
+		want bool
+	}{
+		{answer.ID, true},
+		{openAnswer.ID, false},
+	} {
+		var body routes.Envelope[routes.PostDetail]
+		path := "/api/qna/" + strconv.Itoa(tt.id)
+		if status := user1.JSON(http.MethodPut, path, routes.PostRequest{Subject: "고친 답변", Content: "본문", Secret: false}, &body); status != 200 {
+			t.Fatalf("PUT %s: %d", path, status)
+		}
+		p, _ := h.Store.GetPost(context.Background(), "qna", tt.id)
+		if body.Data.IsSecret != tt.want || p.HasOption("secret") != tt.want {
+			t.Errorf("PUT %s: is_secret %v, option %q, want secret %v", path, body.Data.IsSecret, p.Option, tt.want)
+		}
+	}
+}
+
+func TestDeletePost(t *testing.T) {
+	h := servertest.New(t, servertest.DefaultFixture())
+	user1 := h.Login("user1", "user1pass")
+
+	guestPost := createPost(t, h.NewClient(), "free", routes.PostRequest{Subject: "s", Content: "c", Name: "나그네", Password: "pw1234"})
+	path := "/api/free/" + strconv.Itoa(guestPost.ID)
+	var errBody routes.ErrorEnvelope
+	if status := h.JSON(http.MethodDelete, path, routes.DeleteRequest{Password: "wrong"}, &errBody); status != 403 || errBody.Error.Code != routes.ErrCodeInvalidPassword {
+		t.Errorf("wrong password: %d %+v", status, errBody)
+	}
+	if status := h.JSON(http.MethodDelete, path, routes.DeleteRequest{Password: "pw1234"}, nil); status != 204 {
+		t.Errorf("guest delete: %d", status)
+	}
+	if status := h.JSON(http.MethodGet, "/api/v1/boards/free/posts/"+strconv.Itoa(guestPost.ID), nil, nil); status != 404 {
+		t.Errorf("after delete: %d", status)
+	}
+
+	// 답변이 있는 글은 관리자만 삭제
+	status := user1.JSON(http.MethodDelete, "/api/free/1", nil, &errBody)
+	if status != 409 || errBody.Error.Code != routes.ErrCodeHasReplies {
+		t.Errorf("has replies: %d %+v", status, errBody)
+	}
+
+	// 글쓰기 포인트는 삭제하면 회수
+	created := createPost(t, user1, "free", routes.PostRequest{Subject: "s", Content: "c"})
+	if status := user1.JSON(http.MethodDelete, "/api/free/"+strconv.Itoa(created.ID), nil, nil); status != 204 {
+		t.Errorf("owner delete: %d", status)
+	}
+	if points := h.Store.Points("user1"); len(points) != 0 {
+		t.Errorf("points = %+v", points)
+	}
+
+	// 관리자가 지운 글의 댓글도 함께 삭제
+	admin := h.Login("admin", "admin1234")
+	if status := admin.JSON(http.MethodDelete, "/api/free/1", nil, nil); status != 204 {
+		t.Fatalf("admin delete: %d", status)
+	}
+	if n, _ := h.Store.CountComments(context.Background(), "free", 1); n != 0 {
+		t.Errorf("comments left: %d", n)
+	}
+}
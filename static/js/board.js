@@ -1,3 +1,10 @@
+// 제목, 이름 같은 글자를 HTML 에 넣기 전에 이스케이프
+function escapeHTML(value) {
+    return String(value ?? '').replace(/[&<>"']/g, ch => ({
+        '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;'
+    })[ch]);
+}
+
 // 게시글 동적 로딩
 async function loadPosts(page = 1) {
     try {
@@ -38,13 +45,14 @@ document.addEventListener('DOMContentLoaded', function() {
                 .then(response => response.json())
                 .then(post => {
                     const date = new Date(post.날짜).toLocaleString();
+                    // 내용은 서버에서 정리한 HTML, 나머지 글자는 이스케이프
                     boardContent.innerHTML = `
                         <div class="post-view">
                             <h1>${getBoardTitle(boardType)}</h1>
                             <div class="post-header">
-                                <h2 class="post-title">${post.제목}</h2>
+                                <h2 class="post-title">${escapeHTML(post.제목)}</h2>
                                 <div class="post-info">
-                                    <span class="author">작성자: ${post.이름}</span>
+                                    <span class="author">작성자: ${escapeHTML(post.이름)}</span>
                                     <span class="date">작성일: ${date}</span>
                                     <span class="views">조회: ${post.조회}</span>
                                     <span class="likes">추천: ${post.추천}</span>
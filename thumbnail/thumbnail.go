@@ -0,0 +1,280 @@
+// Package thumbnail makes Gnuboard style list thumbnails
+// (thumb-{name}_{w}x{h}.{ext} next to the original) in pure Go.
+package thumbnail
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 그누보드 G5_THUMB_JPG_QUALITY
+const jpegQuality = 90
+
+// 압축 폭탄 방지: 이보다 픽셀이 많은 원본은 디코딩하지 않음 (RGBA 로 64MB)
+const maxPixels = 4096 * 4096
+
+// 가로, 세로 최대 크기
+const maxSide = 2000
+
+// ErrTooLarge is returned for an original with more pixels than we decode
+var ErrTooLarge = errors.New("thumbnail: image too large")
+
+// ErrInvalidSize is returned by ParseSize for anything but WxH within 1..2000
+var ErrInvalidSize = errors.New("thumbnail: invalid size")
+
+// Size is the width and height of a thumbnail
+type Size struct {
+	Width  int
+	Height int
+}
+
+// String formats the size as WxH
+func (s Size) String() string {
+	return strconv.Itoa(s.Width) + "x" + strconv.Itoa(s.Height)
+}
+
+// ParseSize reads a WxH size such as 202x150
+func ParseSize(value string) (Size, error) {
+	w, h, ok := strings.Cut(strings.TrimSpace(value), "x")
+	if !ok {
+		return Size{}, ErrInvalidSize
+	}
+	width, err1 := strconv.Atoi(w)
+	height, err2 := strconv.Atoi(h)
+	if err1 != nil || err2 != nil || width < 1 || height < 1 || width > maxSide || height > maxSide {
+		return Size{}, ErrInvalidSize
+	}
+	return Size{Width: width, Height: height}, nil
+}
+
+// ParseSizes splits a comma separated list of sizes (THUMBNAIL_SIZES)
+func ParseSizes(value string) ([]Size, error) {
+	var sizes []Size
+	for _, v := range strings.Split(value, ",") {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		s, err := ParseSize(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", err, v)
+		}
+		sizes = append(sizes, s)
+	}
+	return sizes, nil
+}
+
+// 그누보드처럼 원본 형식을 유지 (gif 는 첫 프레임)
+var extensions = map[string]string{"jpeg": "jpg", "png": "png", "gif": "gif"}
+
+// Path is the cached thumbnail of src in the given format (jpeg, png, gif)
+func Path(src string, size Size, format string) string {
+	name := filepath.Base(src)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return filepath.Join(filepath.Dir(src), "thumb-"+name+"_"+size.String()+"."+extensions[format])
+}
+
+// Make returns the thumbnail of src cropped to fill size, creating it when
+// there is no cached file or the original is newer
+func Make(src string, size Size) (string, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := extensions[format]; !ok {
+		return "", image.ErrFormat
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return "", ErrTooLarge
+	}
+
+	path := Path(src, size, format)
+	if fresh(path, info) {
+		return path, nil
+	}
+	// 같은 썸네일을 동시에 요청하면 한 요청만 원본을 디코딩하고 나머지는 결과를 기다림
+	err = making.do(path, func() error {
+		if fresh(path, info) {
+			return nil
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		img, _, err := image.Decode(f)
+		if err != nil {
+			return err
+		}
+		return write(path, Cover(img, size), format)
+	})
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// fresh reports whether the cached thumbnail at path is not older than the original
+func fresh(path string, original os.FileInfo) bool {
+	cached, err := os.Stat(path)
+	return err == nil && !cached.ModTime().Before(original.ModTime())
+}
+
+// 만들고 있는 썸네일 (경로별 singleflight)
+var making flights
+
+type flights struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+	// 다른 요청의 결과를 기다려 받은 횟수
+	shared int
+}
+
+type flight struct {
+	done chan struct{}
+	err  error
+}
+
+// do runs fn for key unless a call for key is already running, in which
+// case it waits for that call and returns its error
+func (g *flights) do(key string, fn func() error) error {
+	g.mu.Lock()
+	if fl, ok := g.calls[key]; ok {
+		g.shared++
+		g.mu.Unlock()
+		<-fl.done
+		return fl.err
+	}
+	if g.calls == nil {
+		g.calls = map[string]*flight{}
+	}
+	fl := &flight{done: make(chan struct{})}
+	g.calls[key] = fl
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(fl.done)
+	}()
+	fl.err = fn()
+	return fl.err
+}
+
+// write saves img through a temporary file so a request never sees a
+// half-written thumbnail
+func write(path string, img image.Image, format string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".thumb-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(tmp, img, &jpeg.Options{Quality: jpegQuality})
+	case "gif":
+		err = gif.Encode(tmp, img, nil)
+	default:
+		err = png.Encode(tmp, img)
+	}
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Remove deletes the thumbnails made from src, like delete_board_thumbnail
+func Remove(src string) error {
+	name := filepath.Base(src)
+	prefix := "thumb-" + strings.TrimSuffix(name, filepath.Ext(name)) + "_"
+	entries, err := os.ReadDir(filepath.Dir(src))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) {
+			if err := os.Remove(filepath.Join(filepath.Dir(src), e.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Cover crops the middle of img to the aspect ratio of size and scales it
+// to exactly size (그누보드 썸네일의 is_crop)
+func Cover(img image.Image, size Size) *image.RGBA {
+	b := img.Bounds()
+	cw, ch := b.Dx(), b.Dy()
+	if cw*size.Height > ch*size.Width {
+		cw = ch * size.Width / size.Height
+	} else {
+		ch = cw * size.Height / size.Width
+	}
+	cw, ch = max(cw, 1), max(ch, 1)
+	origin := b.Min.Add(image.Pt((b.Dx()-cw)/2, (b.Dy()-ch)/2))
+
+	crop := image.NewRGBA(image.Rect(0, 0, cw, ch))
+	draw.Draw(crop, crop.Bounds(), img, origin, draw.Src)
+	return scale(crop, size.Width, size.Height)
+}
+
+// scale resizes src by averaging the source pixels under each target pixel
+// (nearest pixel when enlarging)
+func scale(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max((y+1)*sh/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max((x+1)*sw/width, x0+1)
+			var sum [4]uint64
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					sum[0] += uint64(src.Pix[i])
+					sum[1] += uint64(src.Pix[i+1])
+					sum[2] += uint64(src.Pix[i+2])
+					sum[3] += uint64(src.Pix[i+3])
+					i += 4
+				}
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			o := dst.PixOffset(x, y)
+			for k := range sum {
+				dst.Pix[o+k] = uint8(sum[k] / n)
+			}
+		}
+	}
+	return dst
+}
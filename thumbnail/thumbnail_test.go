@@ -0,0 +1,195 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseSizes(t *testing.T) {
+	sizes, err := ParseSizes(" 202x150, 400x300 ,")
+	if err != nil || len(sizes) != 2 || sizes[0] != (Size{202, 150}) || sizes[1] != (Size{400, 300}) {
+		t.Errorf("ParseSizes = %v, %v", sizes, err)
+	}
+	for _, bad := range []string{"202", "0x10", "10x-1", "axb", "3000x10"} {
+		if _, err := ParseSize(bad); err == nil {
+			t.Errorf("ParseSize(%q) accepted", bad)
+		}
+	}
+}
+
+// 왼쪽 절반은 빨강, 오른쪽 절반은 파랑
+func halves(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= width/2 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCover(t *testing.T) {
+	// 8x2 를 1x1 로: 가운데 2x2 (빨강 1열, 파랑 1열) 의 평균
+	got := Cover(halves(8, 2), Size{1, 1})
+	if got.Bounds().Dx() != 1 || got.RGBAAt(0, 0) != (color.RGBA{127, 0, 127, 255}) {
+		t.Errorf("1x1 = %v", got.RGBAAt(0, 0))
+	}
+
+	// 비율이 같으면 자르지 않고 줄이기만 함
+	got = Cover(halves(40, 20), Size{4, 2})
+	if got.RGBAAt(0, 0) != (color.RGBA{255, 0, 0, 255}) || got.RGBAAt(3, 1) != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("4x2 = %v %v", got.RGBAAt(0, 0), got.RGBAAt(3, 1))
+	}
+
+	// 작은 원본은 늘림
+	if got := Cover(halves(2, 2), Size{10, 6}); got.Bounds() != image.Rect(0, 0, 10, 6) {
+		t.Errorf("enlarged bounds = %v", got.Bounds())
+	}
+}
+
+func TestMake(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "abc_photo.png")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	png.Encode(f, halves(60, 40))
+	f.Close()
+
+	path, err := Make(src, Size{30, 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "thumb-abc_photo_30x30.png") {
+		t.Errorf("path = %s", path)
+	}
+	out, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, format, err := image.DecodeConfig(out)
+	out.Close()
+	if err != nil || format != "png" || cfg.Width != 30 || cfg.Height != 30 {
+		t.Errorf("thumbnail = %+v %s %v", cfg, format, err)
+	}
+
+	// 원본이 그대로면 만들어 둔 파일을 씀
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(src, old, old)
+	made, _ := os.Stat(path)
+	if again, err := Make(src, Size{30, 30}); err != nil || again != path {
+		t.Fatalf("again = %s, %v", again, err)
+	}
+	if cached, _ := os.Stat(path); !cached.ModTime().Equal(made.ModTime()) {
+		t.Error("cached thumbnail was rewritten")
+	}
+
+	if err := Remove(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("thumbnail left after Remove: %v", err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("original removed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "text.png"), []byte("not an image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Make(filepath.Join(dir, "text.png"), Size{30, 30}); err == nil {
+		t.Error("made a thumbnail of a text file")
+	}
+}
+
+func TestMakeTooLarge(t *testing.T) {
+	// IHDR 의 크기만 5000x5000 으로 바꾼 PNG (DecodeConfig 는 헤더만 읽음)
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)))
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 5000)
+	binary.BigEndian.PutUint32(data[20:], 5000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	src := filepath.Join(t.TempDir(), "bomb.png")
+	if err := os.WriteFile(src, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Make(src, Size{30, 30}); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("err = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestFlights(t *testing.T) {
+	var g flights
+	var calls int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fail := errors.New("실패")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs <- g.do("k", func() error {
+			atomic.AddInt32(&calls, 1)
+			close(started)
+			<-release
+			return fail
+		})
+	}()
+	<-started
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- g.do("k", func() error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			})
+		}()
+	}
+	// 기다리는 요청이 모두 붙을 때까지
+	for {
+		g.mu.Lock()
+		shared := g.shared
+		g.mu.Unlock()
+		if shared == 4 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != fail {
+			t.Errorf("err = %v, want shared error", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+
+	// 끝난 뒤에는 다시 실행
+	if err := g.do("k", func() error { return nil }); err != nil {
+		t.Errorf("after = %v", err)
+	}
+}